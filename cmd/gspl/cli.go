@@ -21,6 +21,11 @@ type CLIArgs struct {
 			clifford.Required
 			clifford.Desc `desc:"Path to the linear program file to run"`
 		}
+
+		Sensitivity struct {
+			Value             bool
+			clifford.Clifford `short:"s" long:"sensitivity" desc:"Print objective and RHS ranging"`
+		}
 	}
 
 	Version struct {
@@ -40,3 +40,14 @@ func TestParseArgs_Run(t *testing.T) {
 		t.Fatalf("unexpected file value: %q", args.Run.File.Value)
 	}
 }
+
+func TestParseArgs_RunSensitivity(t *testing.T) {
+	orig := os.Args
+	defer func() { os.Args = orig }()
+
+	os.Args = []string{"gspl", "run", "file.txt", "--sensitivity"}
+	args := ParseArgs()
+	if !args.Run.Sensitivity.Value {
+		t.Fatal("expected sensitivity flag to be set")
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"text/tabwriter"
 
 	"github.com/chriso345/gspl/internal/lang"
 	"github.com/chriso345/gspl/internal/lang/ast"
 	_ "github.com/chriso345/gspl/internal/lang/gmpl"
+	"github.com/chriso345/gspl/lp"
 	"github.com/chriso345/gspl/solver"
 )
 
@@ -32,15 +34,47 @@ func main() {
 		fmt.Printf("Parsed node: %T\n", node)
 		if m, ok := node.(*ast.Module); ok && m.LP != nil {
 			fmt.Println("Found linear program; solving...")
-			sol, err := solver.Solve(m.LP)
+			sol, err := solver.Solve(m.LP, solver.WithSensitivity(args.Run.Sensitivity.Value))
 			if err != nil {
 				exit(1, err)
 			}
 			fmt.Printf("Status: %v\n", sol.Status)
 			fmt.Printf("Objective: %.6f\n", sol.ObjectiveValue)
 			fmt.Printf("Primal: %v\n", sol.PrimalSolution.RawVector().Data)
+			if sol.Sensitivity != nil {
+				printSensitivity(m.LP, sol)
+			}
 		}
 	}
 
 	exit(0, nil)
 }
+
+// printSensitivity writes the allowable decrease and increase of every
+// objective coefficient and constraint RHS.
+func printSensitivity(prog *lp.LinearProgram, sol *solver.Solution) {
+	s := sol.Sensitivity
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "\nObjective ranging:")
+	fmt.Fprintln(w, "Variable\tValue\tReduced cost\tAllowable decrease\tAllowable increase")
+	j := 0
+	for _, v := range prog.Vars {
+		if v.IsSlack {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%.6g\t%.6g\t%.6g\t%.6g\n", v.Name,
+			sol.PrimalSolution.AtVec(j), sol.ReducedCosts.AtVec(j),
+			s.ObjectiveDecrease.AtVec(j), s.ObjectiveIncrease.AtVec(j))
+		j++
+	}
+
+	fmt.Fprintln(w, "\nRHS ranging:")
+	fmt.Fprintln(w, "Constraint\tActivity\tDual value\tAllowable decrease\tAllowable increase")
+	for i := 0; i < s.RHSDecrease.Len(); i++ {
+		fmt.Fprintf(w, "c%d\t%.6g\t%.6g\t%.6g\t%.6g\n", i+1,
+			sol.RowActivity.AtVec(i), sol.DualSolution.AtVec(i),
+			s.RHSDecrease.AtVec(i), s.RHSIncrease.AtVec(i))
+	}
+	w.Flush()
+}
@@ -0,0 +1,462 @@
+package barrier
+
+import (
+	"math"
+	"time"
+
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/matrix"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	// optimalityTolerance is the relative primal infeasibility, dual
+	// infeasibility and duality gap below which a point is optimal.
+	optimalityTolerance = 1e-8
+
+	// stepFraction is the share of the distance to the boundary of the
+	// positive orthant that each step covers.
+	stepFraction = 0.995
+
+	// iterationCap is the number of iterations after which the method is
+	// considered to have stalled. Mehrotra's method rarely needs more than a
+	// few dozen.
+	iterationCap = 200
+
+	// divergenceBound is the magnitude at which a primal or dual value is
+	// taken as a sign that the problem is unbounded or infeasible.
+	divergenceBound = 1e12
+
+	// minimumStep is the step length below which the method has stalled.
+	minimumStep = 1e-10
+
+	// freeRegularisation stands in for the missing bound terms of a free
+	// column, so that the normal equations stay bounded.
+	freeRegularisation = 1e-8
+)
+
+// Solve solves scf with Mehrotra's primal-dual interior point method.
+//
+// On success the status is optimal and scf holds the primal and dual solution
+// and the reduced costs; Basis and AtUpper are left nil, as an interior
+// solution has no basis. A solve stopped by config.MaxIterations or
+// config.Ctx records its last point without duals. If the method stalls, as
+// it does on infeasible and unbounded problems, the status is left as
+// SolverStatusNotSolved so that the caller can classify the problem with the
+// simplex method.
+func Solve(scf *common.StandardComputationalForm, config *common.SolverConfig) error {
+	ip, ok := newInteriorPoint(scf, config.Tolerance)
+	if !ok {
+		*scf.Status = common.SolverStatusInfeasible
+		return nil
+	}
+	start := time.Now()
+	defer func() { scf.Stats.AddInterior(ip.iterations, time.Since(start)) }()
+	if !ip.start() {
+		*scf.Status = common.SolverStatusNotSolved
+		return nil
+	}
+
+	for {
+		r := ip.residuals()
+		if ip.converged(r) {
+			ip.store(scf, common.SolverStatusOptimal)
+			return nil
+		}
+		if stop := ip.stopStatus(config); stop != common.SolverStatusNotSolved {
+			ip.store(scf, stop)
+			return nil
+		}
+		if ip.iterations >= iterationCap || ip.diverged() {
+			*scf.Status = common.SolverStatusNotSolved
+			return nil
+		}
+
+		ip.iterations++
+		if !ip.step(r) {
+			*scf.Status = common.SolverStatusNotSolved
+			return nil
+		}
+	}
+}
+
+// newInteriorPoint sets up the method on scf. It reports false when a column
+// has inconsistent bounds.
+func newInteriorPoint(scf *common.StandardComputationalForm, tol float64) (*interiorPoint, bool) {
+	m, n := scf.Constraints.Dims()
+	ip := &interiorPoint{
+		A:        matrix.AsCSC(scf.Constraints),
+		b:        scf.RHS,
+		c:        scf.Objective,
+		m:        m,
+		n:        n,
+		lower:    make([]float64, n),
+		upper:    make([]float64, n),
+		hasLower: make([]bool, n),
+		hasUpper: make([]bool, n),
+		fixed:    make([]bool, n),
+		x:        make([]float64, n),
+		y:        mat.NewVecDense(m, nil),
+		zl:       make([]float64, n),
+		zu:       make([]float64, n),
+	}
+	for j := range n {
+		lower, upper := scf.Bounds(j)
+		if lower > upper+tol {
+			return nil, false
+		}
+		ip.lower[j], ip.upper[j] = lower, upper
+		if upper-lower <= tol {
+			ip.fixed[j] = true
+			ip.x[j] = lower
+			continue
+		}
+		ip.hasLower[j] = !math.IsInf(lower, -1)
+		ip.hasUpper[j] = !math.IsInf(upper, 1)
+	}
+	return ip, true
+}
+
+// start places the point at the least squares solutions of Ax = b and
+// A^T y = c, moved inside the bounds and made positive in the bound duals.
+func (ip *interiorPoint) start() bool {
+	theta := make([]float64, ip.n)
+	for j := range ip.n {
+		if !ip.fixed[j] {
+			theta[j] = 1
+		}
+	}
+	chol, ok := ip.normalFactor(theta)
+	if !ok {
+		return false
+	}
+
+	// x = A^T (A A^T)^-1 (b - A x_fixed)
+	rhs := mat.VecDenseCopyOf(ip.b)
+	for j := range ip.n {
+		if ip.fixed[j] && ip.x[j] != 0 {
+			matrix.AddScaledCol(rhs, -ip.x[j], ip.A, j)
+		}
+	}
+	w := mat.NewVecDense(ip.m, nil)
+	if err := chol.SolveVecTo(w, rhs); err != nil {
+		return false
+	}
+	for j := range ip.n {
+		if ip.fixed[j] {
+			continue
+		}
+		x := matrix.ColDot(ip.A, j, w)
+		lower, upper := ip.lower[j], ip.upper[j]
+		switch {
+		case ip.hasLower[j] && ip.hasUpper[j]:
+			margin := math.Min(1, (upper-lower)/4)
+			x = math.Min(math.Max(x, lower+margin), upper-margin)
+		case ip.hasLower[j]:
+			x = math.Max(x, lower+1)
+		case ip.hasUpper[j]:
+			x = math.Min(x, upper-1)
+		}
+		ip.x[j] = x
+	}
+
+	// y = (A A^T)^-1 A c
+	rhs.Zero()
+	for j := range ip.n {
+		if !ip.fixed[j] {
+			matrix.AddScaledCol(rhs, ip.c.AtVec(j), ip.A, j)
+		}
+	}
+	if err := chol.SolveVecTo(ip.y, rhs); err != nil {
+		return false
+	}
+	for j := range ip.n {
+		s := ip.c.AtVec(j) - matrix.ColDot(ip.A, j, ip.y)
+		switch {
+		case ip.hasLower[j] && ip.hasUpper[j]:
+			ip.zl[j] = math.Max(s, 0) + 1
+			ip.zu[j] = math.Max(-s, 0) + 1
+		case ip.hasLower[j]:
+			ip.zl[j] = math.Max(s, 1)
+		case ip.hasUpper[j]:
+			ip.zu[j] = math.Max(-s, 1)
+		}
+	}
+	return true
+}
+
+// residuals returns the primal and dual infeasibilities of the current point.
+// The complementarity targets are left for the step to fill in.
+func (ip *interiorPoint) residuals() *residuals {
+	r := &residuals{
+		rb: mat.VecDenseCopyOf(ip.b),
+		rc: make([]float64, ip.n),
+		rl: make([]float64, ip.n),
+		ru: make([]float64, ip.n),
+	}
+	for j := range ip.n {
+		if ip.x[j] != 0 {
+			matrix.AddScaledCol(r.rb, -ip.x[j], ip.A, j)
+		}
+		if !ip.fixed[j] {
+			r.rc[j] = ip.c.AtVec(j) - matrix.ColDot(ip.A, j, ip.y) - ip.zl[j] + ip.zu[j]
+		}
+	}
+	return r
+}
+
+// converged reports whether the relative primal and dual infeasibilities and
+// the relative duality gap are all within optimalityTolerance.
+func (ip *interiorPoint) converged(r *residuals) bool {
+	primal := mat.Norm(r.rb, 2) / (1 + mat.Norm(ip.b, 2))
+	dual := floats.Norm(r.rc, 2) / (1 + mat.Norm(ip.c, 2))
+
+	pobj := ip.primalObjective()
+	gap := math.Abs(pobj-ip.dualObjective()) / (1 + math.Abs(pobj))
+	return primal < optimalityTolerance && dual < optimalityTolerance && gap < optimalityTolerance
+}
+
+func (ip *interiorPoint) primalObjective() float64 {
+	return floats.Dot(ip.c.RawVector().Data, ip.x)
+}
+
+// dualObjective returns b^T y + l^T zl - u^T zu, where a fixed column
+// contributes its reduced cost at its fixed value.
+func (ip *interiorPoint) dualObjective() float64 {
+	obj := mat.Dot(ip.b, ip.y)
+	for j := range ip.n {
+		switch {
+		case ip.fixed[j]:
+			obj += (ip.c.AtVec(j) - matrix.ColDot(ip.A, j, ip.y)) * ip.x[j]
+		default:
+			if ip.hasLower[j] {
+				obj += ip.lower[j] * ip.zl[j]
+			}
+			if ip.hasUpper[j] {
+				obj -= ip.upper[j] * ip.zu[j]
+			}
+		}
+	}
+	return obj
+}
+
+// complementarity returns the average of the products (x-l)*zl and
+// (u-x)*zu over the finite bounds, or zero when there are none.
+func (ip *interiorPoint) complementarity() float64 {
+	sum, count := 0., 0
+	for j := range ip.n {
+		if ip.hasLower[j] {
+			sum += (ip.x[j] - ip.lower[j]) * ip.zl[j]
+			count++
+		}
+		if ip.hasUpper[j] {
+			sum += (ip.upper[j] - ip.x[j]) * ip.zu[j]
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
+
+// stopStatus returns the limit status the solve must stop with before its
+// next iteration, or SolverStatusNotSolved to carry on.
+func (ip *interiorPoint) stopStatus(config *common.SolverConfig) common.SolverStatus {
+	if config.MaxIterations > 0 && ip.iterations >= config.MaxIterations {
+		return common.SolverStatusIterationLimit
+	}
+	return common.StopStatus(config.Ctx)
+}
+
+// diverged reports whether any value of the point has grown beyond
+// divergenceBound.
+func (ip *interiorPoint) diverged() bool {
+	for j := range ip.n {
+		if math.Abs(ip.x[j]) > divergenceBound || ip.zl[j] > divergenceBound || ip.zu[j] > divergenceBound {
+			return true
+		}
+	}
+	for i := range ip.m {
+		if math.Abs(ip.y.AtVec(i)) > divergenceBound {
+			return true
+		}
+	}
+	return false
+}
+
+// step makes one predictor-corrector iteration in Mehrotra's scheme: an
+// affine scaling step towards optimality, whose progress picks the centring
+// parameter, then a second solve with a second order correction. It reports
+// false when the normal equations cannot be solved or the step has become
+// negligible.
+func (ip *interiorPoint) step(r *residuals) bool {
+	theta := make([]float64, ip.n)
+	for j := range ip.n {
+		if ip.fixed[j] {
+			continue
+		}
+		d := freeRegularisation
+		if ip.hasLower[j] {
+			d += ip.zl[j] / (ip.x[j] - ip.lower[j])
+		}
+		if ip.hasUpper[j] {
+			d += ip.zu[j] / (ip.upper[j] - ip.x[j])
+		}
+		theta[j] = 1 / d
+	}
+	chol, ok := ip.normalFactor(theta)
+	if !ok {
+		return false
+	}
+
+	// Predictor: the affine scaling direction aims at zero complementarity
+	for j := range ip.n {
+		if ip.hasLower[j] {
+			r.rl[j] = -(ip.x[j] - ip.lower[j]) * ip.zl[j]
+		}
+		if ip.hasUpper[j] {
+			r.ru[j] = -(ip.upper[j] - ip.x[j]) * ip.zu[j]
+		}
+	}
+	aff, ok := ip.direction(chol, theta, r)
+	if !ok {
+		return false
+	}
+	alphaP, alphaD := ip.stepLengths(aff, 1)
+
+	// The centring parameter follows from how far the affine step gets
+	mu := ip.complementarity()
+	sigma := 0.
+	if mu > 0 {
+		muAff, count := 0., 0
+		for j := range ip.n {
+			if ip.hasLower[j] {
+				muAff += (ip.x[j] - ip.lower[j] + alphaP*aff.dx[j]) * (ip.zl[j] + alphaD*aff.dzl[j])
+				count++
+			}
+			if ip.hasUpper[j] {
+				muAff += (ip.upper[j] - ip.x[j] - alphaP*aff.dx[j]) * (ip.zu[j] + alphaD*aff.dzu[j])
+				count++
+			}
+		}
+		sigma = math.Pow(muAff/float64(count)/mu, 3)
+	}
+
+	// Corrector: centre and correct for the second order term of the
+	// affine step, reusing the factorisation
+	for j := range ip.n {
+		if ip.hasLower[j] {
+			r.rl[j] = sigma*mu - (ip.x[j]-ip.lower[j])*ip.zl[j] - aff.dx[j]*aff.dzl[j]
+		}
+		if ip.hasUpper[j] {
+			r.ru[j] = sigma*mu - (ip.upper[j]-ip.x[j])*ip.zu[j] + aff.dx[j]*aff.dzu[j]
+		}
+	}
+	d, ok := ip.direction(chol, theta, r)
+	if !ok {
+		return false
+	}
+	alphaP, alphaD = ip.stepLengths(d, stepFraction)
+	if alphaP < minimumStep && alphaD < minimumStep {
+		return false
+	}
+
+	for j := range ip.n {
+		ip.x[j] += alphaP * d.dx[j]
+		ip.zl[j] += alphaD * d.dzl[j]
+		ip.zu[j] += alphaD * d.dzu[j]
+	}
+	ip.y.AddScaledVec(ip.y, alphaD, d.dy)
+	return true
+}
+
+// direction solves the Newton system for the residuals r. Eliminating the
+// bound duals leaves dx = Theta (A^T dy - h) with h = rc - rl/(x-l) +
+// ru/(u-x), and substituting into A dx = rb gives the normal equations
+// A Theta A^T dy = rb + A Theta h.
+func (ip *interiorPoint) direction(chol *cholesky, theta []float64, r *residuals) (*direction, bool) {
+	h := make([]float64, ip.n)
+	rhs := mat.VecDenseCopyOf(r.rb)
+	for j := range ip.n {
+		if ip.fixed[j] {
+			continue
+		}
+		h[j] = r.rc[j]
+		if ip.hasLower[j] {
+			h[j] -= r.rl[j] / (ip.x[j] - ip.lower[j])
+		}
+		if ip.hasUpper[j] {
+			h[j] += r.ru[j] / (ip.upper[j] - ip.x[j])
+		}
+		matrix.AddScaledCol(rhs, theta[j]*h[j], ip.A, j)
+	}
+
+	d := &direction{
+		dx:  make([]float64, ip.n),
+		dy:  mat.NewVecDense(ip.m, nil),
+		dzl: make([]float64, ip.n),
+		dzu: make([]float64, ip.n),
+	}
+	if err := chol.SolveVecTo(d.dy, rhs); err != nil {
+		return nil, false
+	}
+	for j := range ip.n {
+		if ip.fixed[j] {
+			continue
+		}
+		d.dx[j] = theta[j] * (matrix.ColDot(ip.A, j, d.dy) - h[j])
+		if ip.hasLower[j] {
+			d.dzl[j] = (r.rl[j] - ip.zl[j]*d.dx[j]) / (ip.x[j] - ip.lower[j])
+		}
+		if ip.hasUpper[j] {
+			d.dzu[j] = (r.ru[j] + ip.zu[j]*d.dx[j]) / (ip.upper[j] - ip.x[j])
+		}
+	}
+	return d, true
+}
+
+// stepLengths returns the primal and dual step lengths along d, each the
+// given fraction of the distance to the boundary and at most one.
+func (ip *interiorPoint) stepLengths(d *direction, fraction float64) (float64, float64) {
+	alphaP, alphaD := 1., 1.
+	for j := range ip.n {
+		if ip.hasLower[j] {
+			if d.dx[j] < 0 {
+				alphaP = math.Min(alphaP, -fraction*(ip.x[j]-ip.lower[j])/d.dx[j])
+			}
+			if d.dzl[j] < 0 {
+				alphaD = math.Min(alphaD, -fraction*ip.zl[j]/d.dzl[j])
+			}
+		}
+		if ip.hasUpper[j] {
+			if d.dx[j] > 0 {
+				alphaP = math.Min(alphaP, fraction*(ip.upper[j]-ip.x[j])/d.dx[j])
+			}
+			if d.dzu[j] < 0 {
+				alphaD = math.Min(alphaD, -fraction*ip.zu[j]/d.dzu[j])
+			}
+		}
+	}
+	return alphaP, alphaD
+}
+
+// store writes the current point to scf with the given status. Only an
+// optimal point carries duals and reduced costs.
+func (ip *interiorPoint) store(scf *common.StandardComputationalForm, status common.SolverStatus) {
+	*scf.Status = status
+	scf.PrimalSolution = mat.NewVecDense(ip.n, append([]float64(nil), ip.x...))
+	*scf.ObjectiveValue = ip.primalObjective()
+	scf.Basis = nil
+	scf.AtUpper = nil
+	if status != common.SolverStatusOptimal {
+		return
+	}
+
+	scf.DualSolution = mat.VecDenseCopyOf(ip.y)
+	scf.ReducedCosts = mat.NewVecDense(ip.n, nil)
+	for j := range ip.n {
+		scf.ReducedCosts.SetVec(j, ip.c.AtVec(j)-matrix.ColDot(ip.A, j, ip.y))
+	}
+}
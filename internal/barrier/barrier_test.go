@@ -0,0 +1,90 @@
+package barrier
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chriso345/gore/assert"
+	"github.com/chriso345/gspl/internal/common"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSolve(t *testing.T) {
+	// Minimize: x1 + 2*x2 + 3*x3
+	// Subject to: x1 + x2 + x3 = 10, x1 - x2 >= 2, x1 <= 7
+	scf := common.NewSCF([]float64{1, 2, 3, 0, 0}, mat.NewDense(3, 5, []float64{
+		1, 1, 1, 0, 0,
+		1, -1, 0, -1, 0,
+		1, 0, 0, 0, 1,
+	}), []float64{10, 2, 7})
+
+	assert.Nil(t, Solve(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, *scf.ObjectiveValue, 13, 1e-6)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(0), 7, 1e-6)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(1), 3, 1e-6)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(2), 0, 1e-6)
+	assert.IsClose(t, scf.DualSolution.AtVec(0), 2, 1e-6)
+	assert.IsClose(t, scf.DualSolution.AtVec(1), 0, 1e-6)
+	assert.IsClose(t, scf.DualSolution.AtVec(2), -1, 1e-6)
+	assert.IsClose(t, scf.ReducedCosts.AtVec(2), 1, 1e-6)
+	assert.True(t, scf.Basis == nil)
+}
+
+func TestSolveBounds(t *testing.T) {
+	// Minimize: -x1 - 2*x2 + x3 + x4
+	// Subject to: x1 + x2 + s = 10, x3 - x4 = -2,
+	//             0 <= x1 <= 3, -1 <= x2 <= 4, x3 free, x4 = 1
+	inf := math.Inf(1)
+	scf := common.NewSCF([]float64{-1, -2, 1, 1, 0}, mat.NewDense(2, 5, []float64{
+		1, 1, 0, 0, 1,
+		0, 0, 1, -1, 0,
+	}), []float64{10, -2})
+	scf.Lower = mat.NewVecDense(5, []float64{0, -1, -inf, 1, 0})
+	scf.Upper = mat.NewVecDense(5, []float64{3, 4, inf, 1, inf})
+
+	assert.Nil(t, Solve(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, *scf.ObjectiveValue, -11, 1e-6)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(0), 3, 1e-6)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(1), 4, 1e-6)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(2), -1, 1e-6)
+	assert.Equal(t, scf.PrimalSolution.AtVec(3), 1.)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(4), 3, 1e-6)
+}
+
+func TestSolveInconsistentBounds(t *testing.T) {
+	scf := common.NewSCF([]float64{1}, mat.NewDense(1, 1, []float64{1}), []float64{1})
+	scf.Lower = mat.NewVecDense(1, []float64{2})
+	scf.Upper = mat.NewVecDense(1, []float64{1})
+
+	assert.Nil(t, Solve(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, *scf.Status, common.SolverStatusInfeasible)
+}
+
+func TestSolveStalls(t *testing.T) {
+	// Infeasible: x1 + x2 = -1 with x >= 0
+	scf := common.NewSCF([]float64{1, 1}, mat.NewDense(1, 2, []float64{1, 1}), []float64{-1})
+	assert.Nil(t, Solve(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, *scf.Status, common.SolverStatusNotSolved)
+
+	// Unbounded: minimize -x1 subject to x1 - x2 = 1
+	scf = common.NewSCF([]float64{-1, 0}, mat.NewDense(1, 2, []float64{1, -1}), []float64{1})
+	assert.Nil(t, Solve(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, *scf.Status, common.SolverStatusNotSolved)
+}
+
+func TestSolveIterationLimit(t *testing.T) {
+	scf := common.NewSCF([]float64{1, 2, 3, 0, 0}, mat.NewDense(3, 5, []float64{
+		1, 1, 1, 0, 0,
+		1, -1, 0, -1, 0,
+		1, 0, 0, 0, 1,
+	}), []float64{10, 2, 7})
+	config := common.DefaultSolverConfig()
+	config.MaxIterations = 1
+
+	assert.Nil(t, Solve(scf, config))
+	assert.Equal(t, *scf.Status, common.SolverStatusIterationLimit)
+	assert.NotNil(t, scf.PrimalSolution)
+	assert.True(t, scf.DualSolution == nil)
+}
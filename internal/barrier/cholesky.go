@@ -0,0 +1,238 @@
+package barrier
+
+import (
+	"container/heap"
+	"math"
+	"slices"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// cholesky is a sparse Cholesky factorisation P M P^T = L L^T of a symmetric
+// positive definite matrix. The ordering and the pattern of L are fixed when
+// it is built, and the values are filled in by factorize, so that a sequence
+// of matrices with one pattern is analysed only once.
+type cholesky struct {
+	m     int
+	perm  []int // Row of M at each position of the ordering
+	iperm []int // Position of each row of M in the ordering
+
+	// L by column over the ordered positions, the diagonal entry first and
+	// the rest in increasing row order
+	colPtr []int
+	rowIdx []int
+	values []float64
+}
+
+// newCholesky analyses the symmetric pattern adj, where adj[i] lists the
+// off-diagonal nonzeros of row i of M. The rows are ordered by minimum degree
+// to limit fill, and the pattern of each column of L is the pattern of M below
+// the diagonal joined with the patterns of its children in the elimination
+// tree.
+func newCholesky(adj [][]int) *cholesky {
+	m := len(adj)
+	ch := &cholesky{m: m, perm: minimumDegree(adj), iperm: make([]int, m)}
+	for k, i := range ch.perm {
+		ch.iperm[i] = k
+	}
+
+	ch.colPtr = make([]int, m+1)
+	children := make([][]int, m)
+	mark := make([]int, m)
+	for i := range mark {
+		mark[i] = -1
+	}
+	var pattern []int
+	for k := range m {
+		pattern = pattern[:0]
+		add := func(i int) {
+			if i > k && mark[i] != k {
+				mark[i] = k
+				pattern = append(pattern, i)
+			}
+		}
+		for _, i := range adj[ch.perm[k]] {
+			add(ch.iperm[i])
+		}
+		for _, c := range children[k] {
+			for _, i := range ch.rowIdx[ch.colPtr[c]+1 : ch.colPtr[c+1]] {
+				add(i)
+			}
+		}
+		slices.Sort(pattern)
+
+		ch.rowIdx = append(ch.rowIdx, k)
+		ch.rowIdx = append(ch.rowIdx, pattern...)
+		ch.colPtr[k+1] = len(ch.rowIdx)
+		if len(pattern) > 0 {
+			parent := pattern[0]
+			children[parent] = append(children[parent], k)
+		}
+	}
+	ch.values = make([]float64, len(ch.rowIdx))
+	return ch
+}
+
+// factorize computes L for the matrix whose column j, at and below the
+// diagonal in the ordering, is added into x by column. It reports false when
+// the matrix is not positive definite.
+//
+// The factorisation is left-looking: column k is formed from column k of M
+// less the earlier columns of L with a nonzero in row k, found through a
+// linked list of the columns waiting on each row.
+func (ch *cholesky) factorize(column func(k int, x []float64)) bool {
+	m := ch.m
+	x := make([]float64, m)
+	head := make([]int, m) // First column waiting on each row, or -1
+	next := make([]int, m) // Next column waiting on the same row
+	pos := make([]int, m)  // Entry of each column at the row it waits on
+	for i := range head {
+		head[i] = -1
+	}
+
+	for k := range m {
+		column(k, x)
+		for j := head[k]; j != -1; {
+			following := next[j]
+			lkj := ch.values[pos[j]]
+			for p := pos[j]; p < ch.colPtr[j+1]; p++ {
+				x[ch.rowIdx[p]] -= ch.values[p] * lkj
+			}
+			if pos[j]++; pos[j] < ch.colPtr[j+1] {
+				i := ch.rowIdx[pos[j]]
+				next[j], head[i] = head[i], j
+			}
+			j = following
+		}
+
+		d := x[k]
+		x[k] = 0
+		if !(d > 0) {
+			for p := ch.colPtr[k] + 1; p < ch.colPtr[k+1]; p++ {
+				x[ch.rowIdx[p]] = 0
+			}
+			return false
+		}
+		lkk := math.Sqrt(d)
+		ch.values[ch.colPtr[k]] = lkk
+		for p := ch.colPtr[k] + 1; p < ch.colPtr[k+1]; p++ {
+			i := ch.rowIdx[p]
+			ch.values[p] = x[i] / lkk
+			x[i] = 0
+		}
+		if pos[k] = ch.colPtr[k] + 1; pos[k] < ch.colPtr[k+1] {
+			i := ch.rowIdx[pos[k]]
+			next[k], head[i] = head[i], k
+		}
+	}
+	return true
+}
+
+// SolveVecTo solves M x = b and stores the result in dst.
+func (ch *cholesky) SolveVecTo(dst *mat.VecDense, b mat.Vector) error {
+	if b.Len() != ch.m || dst.Len() != ch.m {
+		return mat.ErrShape
+	}
+	y := make([]float64, ch.m)
+	for k, i := range ch.perm {
+		y[k] = b.AtVec(i)
+	}
+
+	// L z = P b, then L^T w = z
+	for k := range ch.m {
+		y[k] /= ch.values[ch.colPtr[k]]
+		for p := ch.colPtr[k] + 1; p < ch.colPtr[k+1]; p++ {
+			y[ch.rowIdx[p]] -= ch.values[p] * y[k]
+		}
+	}
+	for k := ch.m - 1; k >= 0; k-- {
+		for p := ch.colPtr[k] + 1; p < ch.colPtr[k+1]; p++ {
+			y[k] -= ch.values[p] * y[ch.rowIdx[p]]
+		}
+		y[k] /= ch.values[ch.colPtr[k]]
+	}
+
+	for k, i := range ch.perm {
+		dst.SetVec(i, y[k])
+	}
+	return nil
+}
+
+// minimumDegree returns an elimination order for the symmetric pattern adj
+// that always eliminates a vertex of least degree in the graph left by the
+// eliminations before it, breaking ties by the lowest index.
+func minimumDegree(adj [][]int) []int {
+	m := len(adj)
+	graph := make([]map[int]bool, m)
+	for i := range m {
+		graph[i] = make(map[int]bool, len(adj[i]))
+		for _, j := range adj[i] {
+			if j != i {
+				graph[i][j] = true
+			}
+		}
+	}
+
+	q := &degreeQueue{}
+	for i := range m {
+		heap.Push(q, degreeEntry{len(graph[i]), i})
+	}
+	order := make([]int, 0, m)
+	eliminated := make([]bool, m)
+	for len(order) < m {
+		e := heap.Pop(q).(degreeEntry)
+		v := e.vertex
+		if eliminated[v] || e.degree != len(graph[v]) {
+			continue // Stale entry
+		}
+		eliminated[v] = true
+		order = append(order, v)
+
+		// The neighbours of v become a clique
+		nbrs := make([]int, 0, len(graph[v]))
+		for u := range graph[v] {
+			nbrs = append(nbrs, u)
+		}
+		for _, u := range nbrs {
+			delete(graph[u], v)
+			for _, w := range nbrs {
+				if w != u {
+					graph[u][w] = true
+				}
+			}
+		}
+		for _, u := range nbrs {
+			heap.Push(q, degreeEntry{len(graph[u]), u})
+		}
+		graph[v] = nil
+	}
+	return order
+}
+
+// degreeEntry is a vertex queued by its degree at the time it was queued.
+type degreeEntry struct {
+	degree, vertex int
+}
+
+// degreeQueue is a min-heap of vertices by degree and then index.
+type degreeQueue []degreeEntry
+
+func (q degreeQueue) Len() int { return len(q) }
+
+func (q degreeQueue) Less(i, j int) bool {
+	if q[i].degree != q[j].degree {
+		return q[i].degree < q[j].degree
+	}
+	return q[i].vertex < q[j].vertex
+}
+
+func (q degreeQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *degreeQueue) Push(x any) { *q = append(*q, x.(degreeEntry)) }
+
+func (q *degreeQueue) Pop() any {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
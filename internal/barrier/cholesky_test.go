@@ -0,0 +1,51 @@
+package barrier
+
+import (
+	"testing"
+
+	"github.com/chriso345/gore/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCholeskyArrow(t *testing.T) {
+	// Row 0 meets every other row, so eliminating it first would fill L in
+	// completely; minimum degree puts it off and L keeps the pattern of M
+	m := 5
+	M := mat.NewSymDense(m, nil)
+	adj := make([][]int, m)
+	for i := range m {
+		M.SetSym(i, i, float64(m+i))
+		if i > 0 {
+			M.SetSym(0, i, 1)
+			adj[0] = append(adj[0], i)
+			adj[i] = append(adj[i], 0)
+		}
+	}
+
+	ch := newCholesky(adj)
+	assert.True(t, ch.perm[0] != 0)
+	assert.Equal(t, len(ch.rowIdx), 2*m-1)
+
+	column := func(k int, x []float64) {
+		i := ch.perm[k]
+		for r := range m {
+			if pos := ch.iperm[r]; pos >= k {
+				x[pos] += M.At(r, i)
+			}
+		}
+	}
+	assert.True(t, ch.factorize(column))
+
+	b := mat.NewVecDense(m, []float64{1, -2, 3, -4, 5})
+	got := mat.NewVecDense(m, nil)
+	assert.Nil(t, ch.SolveVecTo(got, b))
+	var want mat.VecDense
+	assert.Nil(t, want.SolveVec(M, b))
+	for i := range m {
+		assert.IsClose(t, got.AtVec(i), want.AtVec(i), 1e-12)
+	}
+
+	// A matrix that is not positive definite is refused
+	M.SetSym(2, 2, -1)
+	assert.False(t, ch.factorize(column))
+}
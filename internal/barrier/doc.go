@@ -0,0 +1,5 @@
+// Package barrier provides a primal-dual interior point method for solving
+// linear programs in standard computational form.
+//
+// This package is internal and intended for use within the gspl project only.
+package barrier
@@ -0,0 +1,98 @@
+package barrier
+
+import "math"
+
+const (
+	// normalRegularisation is the shift added to the diagonal of the normal
+	// matrix, relative to its largest diagonal entry, so that redundant rows
+	// do not make it singular.
+	normalRegularisation = 1e-12
+
+	// regularisationAttempts is how many times the shift is raised a
+	// hundredfold before the normal matrix is given up as singular.
+	regularisationAttempts = 6
+)
+
+// normalFactor forms the normal matrix A Theta A^T for the diagonal theta and
+// returns its sparse Cholesky factorisation. The pattern is analysed on the
+// first call and reused, since theta is nonzero on the same columns
+// throughout. It reports false when the matrix cannot be factorised even
+// after regularisation.
+func (ip *interiorPoint) normalFactor(theta []float64) (*cholesky, bool) {
+	if ip.normal == nil {
+		ip.analyseNormal()
+	}
+
+	diag := make([]float64, ip.m)
+	largest := 1.
+	for i := range ip.m {
+		for p, j := range ip.rowCols[i] {
+			diag[i] += theta[j] * ip.rowVals[i][p] * ip.rowVals[i][p]
+		}
+		largest = math.Max(largest, diag[i])
+	}
+
+	// Column k of the normal matrix in the ordering, from row perm[k] of A
+	ch := ip.normal
+	shift := normalRegularisation * largest
+	column := func(k int, x []float64) {
+		i := ch.perm[k]
+		for p, j := range ip.rowCols[i] {
+			t := theta[j] * ip.rowVals[i][p]
+			if t == 0 {
+				continue
+			}
+			rows, vals := ip.A.Col(j)
+			for q, r := range rows {
+				if pos := ch.iperm[r]; pos > k {
+					x[pos] += t * vals[q]
+				}
+			}
+		}
+		x[k] += diag[i] + shift
+	}
+	for range regularisationAttempts {
+		if ch.factorize(column) {
+			return ch, true
+		}
+		shift *= 100
+	}
+	return nil, false
+}
+
+// analyseNormal records the rows of A over the columns that take part in the
+// iteration and builds the Cholesky analysis of the pattern of A A^T.
+func (ip *interiorPoint) analyseNormal() {
+	ip.rowCols = make([][]int, ip.m)
+	ip.rowVals = make([][]float64, ip.m)
+	for j := range ip.n {
+		if ip.fixed[j] {
+			continue
+		}
+		rows, vals := ip.A.Col(j)
+		for p, i := range rows {
+			ip.rowCols[i] = append(ip.rowCols[i], j)
+			ip.rowVals[i] = append(ip.rowVals[i], vals[p])
+		}
+	}
+
+	// Rows i and r of A A^T meet wherever they share a column
+	adj := make([][]int, ip.m)
+	mark := make([]int, ip.m)
+	for i := range mark {
+		mark[i] = -1
+	}
+	for i := range ip.m {
+		mark[i] = i
+		for _, j := range ip.rowCols[i] {
+			rows, _ := ip.A.Col(j)
+			for _, r := range rows {
+				if mark[r] != i {
+					mark[r] = i
+					adj[i] = append(adj[i], r)
+				}
+			}
+		}
+	}
+	ip.normal = newCholesky(adj)
+}
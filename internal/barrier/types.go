@@ -0,0 +1,57 @@
+package barrier
+
+import (
+	"github.com/chriso345/gspl/internal/matrix"
+	"gonum.org/v1/gonum/mat"
+)
+
+// interiorPoint is the state of the method on min c^T x subject to Ax = b and
+// l <= x <= u. The bounds are kept by the barrier directly, each finite bound
+// carrying its own dual, so they never become explicit rows.
+type interiorPoint struct {
+	A *matrix.CSC
+	b *mat.VecDense
+	c *mat.VecDense
+
+	m int
+	n int
+
+	lower    []float64
+	upper    []float64
+	hasLower []bool // The column has a finite lower bound
+	hasUpper []bool // The column has a finite upper bound
+	fixed    []bool // The column is fixed and takes no part in the iteration
+
+	// The current point: x with the row duals y and the duals zl >= 0 and
+	// zu >= 0 of the lower and upper bounds, zero where a bound is missing
+	x  []float64
+	y  *mat.VecDense
+	zl []float64
+	zu []float64
+
+	// Rows of A over the columns that are not fixed, and the Cholesky
+	// analysis of A Theta A^T, both built on the first factorisation
+	rowCols [][]int
+	rowVals [][]float64
+	normal  *cholesky
+
+	iterations int
+}
+
+// direction is a Newton step for every part of the point.
+type direction struct {
+	dx  []float64
+	dy  *mat.VecDense
+	dzl []float64
+	dzu []float64
+}
+
+// residuals holds the right-hand side of the Newton system: the primal and
+// dual infeasibilities rb = b - Ax and rc = c - A^T y - zl + zu, and the
+// targets rl and ru for the complementarity products (x-l)*zl and (u-x)*zu.
+type residuals struct {
+	rb *mat.VecDense
+	rc []float64
+	rl []float64
+	ru []float64
+}
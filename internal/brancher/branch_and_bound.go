@@ -1,7 +1,9 @@
 package brancher
 
 import (
-	"fmt"
+	"log/slog"
+	"math"
+	"sync"
 
 	"github.com/chriso345/gspl/internal/common"
 	"github.com/chriso345/gspl/internal/concurrency"
@@ -9,105 +11,303 @@ import (
 	"github.com/chriso345/gspl/internal/simplex"
 )
 
-func branchAndBound(ip *common.IntegerProgram, rootNode *common.Node, config *common.SolverConfig) error {
-	return branchAndBoundParallel(ip, rootNode, config)
+// search is the state of a branch and bound search shared by its workers. It
+// is guarded by the BestMutex of ip.
+type search struct {
+	ip     *common.IntegerProgram
+	config *common.SolverConfig
+
+	open   nodeQueue
+	active map[*openNode]struct{} // Nodes being branched on
+	wake   *sync.Cond             // Signalled when a node is queued or the search ends
+	done   bool
 }
 
-// branchAndBoundParallel runs branch-and-bound in parallel using goroutines and channels.
-func branchAndBoundParallel(ip *common.IntegerProgram, rootNode *common.Node, config *common.SolverConfig) error {
-	nodes, err := branchFunc(rootNode)
-	if err != nil {
-		return errors.New(errors.ErrUnknown, "error in branching function", err)
+// branchAndBound explores the tree below rootNode, whose relaxation has a
+// fractional optimum. Workers repeatedly take the open node favoured by the
+// node selection rule, branch on it and re-optimise each child with the dual
+// simplex. A child is closed when it is infeasible, integer, or its bound
+// cannot beat the incumbent, and queued otherwise. The search ends when no
+// node is left, when the incumbent is within GapSensitivity of the best bound
+// of the open nodes, which leaves SolverStatusGapLimit, or when a limit stops
+// it.
+func branchAndBound(ip *common.IntegerProgram, rootNode *common.Node, config *common.SolverConfig) error {
+	s := &search{
+		ip:     ip,
+		config: config,
+		open:   nodeQueue{selection: config.NodeSelection},
+		active: make(map[*openNode]struct{}),
+		wake:   sync.NewCond(&ip.BestMutex),
 	}
+	s.open.push(rootNode)
 
-	type result struct {
-		node *common.Node
-		err  error
+	var wg sync.WaitGroup
+	for concurrency.TryAcquireGoroutine() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer concurrency.ReleaseGoroutine()
+			s.work()
+		}()
 	}
-	results := make(chan result, len(nodes))
+	s.work()
+	wg.Wait()
+	return nil
+}
 
-	// helper to process a node (can run inline or in goroutine)
-	processNode := func(root *common.Node, node *common.Node) error {
-		node.Depth = root.Depth + 1
-		if config.Debug {
-			fmt.Printf("[DEBUG] Branching to new node at depth %d\n", node.Depth)
-		}
-		err := simplex.Simplex(node.SCF, config)
-		if err != nil {
-			return err
+// work branches on open nodes until the search ends.
+func (s *search) work() {
+	ip := s.ip
+	for {
+		ip.BestMutex.Lock()
+		for s.open.Len() == 0 && len(s.active) > 0 && !s.done {
+			s.wake.Wait()
 		}
-		if *node.SCF.Status != common.SolverStatusOptimal {
-			return nil
+		if s.done || s.open.Len() == 0 {
+			s.done = true
+			ip.BestMutex.Unlock()
+			s.wake.Broadcast()
+			return
 		}
-		node.IsInteger = isIntegerFeasible(node.SCF)
-		if config.Debug {
-			fmt.Printf("[DEBUG] Node Objective: %.4f, IsInteger: %v\n\n", *node.SCF.ObjectiveValue, node.IsInteger)
-			fmt.Printf("[DEBUG] Primal Solution: %v\n", node.SCF.PrimalSolution)
+
+		n := s.open.pop()
+		inc := incumbent(ip)
+		if n.bound >= inc-s.config.Tolerance {
+			// The incumbent improved on the node while it was queued
+			s.prune()
+			ip.BestMutex.Unlock()
+			continue
 		}
-		if node.IsInteger {
-			objVal := *node.SCF.ObjectiveValue
-			// Flip to original sense if this SCF represents a maximisation
-			if node.SCF.IsMaximization {
-				objVal = -objVal
-			}
-			// protect BestObj update
-			ip.BestMutex.Lock()
-			// If no best solution yet, accept this one
-			if ip.BestSolution == nil {
-				ip.BestObj = objVal
-				ip.BestSolution = node.SCF.PrimalSolution
-				if config.Debug {
-					fmt.Printf("[DEBUG] New Best Obj: %.4f\n", ip.BestObj)
-				}
-				ip.BestMutex.Unlock()
-				return nil
-			}
-			// Update depending on minimisation/maximisation
-			if node.SCF.IsMaximization {
-				if objVal > ip.BestObj+config.Tolerance {
-					ip.BestObj = objVal
-					ip.BestSolution = node.SCF.PrimalSolution
-					if config.Debug {
-						fmt.Printf("[DEBUG] New Best Obj: %.4f\n", ip.BestObj)
-					}
-				}
-			} else {
-				if objVal < ip.BestObj-config.Tolerance {
-					ip.BestObj = objVal
-					ip.BestSolution = node.SCF.PrimalSolution
-					if config.Debug {
-						fmt.Printf("[DEBUG] New Best Obj: %.4f\n", ip.BestObj)
-					}
-				}
+		if bound := math.Min(n.bound, s.bound()); common.RelativeGap(inc, bound) <= s.config.GapSensitivity {
+			// Close enough: the remaining nodes are left unexplored, so the
+			// incumbent is not proven optimal
+			ip.Unexplored = math.Min(ip.Unexplored, bound)
+			if !ip.Stopped.Stopped() {
+				ip.Stopped = common.SolverStatusGapLimit
 			}
+			s.done = true
 			ip.BestMutex.Unlock()
-			return nil
+			s.wake.Broadcast()
+			return
 		}
-		// Not integer feasible, branch recursively
-		return branchAndBoundParallel(ip, node, config)
-	}
-
-	for _, node := range nodes {
-		nd := node
-		// try to acquire goroutine slot
-		if concurrency.TryAcquireGoroutine() {
-			go func(n *common.Node) {
-				defer concurrency.ReleaseGoroutine()
-				err := processNode(rootNode, n)
-				results <- result{n, err}
-			}(nd)
-		} else {
-			// run inline
-			err := processNode(rootNode, nd)
-			results <- result{nd, err}
+		s.active[n] = struct{}{}
+		ip.BestMutex.Unlock()
+
+		err := s.branch(n)
+
+		ip.BestMutex.Lock()
+		delete(s.active, n)
+		ip.BestMutex.Unlock()
+		s.wake.Broadcast()
+		if err != nil {
+			s.config.Log().LogAttrs(s.config.Ctx, slog.LevelError, "node solve failed",
+				slog.Int("depth", n.node.Depth),
+				slog.Any("error", err),
+			)
 		}
 	}
+}
+
+// branch splits the open node n into children and solves and settles each.
+func (s *search) branch(n *openNode) error {
+	ip := s.ip
+	children, err := branchFunc(n.node)
+	if err != nil {
+		return errors.New(errors.ErrUnknown, "error in branching function", err)
+	}
+
+	for _, child := range children {
+		child.Depth = n.node.Depth + 1
+		// Stop expanding the tree once the solve is cancelled or out of time
+		if status := common.StopStatus(s.config.Ctx); status != common.SolverStatusNotSolved {
+			s.stop(status)
+			return nil
+		}
+		ip.BestMutex.Lock()
+		done := s.done
+		ip.BestMutex.Unlock()
+		if done {
+			return nil
+		}
 
-	for range nodes {
-		r := <-results
-		if r.err != nil && config.Logging {
-			fmt.Printf("Error in branchAndBoundParallel: %v\n", r.err)
+		// Children only tighten a bound, so the parent's optimal basis stays
+		// dual feasible and the dual simplex re-optimises from it
+		child.SCF.Stats = &common.Stats{}
+		if err := simplex.DualSimplex(child.SCF, s.config); err != nil {
+			return err
 		}
+		ip.BestMutex.Lock()
+		recordNode(ip.SCF.Stats, child)
+		ip.BestMutex.Unlock()
+		notifyNode(ip, child, s.config)
+		logNode(s.config, child)
+
+		if status := *child.SCF.Status; status.Stopped() {
+			s.stop(status)
+			return nil
+		}
+		s.settle(child)
 	}
 	return nil
 }
+
+// settle closes child when it is infeasible or cannot beat the incumbent, makes
+// it the incumbent when it is integer, and queues it otherwise.
+func (s *search) settle(child *common.Node) {
+	ip := s.ip
+	ip.BestMutex.Lock()
+	if *child.SCF.Status != common.SolverStatusOptimal {
+		s.prune()
+		ip.BestMutex.Unlock()
+		return
+	}
+	objVal := *child.SCF.ObjectiveValue
+	if objVal >= incumbent(ip)-s.config.Tolerance {
+		// Nothing below the child can improve on the incumbent
+		s.prune()
+		ip.BestMutex.Unlock()
+		return
+	}
+
+	child.IsInteger = isIntegerFeasible(child.SCF)
+	if !child.IsInteger {
+		s.open.push(child)
+		ip.BestMutex.Unlock()
+		s.wake.Signal()
+		return
+	}
+
+	// BestObj is kept in the original sense of the program
+	if child.SCF.IsMaximization {
+		objVal = -objVal
+	}
+	ip.BestObj = objVal
+	ip.BestSolution = child.SCF.PrimalSolution
+	ip.BestMutex.Unlock()
+	notifyIncumbent(ip, s.config)
+	logIncumbent(ip, child, s.config)
+}
+
+// stop ends the search on a limit. The open nodes and those being branched on
+// are left unexplored, so their bounds limit what the incumbent can claim.
+func (s *search) stop(status common.SolverStatus) {
+	s.ip.BestMutex.Lock()
+	s.ip.Stopped = status
+	s.ip.Unexplored = math.Min(s.ip.Unexplored, s.bound())
+	s.done = true
+	s.ip.BestMutex.Unlock()
+	s.wake.Broadcast()
+}
+
+// bound returns the least bound of the open nodes and those being branched
+// on, or +Inf when there are none. The caller must hold BestMutex.
+func (s *search) bound() float64 {
+	b := s.open.bound()
+	for n := range s.active {
+		b = math.Min(b, n.bound)
+	}
+	return b
+}
+
+// prune counts a node closed without branching. The caller must hold
+// BestMutex.
+func (s *search) prune() {
+	if st := s.ip.SCF.Stats; st != nil {
+		st.NodesPruned++
+	}
+}
+
+// recordNode adds the solve of node to stats, which the caller must hold
+// BestMutex to update.
+func recordNode(stats *common.Stats, node *common.Node) {
+	if stats == nil {
+		return
+	}
+	stats.Add(node.SCF.Stats)
+	stats.Nodes++
+	stats.MaxDepth = max(stats.MaxDepth, node.Depth)
+}
+
+// notifyNode reports the solve of node to the callback of config.
+func notifyNode(ip *common.IntegerProgram, node *common.Node, config *common.SolverConfig) {
+	if config.Callback == nil {
+		return
+	}
+	e := common.Event{
+		Kind:      common.EventNode,
+		Phase:     common.PhaseBranch,
+		Status:    *node.SCF.Status,
+		Depth:     node.Depth,
+		Objective: *node.SCF.ObjectiveValue,
+	}
+	ip.BestMutex.Lock()
+	e.Incumbent = incumbent(ip)
+	if st := ip.SCF.Stats; st != nil {
+		e.Nodes = st.Nodes
+	}
+	ip.BestMutex.Unlock()
+	config.Notify(e)
+}
+
+// notifyIncumbent reports a new incumbent of ip to the callback of config.
+func notifyIncumbent(ip *common.IntegerProgram, config *common.SolverConfig) {
+	if config.Callback == nil {
+		return
+	}
+	e := common.Event{Kind: common.EventIncumbent, Phase: common.PhaseBranch}
+	ip.BestMutex.Lock()
+	e.Incumbent = incumbent(ip)
+	e.Objective = e.Incumbent
+	if st := ip.SCF.Stats; st != nil {
+		e.Nodes = st.Nodes
+	}
+	ip.BestMutex.Unlock()
+	config.Notify(e)
+}
+
+// incumbent returns the objective of the incumbent of ip in minimisation
+// form, or +Inf when there is none. The caller must hold BestMutex.
+func incumbent(ip *common.IntegerProgram) float64 {
+	if ip.BestSolution == nil {
+		return math.Inf(1)
+	}
+	if ip.SCF.IsMaximization {
+		return -ip.BestObj
+	}
+	return ip.BestObj
+}
+
+// logNode records the solve of the relaxation of node at debug level, with
+// its objective in the sense of the program.
+func logNode(config *common.SolverConfig, node *common.Node) {
+	log := config.Log()
+	if !log.Enabled(config.Ctx, slog.LevelDebug) {
+		return
+	}
+	objVal := *node.SCF.ObjectiveValue
+	if node.SCF.IsMaximization {
+		objVal = -objVal
+	}
+	log.LogAttrs(config.Ctx, slog.LevelDebug, "node solved",
+		slog.Int("depth", node.Depth),
+		slog.String("status", node.SCF.Status.String()),
+		slog.Float64("objective", objVal),
+		slog.Bool("integer", *node.SCF.Status == common.SolverStatusOptimal && isIntegerFeasible(node.SCF)),
+	)
+}
+
+// logIncumbent records the new incumbent of ip, found at node, at info level.
+func logIncumbent(ip *common.IntegerProgram, node *common.Node, config *common.SolverConfig) {
+	ip.BestMutex.Lock()
+	objVal := ip.BestObj
+	nodes := 0
+	if st := ip.SCF.Stats; st != nil {
+		nodes = st.Nodes
+	}
+	ip.BestMutex.Unlock()
+	config.Log().LogAttrs(config.Ctx, slog.LevelInfo, "new incumbent",
+		slog.Float64("objective", objVal),
+		slog.Int("depth", node.Depth),
+		slog.Int("nodes", nodes),
+	)
+}
@@ -1,7 +1,7 @@
 package brancher
 
 import (
-	"fmt"
+	"log/slog"
 	"math"
 
 	"github.com/chriso345/gspl/internal/common"
@@ -12,6 +12,8 @@ import (
 func BranchAndBound(ip *common.IntegerProgram, config *common.SolverConfig) error {
 	// Define the strategies to be used in tree traversal
 	defineStrategies(ip)
+	ip.Unexplored = math.Inf(1)
+	defer recordGap(ip)
 
 	// Solve at the root
 	rootNode := &common.Node{
@@ -21,10 +23,15 @@ func BranchAndBound(ip *common.IntegerProgram, config *common.SolverConfig) erro
 		// Depth:    0,
 	}
 
-	err := simplex.Simplex(rootNode.SCF, config)
+	err := simplex.Solve(rootNode.SCF, config)
 	if err != nil {
 		return errors.New(errors.ErrUnknown, "error solving root node", err)
 	}
+	if st := ip.SCF.Stats; st != nil {
+		st.Nodes++
+	}
+	notifyNode(ip, rootNode, config)
+	logNode(config, rootNode)
 
 	// If the root node is not optimal, the IP is infeasible or unbounded
 	if *rootNode.SCF.Status != common.SolverStatusOptimal {
@@ -35,10 +42,6 @@ func BranchAndBound(ip *common.IntegerProgram, config *common.SolverConfig) erro
 	// Check if the root solution is integer feasible
 	rootNode.IsInteger = isIntegerFeasible(rootNode.SCF)
 
-	if config.Logging {
-		fmt.Printf("[DEBUG] Primal Solution: %v\n", rootNode.SCF.PrimalSolution)
-	}
-
 	if rootNode.IsInteger {
 		// BestObj is stored in the original problem sense. If the SCF indicates
 		// the original problem was a maximisation, flip the sign (Simplex returns
@@ -50,15 +53,30 @@ func BranchAndBound(ip *common.IntegerProgram, config *common.SolverConfig) erro
 		}
 		ip.BestSolution = rootNode.SCF.PrimalSolution
 		*ip.SCF.Status = common.SolverStatusOptimal
+		notifyIncumbent(ip, config)
+		logIncumbent(ip, rootNode, config)
 		return nil
 	}
 
 	err = branchAndBound(ip, rootNode, config)
 
-	// Set final SCF status depending on whether a best solution was found
-	if ip.BestSolution != nil {
+	// Set final SCF status depending on whether a best solution was found. A
+	// search cut short by a limit or the gap sensitivity keeps the incumbent
+	// but proves nothing, so only a search that closed the gap is optimal.
+	switch {
+	case ip.Stopped.Stopped():
+		*ip.SCF.Status = ip.Stopped
+		level := slog.LevelWarn
+		if ip.Stopped == common.SolverStatusGapLimit {
+			level = slog.LevelInfo
+		}
+		config.Log().LogAttrs(config.Ctx, level, "branch and bound stopped early",
+			slog.String("status", ip.Stopped.String()),
+			slog.Float64("gap", gap(ip)),
+		)
+	case ip.BestSolution != nil:
 		*ip.SCF.Status = common.SolverStatusOptimal
-	} else {
+	default:
 		*ip.SCF.Status = common.SolverStatusInfeasible
 	}
 
@@ -67,6 +85,22 @@ func BranchAndBound(ip *common.IntegerProgram, config *common.SolverConfig) erro
 	return err
 }
 
+// recordGap sets the final gap of ip in its statistics: zero once the search
+// is complete, the gap to the best unexplored node when GapSensitivity or a
+// limit stopped it, and +Inf without an incumbent.
+func recordGap(ip *common.IntegerProgram) {
+	if st := ip.SCF.Stats; st != nil {
+		st.Gap = gap(ip)
+	}
+}
+
+// gap returns the relative gap between the incumbent of ip and the best bound
+// on what its unexplored nodes might still hold.
+func gap(ip *common.IntegerProgram) float64 {
+	inc := incumbent(ip)
+	return common.RelativeGap(inc, math.Min(inc, ip.Unexplored))
+}
+
 // isIntegerFeasible checks if a solution is currently integer feasible
 func isIntegerFeasible(scf *common.StandardComputationalForm) bool {
 	sol := scf.PrimalSolution
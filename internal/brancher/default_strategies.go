@@ -1,39 +1,44 @@
 package brancher
 
 import (
+	"math"
+
 	"github.com/chriso345/gspl/internal/common"
 	"github.com/chriso345/gspl/internal/errors"
 )
 
 // DefaultBranch represents the default branching strategy.
 //
-// This branches on the first variable found that is not integer in the current node
+// This branches on the first variable found that is not integer in the current node,
+// tightening its upper bound to floor(x) in one child and its lower bound to ceil(x)
+// in the other.
 func DefaultBranch(node *common.Node) ([]*common.Node, error) {
-	down := &common.Node{
-		SCF: node.SCF.Copy(),
-	}
-	up := &common.Node{
-		SCF: node.SCF.Copy(),
-	}
-
 	// Get the branching variable
 	branchingVarIndex := -1
 	for i := 0; i < node.SCF.PrimalSolution.Len(); i++ {
 		val := node.SCF.PrimalSolution.AtVec(i)
-		if val != float64(int(val)) {
+		if val != math.Floor(val) {
 			branchingVarIndex = i
 			break
 		}
 	}
 
-	// Add constraints to respective child nodes (added as <= constraints)
-	down.SCF.AddBranch(branchingVarIndex, float64(int(node.SCF.PrimalSolution.AtVec(branchingVarIndex))), 1)
-	up.SCF.AddBranch(branchingVarIndex, float64(int(node.SCF.PrimalSolution.AtVec(branchingVarIndex))+1), 2)
-
 	if branchingVarIndex == -1 {
 		return nil, errors.New(errors.ErrInfeasible, "no branching variable found; node is already integer feasible", nil)
 	}
 
+	down := &common.Node{
+		SCF: node.SCF.Copy(),
+	}
+	up := &common.Node{
+		SCF: node.SCF.Copy(),
+	}
+
+	val := node.SCF.PrimalSolution.AtVec(branchingVarIndex)
+	lower, upper := node.SCF.Bounds(branchingVarIndex)
+	down.SCF.SetBounds(branchingVarIndex, lower, math.Floor(val))
+	up.SCF.SetBounds(branchingVarIndex, math.Ceil(val), upper)
+
 	return []*common.Node{up, down}, nil
 }
 
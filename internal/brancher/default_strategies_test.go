@@ -1,6 +1,7 @@
 package brancher
 
 import (
+	"math"
 	"testing"
 
 	"github.com/chriso345/gore/assert"
@@ -27,18 +28,32 @@ func TestDefaultBranch_HappyPath(t *testing.T) {
 	up := children[0]
 	down := children[1]
 
-	r := down.SCF.Constraints.RawMatrix().Rows - 1
-	val := down.SCF.Constraints.At(r, 1)
-	assert.Equal(t, val, 1.0)
-	rhs := down.SCF.RHS.AtVec(r)
-	assert.Equal(t, rhs, 2.0)
+	// Down child caps x1 at floor(2.5), up child raises it to ceil(2.5)
+	lower, upper := down.SCF.Bounds(1)
+	assert.Equal(t, lower, 0.0)
+	assert.Equal(t, upper, 2.0)
 
-	// Check up (dir 2) has a -1 at column 1 and RHS -3 (-(int+1))
-	r2 := up.SCF.Constraints.RawMatrix().Rows - 1
-	val2 := up.SCF.Constraints.At(r2, 1)
-	assert.Equal(t, val2, -1.0)
-	rhs2 := up.SCF.RHS.AtVec(r2)
-	assert.Equal(t, rhs2, -3.0)
+	lower, upper = up.SCF.Bounds(1)
+	assert.Equal(t, lower, 3.0)
+	assert.True(t, math.IsInf(upper, 1))
+
+	// No rows are appended and the parent is untouched
+	downRows, _ := down.SCF.Constraints.Dims()
+	upRows, _ := up.SCF.Constraints.Dims()
+	assert.Equal(t, downRows, 1)
+	assert.Equal(t, upRows, 1)
+	assert.True(t, node.SCF.Lower == nil)
+}
+
+func TestDefaultBranch_IntegerNode(t *testing.T) {
+	scf := &common.StandardComputationalForm{
+		PrimalSolution: mat.NewVecDense(2, []float64{1.0, 2.0}),
+		Constraints:    mat.NewDense(1, 2, []float64{0, 0}),
+		RHS:            mat.NewVecDense(1, []float64{0}),
+		Objective:      mat.NewVecDense(2, []float64{0, 0}),
+	}
+	_, err := DefaultBranch(&common.Node{SCF: scf})
+	assert.NotNil(t, err)
 }
 
 func TestDefineStrategies_SetsDefaultsOrUsesProvided(t *testing.T) {
@@ -1,5 +1,11 @@
 // Package brancher provides the branch-and-bound algorithm for solving
 // pure integer linear programming problems.
 //
+// Solved nodes whose relaxation is fractional wait in a queue ordered by the
+// node selection rule (depth-first, best-first or best-estimate), and workers
+// branch on the node at its head. A node is pruned when it is infeasible or its
+// bound cannot beat the incumbent, and the search stops early once the
+// incumbent is within the gap sensitivity of the best open bound.
+//
 // This package is internal and intended for use within the gspl project only.
 package brancher
@@ -0,0 +1,106 @@
+package brancher
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/chriso345/gspl/internal/common"
+)
+
+// openNode is a node whose relaxation has been solved to a fractional optimum
+// and that waits in the queue to be branched on. Its bound and estimate are in
+// minimisation form.
+type openNode struct {
+	node     *common.Node
+	bound    float64 // Relaxation objective, a bound on every solution below the node
+	estimate float64 // Estimated objective of the best integer solution below the node
+	seq      int     // Order of arrival, so that ties go to the newest node
+}
+
+// nodeQueue holds the open nodes of a branch and bound search, ordered by the
+// node selection rule so that the node to branch on next is at the top.
+type nodeQueue struct {
+	selection common.NodeSelection
+	nodes     []*openNode
+	pushed    int
+}
+
+func (q *nodeQueue) Len() int { return len(q.nodes) }
+
+func (q *nodeQueue) Less(i, j int) bool {
+	a, b := q.nodes[i], q.nodes[j]
+	switch q.selection {
+	case common.NodeDepthFirst:
+		if a.node.Depth != b.node.Depth {
+			return a.node.Depth > b.node.Depth
+		}
+	case common.NodeBestEstimate:
+		if a.estimate != b.estimate {
+			return a.estimate < b.estimate
+		}
+	}
+	if a.bound != b.bound {
+		return a.bound < b.bound
+	}
+	if a.node.Depth != b.node.Depth {
+		return a.node.Depth > b.node.Depth
+	}
+	return a.seq > b.seq
+}
+
+func (q *nodeQueue) Swap(i, j int) { q.nodes[i], q.nodes[j] = q.nodes[j], q.nodes[i] }
+
+func (q *nodeQueue) Push(x any) {
+	n := x.(*openNode)
+	n.seq = q.pushed
+	q.pushed++
+	q.nodes = append(q.nodes, n)
+}
+
+func (q *nodeQueue) Pop() any {
+	last := len(q.nodes) - 1
+	n := q.nodes[last]
+	q.nodes[last] = nil
+	q.nodes = q.nodes[:last]
+	return n
+}
+
+// push adds node, whose relaxation is solved, to the queue.
+func (q *nodeQueue) push(node *common.Node) {
+	bound := *node.SCF.ObjectiveValue
+	heap.Push(q, &openNode{node: node, bound: bound, estimate: estimate(node.SCF, bound)})
+}
+
+// pop removes and returns the node to branch on next.
+func (q *nodeQueue) pop() *openNode {
+	return heap.Pop(q).(*openNode)
+}
+
+// bound returns the least bound of the queued nodes, or +Inf when it is empty.
+func (q *nodeQueue) bound() float64 {
+	if q.selection == common.NodeBestFirst && len(q.nodes) > 0 {
+		return q.nodes[0].bound
+	}
+	b := math.Inf(1)
+	for _, n := range q.nodes {
+		b = math.Min(b, n.bound)
+	}
+	return b
+}
+
+// estimate returns the objective of the best integer solution expected below
+// a node with the relaxed solution of scf and objective bound. Each
+// fractional variable is rounded the cheaper way, at a cost of its objective
+// coefficient per unit of rounding.
+func estimate(scf *common.StandardComputationalForm, bound float64) float64 {
+	est := bound
+	x := scf.PrimalSolution
+	for j := 0; j < x.Len(); j++ {
+		if scf.SlackIndices[j] != -1 {
+			continue
+		}
+		f := x.AtVec(j) - math.Floor(x.AtVec(j))
+		est += math.Min(f, 1-f) * math.Abs(scf.Objective.AtVec(j))
+	}
+	return est
+}
@@ -0,0 +1,67 @@
+package brancher
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chriso345/gore/assert"
+	"github.com/chriso345/gspl/internal/common"
+	"gonum.org/v1/gonum/mat"
+)
+
+// newQueueNode returns a solved node at depth with objective obj and a
+// relaxed solution of x, whose objective coefficients are all one.
+func newQueueNode(depth int, obj float64, x ...float64) *common.Node {
+	scf := newTestSCF(x)
+	*scf.ObjectiveValue = obj
+	scf.Objective = mat.NewVecDense(len(x), nil)
+	for i := range scf.SlackIndices {
+		scf.SlackIndices[i] = -1
+		scf.Objective.SetVec(i, 1)
+	}
+	return &common.Node{SCF: scf, Depth: depth}
+}
+
+func TestNodeQueueSelection(t *testing.T) {
+	// Shallow with the best bound, deep with the worst, and one between
+	// whose solution is nearly integer
+	shallow := newQueueNode(1, 1, 0.5, 0.5)
+	deep := newQueueNode(3, 3, 0.5, 0.5)
+	near := newQueueNode(2, 2, 0.9, 0.1)
+
+	order := func(selection common.NodeSelection) []*common.Node {
+		q := &nodeQueue{selection: selection}
+		for _, n := range []*common.Node{shallow, deep, near} {
+			q.push(n)
+		}
+		assert.Equal(t, q.bound(), 1.)
+		var nodes []*common.Node
+		for q.Len() > 0 {
+			nodes = append(nodes, q.pop().node)
+		}
+		return nodes
+	}
+
+	best := order(common.NodeBestFirst)
+	assert.True(t, best[0] == shallow && best[1] == near && best[2] == deep)
+
+	depth := order(common.NodeDepthFirst)
+	assert.True(t, depth[0] == deep && depth[1] == near && depth[2] == shallow)
+
+	// The estimates are 2, 4 and 2.2, so the nearly integer node moves ahead
+	// of the deep one
+	est := order(common.NodeBestEstimate)
+	assert.True(t, est[0] == shallow && est[1] == near && est[2] == deep)
+
+	q := &nodeQueue{}
+	assert.True(t, math.IsInf(q.bound(), 1))
+}
+
+func TestEstimate(t *testing.T) {
+	node := newQueueNode(0, 5, 1, 2.25, 3.5)
+	assert.IsClose(t, estimate(node.SCF, 5), 5.75, 1e-12)
+
+	// Slack columns need not be integer
+	node.SCF.SlackIndices[2] = 0
+	assert.IsClose(t, estimate(node.SCF, 5), 5.25, 1e-12)
+}
@@ -0,0 +1,84 @@
+package common
+
+// EventKind identifies what happened when a Callback is invoked
+type EventKind int
+
+const (
+	EventIteration EventKind = iota // A number of simplex pivots were made
+	EventPhase                      // A simplex phase began
+	EventIncumbent                  // Branch and bound found a better integer solution
+	EventNode                       // Branch and bound solved the relaxation of a node
+)
+
+// String returns the string representation of the EventKind
+func (k EventKind) String() string {
+	switch k {
+	case EventIteration:
+		return "Iteration"
+	case EventPhase:
+		return "Phase"
+	case EventIncumbent:
+		return "Incumbent"
+	case EventNode:
+		return "Node"
+	default:
+		return "Unknown"
+	}
+}
+
+// Phase identifies the stage of a solve an Event comes from
+type Phase int
+
+const (
+	PhaseOne    Phase = iota // Phase 1 of the primal simplex, towards a feasible basis
+	PhaseTwo                 // Phase 2 of the primal simplex, towards an optimal basis
+	PhaseDual                // Dual simplex
+	PhaseBranch              // Branch and bound
+)
+
+// String returns the string representation of the Phase
+func (p Phase) String() string {
+	switch p {
+	case PhaseOne:
+		return "Phase 1"
+	case PhaseTwo:
+		return "Phase 2"
+	case PhaseDual:
+		return "Dual"
+	case PhaseBranch:
+		return "Branch and Bound"
+	default:
+		return "Unknown"
+	}
+}
+
+// Event describes the progress of a solve to a Callback. Objective values are
+// in minimisation form until Solve converts them to the sense of the program.
+type Event struct {
+	Kind  EventKind
+	Phase Phase
+
+	// Simplex pivots of the current LP solve so far, and the objective of its
+	// current point. In Phase 1 the objective is the sum of infeasibilities.
+	Iterations int
+	Objective  float64
+
+	// Branch and bound: the status and depth of the node just solved, the
+	// number of nodes solved so far and the objective of the incumbent,
+	// which is infinite while there is none
+	Status    SolverStatus
+	Depth     int
+	Nodes     int
+	Incumbent float64
+}
+
+// Callback receives the Events of a solve. Returning an error stops the solve.
+type Callback func(Event) error
+
+// Notify passes e to the callback of config, if any. The error it returns is
+// left to Solve, which stops the solve through its context.
+func (cfg *SolverConfig) Notify(e Event) {
+	if cfg.Callback != nil {
+		_ = cfg.Callback(e)
+	}
+}
@@ -0,0 +1,38 @@
+package common
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chriso345/gore/assert"
+)
+
+func TestEventKindString(t *testing.T) {
+	assert.Equal(t, EventIteration.String(), "Iteration")
+	assert.Equal(t, EventPhase.String(), "Phase")
+	assert.Equal(t, EventIncumbent.String(), "Incumbent")
+	assert.Equal(t, EventNode.String(), "Node")
+	assert.Equal(t, EventKind(999).String(), "Unknown")
+}
+
+func TestPhaseString(t *testing.T) {
+	assert.Equal(t, PhaseOne.String(), "Phase 1")
+	assert.Equal(t, PhaseTwo.String(), "Phase 2")
+	assert.Equal(t, PhaseDual.String(), "Dual")
+	assert.Equal(t, PhaseBranch.String(), "Branch and Bound")
+	assert.Equal(t, Phase(999).String(), "Unknown")
+}
+
+func TestNotify(t *testing.T) {
+	cfg := DefaultSolverConfig()
+	cfg.Notify(Event{Kind: EventNode}) // No callback is a no-op
+
+	var got []Event
+	cfg.Callback = func(e Event) error {
+		got = append(got, e)
+		return errors.New("stop")
+	}
+	cfg.Notify(Event{Kind: EventNode, Depth: 2})
+	assert.Equal(t, len(got), 1)
+	assert.Equal(t, got[0].Depth, 2)
+}
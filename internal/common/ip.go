@@ -17,6 +17,16 @@ type IntegerProgram struct {
 	// Mutex to protect BestObj and BestSolution updates across goroutines
 	BestMutex sync.Mutex
 
+	// Stopped is the limit status of a node solve that cut the search short,
+	// if any; it becomes the final status. Protected by BestMutex.
+	Stopped SolverStatus
+
+	// Unexplored is the least relaxation objective, in minimisation form, of
+	// the nodes a limit or the gap sensitivity left unexplored, or +Inf when
+	// there are none.
+	// Protected by BestMutex.
+	Unexplored float64
+
 	// User-supplied strategy functions
 	Branch    BranchFunc
 	Heuristic HeuristicFunc
@@ -0,0 +1,27 @@
+package common
+
+import (
+	"log/slog"
+	"os"
+)
+
+// discard is the logger of a solve that asked for no logging
+var discard = slog.New(slog.DiscardHandler)
+
+// Log returns the logger of cfg. Without one, Logging writes text records to
+// standard error, including debug records when Debug is also set, and records
+// are otherwise discarded.
+func (cfg *SolverConfig) Log() *slog.Logger {
+	switch {
+	case cfg.Logger != nil:
+		return cfg.Logger
+	case cfg.Logging || cfg.Debug:
+		level := slog.LevelInfo
+		if cfg.Debug {
+			level = slog.LevelDebug
+		}
+		return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	default:
+		return discard
+	}
+}
@@ -1,19 +1,44 @@
 package common
 
 import (
+	"math"
+
+	"github.com/chriso345/gspl/internal/matrix"
 	"gonum.org/v1/gonum/mat"
 )
 
 // StandardComputationalForm represents a linear programming problem in standard form.
 type StandardComputationalForm struct {
 	Objective   *mat.VecDense // c
-	Constraints *mat.Dense    // A
+	Constraints mat.Matrix    // A, usually a sparse *matrix.CSC
 	RHS         *mat.VecDense // b
 
+	// Column bounds l <= x <= u. A nil vector means every column uses the
+	// default bound (0 below, +Inf above).
+	Lower *mat.VecDense // l
+	Upper *mat.VecDense // u
+
 	PrimalSolution *mat.VecDense // x*
+	DualSolution   *mat.VecDense // y*, one entry per row of A
+	ReducedCosts   *mat.VecDense // c - A^T y, one entry per column of A
+
+	// Certificates of an infeasible or unbounded solve. FarkasRay is a y, one
+	// entry per row of A, with y^T b > y^T A x for every x within the bounds.
+	// UnboundedRay is a d, one entry per column of A, with Ad = 0 and
+	// c^T d < 0 that no bound stops.
+	FarkasRay    *mat.VecDense
+	UnboundedRay *mat.VecDense
+
+	// Final basis of an optimal solve. Basis holds the basic column of each
+	// row, where an index >= the number of columns of A is the artificial
+	// column of row index-n. AtUpper marks nonbasic columns at their upper bound.
+	// When set before a solve, the simplex methods start from this basis.
+	Basis   []int
+	AtUpper []bool
 
 	ObjectiveValue *float64
 	Status         *SolverStatus // Optimal, Infeasible, Unbounded, etc.
+	Stats          *Stats        // Work of the solves of this form; nil records nothing
 	SlackIndices   []int         // Indices of slack variables in the solution
 	NumPrimals     int           // Number of primal variables (non-slack)
 
@@ -24,7 +49,24 @@ type StandardComputationalForm struct {
 	IsMaximization bool
 }
 
-// Copy creates a deep copy of the SCF
+// NewSCF returns the unsolved form of min c^T x subject to Ax = b and x >= 0,
+// holding A in sparse form. It suits small problems built by hand, as in
+// tests; bounds and any other fields are set on the result.
+func NewSCF(c []float64, A mat.Matrix, b []float64) *StandardComputationalForm {
+	objVal := 0.
+	status := SolverStatusNotSolved
+	return &StandardComputationalForm{
+		Objective:      mat.NewVecDense(len(c), c),
+		Constraints:    matrix.CSCFromMatrix(A),
+		RHS:            mat.NewVecDense(len(b), b),
+		ObjectiveValue: &objVal,
+		Status:         &status,
+	}
+}
+
+// Copy creates a copy of the SCF for a branch-and-bound child. Branching only
+// changes bounds, so the constraint matrix is shared rather than copied; it is
+// never modified in place, and AddBranch gives the copy a matrix of its own.
 func (scf *StandardComputationalForm) Copy() *StandardComputationalForm {
 	// Deep-copy pointer fields to avoid sharing mutable state between SCFs
 	var objValPtr *float64
@@ -43,11 +85,35 @@ func (scf *StandardComputationalForm) Copy() *StandardComputationalForm {
 	slackCopy := make([]int, len(scf.SlackIndices))
 	copy(slackCopy, scf.SlackIndices)
 
+	var lower, upper *mat.VecDense
+	if scf.Lower != nil {
+		lower = mat.VecDenseCopyOf(scf.Lower)
+	}
+	if scf.Upper != nil {
+		upper = mat.VecDenseCopyOf(scf.Upper)
+	}
+
+	// Children of a branch-and-bound node warm start from the parent's basis
+	var basis []int
+	var atUpper []bool
+	if scf.Basis != nil {
+		basis = make([]int, len(scf.Basis))
+		copy(basis, scf.Basis)
+	}
+	if scf.AtUpper != nil {
+		atUpper = make([]bool, len(scf.AtUpper))
+		copy(atUpper, scf.AtUpper)
+	}
+
 	return &StandardComputationalForm{
 		Objective:      mat.VecDenseCopyOf(scf.Objective),
-		Constraints:    mat.DenseCopyOf(scf.Constraints),
+		Constraints:    scf.Constraints,
 		RHS:            mat.VecDenseCopyOf(scf.RHS),
+		Lower:          lower,
+		Upper:          upper,
 		PrimalSolution: mat.VecDenseCopyOf(scf.PrimalSolution),
+		Basis:          basis,
+		AtUpper:        atUpper,
 		ObjectiveValue: objValPtr,
 		Status:         statusPtr,
 		SlackIndices:   slackCopy,
@@ -56,36 +122,52 @@ func (scf *StandardComputationalForm) Copy() *StandardComputationalForm {
 	}
 }
 
+// Bounds returns the lower and upper bound of column j.
+func (scf *StandardComputationalForm) Bounds(j int) (lower, upper float64) {
+	lower, upper = 0, math.Inf(1)
+	if scf.Lower != nil {
+		lower = scf.Lower.AtVec(j)
+	}
+	if scf.Upper != nil {
+		upper = scf.Upper.AtVec(j)
+	}
+	return lower, upper
+}
+
+// SetBounds sets the bounds of column j, allocating the bound vectors with
+// their defaults on first use.
+func (scf *StandardComputationalForm) SetBounds(j int, lower, upper float64) {
+	_, n := scf.Constraints.Dims()
+	if scf.Lower == nil {
+		scf.Lower = mat.NewVecDense(n, nil)
+	}
+	if scf.Upper == nil {
+		scf.Upper = mat.NewVecDense(n, nil)
+		for i := range n {
+			scf.Upper.SetVec(i, math.Inf(1))
+		}
+	}
+	scf.Lower.SetVec(j, lower)
+	scf.Upper.SetVec(j, upper)
+}
+
 // AddBranch adds a new constraint to the SCF
 func (scf *StandardComputationalForm) AddBranch(idx int, rhs float64, dir int) {
 	numRows, numCols := scf.Constraints.Dims()
-	newConstraints := mat.NewDense(numRows+1, numCols, nil)
 	newRHS := mat.NewVecDense(numRows+1, nil)
 	for i := 0; i < numRows; i++ {
-		for j := 0; j < numCols; j++ {
-			newConstraints.Set(i, j, scf.Constraints.At(i, j))
-		}
 		newRHS.SetVec(i, scf.RHS.AtVec(i))
 	}
 
-	for j := 0; j < numCols; j++ {
-		if j == idx {
-			switch dir {
-			case 1:
-				newConstraints.Set(numRows, j, 1)
-			case 2:
-				newConstraints.Set(numRows, j, -1)
-			}
-		} else {
-			newConstraints.Set(numRows, j, 0)
-		}
-	}
+	row := make([]float64, numCols)
 	switch dir {
 	case 1:
+		row[idx] = 1
 		newRHS.SetVec(numRows, rhs)
 	case 2:
+		row[idx] = -1
 		newRHS.SetVec(numRows, -rhs)
 	}
-	scf.Constraints = newConstraints
+	scf.Constraints = matrix.CSCAppendRow(matrix.AsCSC(scf.Constraints), row)
 	scf.RHS = newRHS
 }
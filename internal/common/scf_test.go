@@ -1,12 +1,23 @@
 package common
 
 import (
+	"math"
 	"testing"
 
 	"github.com/chriso345/gore/assert"
+	"github.com/chriso345/gspl/internal/matrix"
 	"gonum.org/v1/gonum/mat"
 )
 
+func TestNewSCF(t *testing.T) {
+	scf := NewSCF([]float64{1, 2}, mat.NewDense(1, 2, []float64{3, 0}), []float64{5})
+	assert.Equal(t, *scf.Status, SolverStatusNotSolved)
+	assert.Equal(t, *scf.ObjectiveValue, 0.)
+	assert.Equal(t, scf.Constraints.At(0, 0), 3.)
+	assert.Equal(t, scf.Constraints.(*matrix.CSC).NNZ(), 1)
+	assert.True(t, scf.Lower == nil && scf.Upper == nil)
+}
+
 func TestSCFCopy(t *testing.T) {
 	obj := mat.NewVecDense(2, []float64{1, 2})
 	constr := mat.NewDense(1, 2, []float64{3, 4})
@@ -29,13 +40,20 @@ func TestSCFCopy(t *testing.T) {
 	copySCF := scf.Copy()
 	// Ensure dimensions and values match
 	assert.Equal(t, copySCF.Objective.Len(), 2)
-	assert.Equal(t, copySCF.Constraints.RawMatrix().Rows, 1)
+	rows, _ := copySCF.Constraints.Dims()
+	assert.Equal(t, rows, 1)
 	assert.Equal(t, copySCF.RHS.Len(), 1)
 	assert.Equal(t, *copySCF.ObjectiveValue, objVal)
 	assert.Equal(t, *copySCF.Status, status)
 	// Ensure deep copy: modifying original doesn't affect copy
 	obj.SetVec(0, 999)
 	assert.Equal(t, copySCF.Objective.AtVec(0), 1.0)
+
+	// The constraint matrix is shared, and a branch row replaces it
+	assert.True(t, copySCF.Constraints == scf.Constraints)
+	copySCF.AddBranch(0, 1, 1)
+	rows, _ = scf.Constraints.Dims()
+	assert.Equal(t, rows, 1)
 }
 
 func TestSCFAddBranch(t *testing.T) {
@@ -60,3 +78,32 @@ func TestSCFAddBranch(t *testing.T) {
 	assert.Equal(t, scf.Constraints.At(2, 0), -1.0)
 	assert.Equal(t, scf.RHS.AtVec(2), -3.0)
 }
+
+func TestSCFBounds(t *testing.T) {
+	scf := &StandardComputationalForm{
+		Objective:   mat.NewVecDense(2, []float64{1, 2}),
+		Constraints: mat.NewDense(1, 2, []float64{3, 4}),
+		RHS:         mat.NewVecDense(1, []float64{5}),
+	}
+
+	lower, upper := scf.Bounds(1)
+	assert.Equal(t, lower, 0.0)
+	assert.True(t, math.IsInf(upper, 1))
+
+	scf.SetBounds(1, -2, 7)
+	lower, upper = scf.Bounds(1)
+	assert.Equal(t, lower, -2.0)
+	assert.Equal(t, upper, 7.0)
+
+	// Untouched columns keep their defaults
+	lower, upper = scf.Bounds(0)
+	assert.Equal(t, lower, 0.0)
+	assert.True(t, math.IsInf(upper, 1))
+
+	// Copies own their bounds
+	scf.PrimalSolution = mat.NewVecDense(2, nil)
+	cp := scf.Copy()
+	cp.SetBounds(1, 0, 1)
+	lower, _ = scf.Bounds(1)
+	assert.Equal(t, lower, -2.0)
+}
@@ -2,20 +2,43 @@ package common
 
 import (
 	"context"
+	"log/slog"
+	"time"
+
 	"github.com/chriso345/gspl/internal/errors"
+	"gonum.org/v1/gonum/mat"
 )
 
 // SolverConfig holds the actual configuration with no pointers.
 type SolverConfig struct {
 	Logging       bool
+	Logger        *slog.Logger // Receives the records of the solve; see Log
 	Tolerance     float64
 	MaxIterations int
 
 	// Context for cancellation
-	Ctx context.Context
+	Ctx       context.Context
+	TimeLimit time.Duration // Wall-clock limit of a solve; zero means none
+
+	// Progress reporting: Callback receives the events of the solve, with
+	// an EventIteration every CallbackInterval simplex pivots when positive
+	Callback         Callback
+	CallbackInterval int
+
+	// LP Specific Options
+	Algorithm   Algorithm     // Algorithm used for continuous problems
+	Pricing     Pricing       // Entering column rule of the primal simplex
+	Sensitivity bool          // Compute objective and RHS ranging at the optimum
+	Presolve    bool          // Reduce the problem before the simplex method
+	Scaling     Scaling       // Row and column scaling applied before the simplex method
+	Crossover   bool          // Move a barrier or PDLP solution to an optimal basis
+	Exact       bool          // Finish with an exact rational simplex from the final basis
+	WarmStart   *Basis        // Starting basis of the simplex method, or nil for a cold start
+	StartPoint  *mat.VecDense // Primal point crossover starts from, or nil
 
 	// IP Specific Options
-	GapSensitivity float64
+	GapSensitivity float64       // Relative gap between incumbent and bound at which branch and bound stops
+	NodeSelection  NodeSelection // Order in which branch and bound explores open nodes
 	Branch         BranchFunc
 	Heuristic      HeuristicFunc
 	Cut            CutFunc
@@ -30,11 +53,26 @@ type SolverConfig struct {
 func DefaultSolverConfig() *SolverConfig {
 	return &SolverConfig{
 		Logging:       false, // Default logging is off
+		Logger:        nil,
 		Tolerance:     1e-6,
 		MaxIterations: 1000,
 		Ctx:           context.Background(),
 
-		GapSensitivity: 0.05,
+		Callback:         nil,
+		CallbackInterval: 0,
+
+		Algorithm:   AlgorithmPrimal,
+		Pricing:     PricingDantzig,
+		Sensitivity: false,
+		Presolve:    true,
+		Scaling:     ScalingGeometric,
+		Crossover:   false,
+		Exact:       false,
+		WarmStart:   nil,
+		StartPoint:  nil,
+
+		GapSensitivity: 0,
+		NodeSelection:  NodeDepthFirst,
 		Branch:         nil, // Default branching strategy defined in `brancher`
 		Heuristic:      nil, // Default heuristic defined in `brancher`
 		Cut:            nil, // Default cutting planes defined in `brancher`
@@ -56,9 +94,24 @@ func ValidateSolverConfig(cfg *SolverConfig) error {
 	if cfg.MaxIterations <= 0 {
 		return errors.New(errors.ErrInvalidInput, "max iterations must be > 0", nil)
 	}
+	if cfg.TimeLimit < 0 {
+		return errors.New(errors.ErrInvalidInput, "time limit must be >= 0", nil)
+	}
+	if cfg.Algorithm < AlgorithmPrimal || cfg.Algorithm > AlgorithmPDLP {
+		return errors.New(errors.ErrInvalidInput, "unknown algorithm", nil)
+	}
+	if cfg.Pricing < PricingDantzig || cfg.Pricing > PricingPartial {
+		return errors.New(errors.ErrInvalidInput, "unknown pricing rule", nil)
+	}
+	if cfg.Scaling < ScalingNone || cfg.Scaling > ScalingEquilibration {
+		return errors.New(errors.ErrInvalidInput, "unknown scaling method", nil)
+	}
 	if cfg.GapSensitivity < 0 || cfg.GapSensitivity > 1 {
 		return errors.New(errors.ErrInvalidInput, "gap sensitivity must be between 0 and 1", nil)
 	}
+	if cfg.NodeSelection < NodeDepthFirst || cfg.NodeSelection > NodeBestEstimate {
+		return errors.New(errors.ErrInvalidInput, "unknown node selection rule", nil)
+	}
 
 	if cfg.Debug {
 		cfg.Logging = true
@@ -70,3 +123,24 @@ func ValidateSolverConfig(cfg *SolverConfig) error {
 
 	return nil
 }
+
+// StopStatus returns the status a solve stops with once ctx is done:
+// SolverStatusTimeLimit when its deadline passed and SolverStatusCancelled
+// otherwise. It returns SolverStatusNotSolved while ctx is live or nil.
+func StopStatus(ctx context.Context) SolverStatus {
+	if ctx == nil {
+		return SolverStatusNotSolved
+	}
+	switch ctx.Err() {
+	case nil:
+		// The deadline timer may not have fired yet
+		if d, ok := ctx.Deadline(); ok && !time.Now().Before(d) {
+			return SolverStatusTimeLimit
+		}
+		return SolverStatusNotSolved
+	case context.DeadlineExceeded:
+		return SolverStatusTimeLimit
+	default:
+		return SolverStatusCancelled
+	}
+}
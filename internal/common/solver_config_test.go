@@ -1,7 +1,11 @@
 package common
 
 import (
+	"context"
+	"io"
+	"log/slog"
 	"testing"
+	"time"
 
 	"github.com/chriso345/gore/assert"
 )
@@ -11,7 +15,8 @@ func TestDefaultSolverConfig(t *testing.T) {
 	assert.False(t, cfg.Logging)
 	assert.Equal(t, cfg.Tolerance, 1e-6)
 	assert.Equal(t, cfg.MaxIterations, 1000)
-	assert.Equal(t, cfg.GapSensitivity, 0.05)
+	assert.Equal(t, cfg.GapSensitivity, 0.)
+	assert.Equal(t, cfg.NodeSelection, NodeDepthFirst)
 	assert.True(t, cfg.Branch == nil)
 	assert.True(t, cfg.Heuristic == nil)
 	assert.True(t, cfg.Cut == nil)
@@ -23,3 +28,85 @@ func TestValidateSolverConfig(t *testing.T) {
 	err := ValidateSolverConfig(cfg)
 	assert.Nil(t, err)
 }
+
+func TestValidateSolverConfigAlgorithm(t *testing.T) {
+	cfg := DefaultSolverConfig()
+	cfg.Algorithm = AlgorithmDual
+	assert.Nil(t, ValidateSolverConfig(cfg))
+
+	cfg.Algorithm = AlgorithmBarrier
+	assert.Nil(t, ValidateSolverConfig(cfg))
+
+	cfg.Algorithm = AlgorithmPDLP
+	assert.Nil(t, ValidateSolverConfig(cfg))
+
+	cfg.Algorithm = Algorithm(42)
+	assert.NotNil(t, ValidateSolverConfig(cfg))
+}
+
+func TestValidateSolverConfigScaling(t *testing.T) {
+	cfg := DefaultSolverConfig()
+	cfg.Scaling = ScalingNone
+	assert.Nil(t, ValidateSolverConfig(cfg))
+
+	cfg.Scaling = Scaling(42)
+	assert.NotNil(t, ValidateSolverConfig(cfg))
+}
+
+func TestValidateSolverConfigPricing(t *testing.T) {
+	cfg := DefaultSolverConfig()
+	cfg.Pricing = PricingDevex
+	assert.Nil(t, ValidateSolverConfig(cfg))
+
+	cfg.Pricing = Pricing(42)
+	assert.NotNil(t, ValidateSolverConfig(cfg))
+}
+
+func TestValidateSolverConfigNodeSelection(t *testing.T) {
+	cfg := DefaultSolverConfig()
+	cfg.NodeSelection = NodeBestEstimate
+	assert.Nil(t, ValidateSolverConfig(cfg))
+
+	cfg.NodeSelection = NodeSelection(42)
+	assert.NotNil(t, ValidateSolverConfig(cfg))
+}
+
+func TestValidateSolverConfigTimeLimit(t *testing.T) {
+	cfg := DefaultSolverConfig()
+	cfg.TimeLimit = time.Second
+	assert.Nil(t, ValidateSolverConfig(cfg))
+
+	cfg.TimeLimit = -time.Second
+	assert.NotNil(t, ValidateSolverConfig(cfg))
+}
+
+func TestStopStatus(t *testing.T) {
+	assert.Equal(t, StopStatus(nil), SolverStatusNotSolved)
+	assert.Equal(t, StopStatus(context.Background()), SolverStatusNotSolved)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, StopStatus(ctx), SolverStatusCancelled)
+
+	ctx, cancel = context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	assert.Equal(t, StopStatus(ctx), SolverStatusTimeLimit)
+}
+
+func TestSolverConfigLog(t *testing.T) {
+	ctx := context.Background()
+	cfg := DefaultSolverConfig()
+	assert.False(t, cfg.Log().Enabled(ctx, slog.LevelError))
+
+	// Logging writes info records, and debug records with Debug as well
+	cfg.Logging = true
+	assert.True(t, cfg.Log().Enabled(ctx, slog.LevelInfo))
+	assert.False(t, cfg.Log().Enabled(ctx, slog.LevelDebug))
+	cfg.Debug = true
+	assert.True(t, cfg.Log().Enabled(ctx, slog.LevelDebug))
+
+	// A logger of its own takes precedence
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg.Logger = logger
+	assert.True(t, cfg.Log() == logger)
+}
@@ -0,0 +1,70 @@
+package common
+
+import (
+	"math"
+	"time"
+)
+
+// Stats records the work done by a solve. Iteration counts and times are
+// summed over every LP the solve runs, so for an integer program they cover
+// the root relaxation and every node.
+type Stats struct {
+	Phase1Iterations   int // Primal simplex pivots towards a feasible basis
+	Phase2Iterations   int // Primal simplex pivots towards an optimal basis
+	DualIterations     int // Dual simplex pivots
+	InteriorIterations int // Barrier or PDLP iterations
+	Refactorisations   int // Fresh LU factorisations of a simplex basis
+
+	PresolveTime time.Duration
+	Phase1Time   time.Duration
+	Phase2Time   time.Duration
+	DualTime     time.Duration
+	InteriorTime time.Duration // Barrier or PDLP
+	TotalTime    time.Duration
+
+	// Branch and bound: the nodes whose relaxation was solved, the root
+	// included, those closed without branching because they were infeasible
+	// or could not improve on the incumbent, the depth of the deepest node,
+	// and the final relative gap between the incumbent and the best bound
+	Nodes       int
+	NodesPruned int
+	MaxDepth    int
+	Gap         float64
+}
+
+// Add adds the iteration counts and times of other to s.
+func (s *Stats) Add(other *Stats) {
+	if s == nil || other == nil {
+		return
+	}
+	s.Phase1Iterations += other.Phase1Iterations
+	s.Phase2Iterations += other.Phase2Iterations
+	s.DualIterations += other.DualIterations
+	s.InteriorIterations += other.InteriorIterations
+	s.Refactorisations += other.Refactorisations
+	s.PresolveTime += other.PresolveTime
+	s.Phase1Time += other.Phase1Time
+	s.Phase2Time += other.Phase2Time
+	s.DualTime += other.DualTime
+	s.InteriorTime += other.InteriorTime
+}
+
+// AddInterior records a run of an interior point or first-order method. It
+// does nothing on a nil Stats.
+func (s *Stats) AddInterior(iterations int, elapsed time.Duration) {
+	if s == nil {
+		return
+	}
+	s.InteriorIterations += iterations
+	s.InteriorTime += elapsed
+}
+
+// RelativeGap returns the gap between the objective of an incumbent and a
+// bound on the optimum, both in minimisation form, relative to the incumbent
+// or absolute when the incumbent is smaller than one in magnitude.
+func RelativeGap(incumbent, bound float64) float64 {
+	if math.IsInf(incumbent, 0) || math.IsNaN(incumbent) {
+		return math.Inf(1)
+	}
+	return math.Max(0, incumbent-bound) / math.Max(1, math.Abs(incumbent))
+}
@@ -0,0 +1,35 @@
+package common
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/chriso345/gore/assert"
+)
+
+func TestStatsAdd(t *testing.T) {
+	s := &Stats{Phase1Iterations: 1, DualTime: time.Second, Nodes: 3}
+	s.Add(&Stats{Phase1Iterations: 2, Refactorisations: 4, DualTime: time.Second, Nodes: 5})
+	assert.Equal(t, s.Phase1Iterations, 3)
+	assert.Equal(t, s.Refactorisations, 4)
+	assert.Equal(t, s.DualTime, 2*time.Second)
+	assert.Equal(t, s.Nodes, 3) // Search counts are kept by the caller
+
+	s.Add(nil)
+	var none *Stats
+	none.Add(s)
+	none.AddInterior(10, time.Second)
+	s.AddInterior(10, time.Second)
+	assert.Equal(t, s.InteriorIterations, 10)
+	assert.Equal(t, s.InteriorTime, time.Second)
+}
+
+func TestRelativeGap(t *testing.T) {
+	assert.Equal(t, RelativeGap(10, 8), 0.2)
+	assert.Equal(t, RelativeGap(-10, -12), 0.2)
+	assert.Equal(t, RelativeGap(0.5, 0), 0.5)
+	assert.Equal(t, RelativeGap(10, 10), 0.)
+	assert.Equal(t, RelativeGap(10, 11), 0.)
+	assert.True(t, math.IsInf(RelativeGap(math.Inf(1), 0), 1))
+}
@@ -8,6 +8,10 @@ const (
 	SolverStatusOptimal
 	SolverStatusInfeasible
 	SolverStatusUnbounded
+	SolverStatusIterationLimit // Stopped at the iteration limit
+	SolverStatusTimeLimit      // Stopped at the time limit
+	SolverStatusCancelled      // Stopped by cancellation of the context
+	SolverStatusGapLimit       // Stopped with the incumbent within the gap sensitivity of the bound
 )
 
 // String returns the string representation of the SolverStatus
@@ -21,7 +25,156 @@ func (s SolverStatus) String() string {
 		return "Infeasible"
 	case SolverStatusUnbounded:
 		return "Unbounded"
+	case SolverStatusIterationLimit:
+		return "Iteration Limit"
+	case SolverStatusTimeLimit:
+		return "Time Limit"
+	case SolverStatusCancelled:
+		return "Cancelled"
+	case SolverStatusGapLimit:
+		return "Gap Limit"
 	default:
 		return "Unknown"
 	}
 }
+
+// Stopped reports whether the solve was cut short by a limit before it could
+// decide the problem. The solution then holds the last point reached.
+func (s SolverStatus) Stopped() bool {
+	switch s {
+	case SolverStatusIterationLimit, SolverStatusTimeLimit, SolverStatusCancelled, SolverStatusGapLimit:
+		return true
+	}
+	return false
+}
+
+// Algorithm selects the LP algorithm used to solve a continuous problem
+type Algorithm int
+
+const (
+	AlgorithmPrimal  Algorithm = iota // Two-phase primal revised simplex
+	AlgorithmDual                     // Dual revised simplex
+	AlgorithmBarrier                  // Primal-dual interior point method
+	AlgorithmPDLP                     // Restarted primal-dual hybrid gradient
+)
+
+// String returns the string representation of the Algorithm
+func (a Algorithm) String() string {
+	switch a {
+	case AlgorithmPrimal:
+		return "Primal Simplex"
+	case AlgorithmDual:
+		return "Dual Simplex"
+	case AlgorithmBarrier:
+		return "Barrier"
+	case AlgorithmPDLP:
+		return "PDLP"
+	default:
+		return "Unknown"
+	}
+}
+
+// Scaling selects how the rows and columns of a problem are scaled before it
+// is solved
+type Scaling int
+
+const (
+	ScalingNone          Scaling = iota // Solve the problem as given
+	ScalingGeometric                    // Geometric mean scaling, then equilibration
+	ScalingEquilibration                // Scale the largest entry of each row and column to one
+)
+
+// String returns the string representation of the Scaling
+func (s Scaling) String() string {
+	switch s {
+	case ScalingNone:
+		return "None"
+	case ScalingGeometric:
+		return "Geometric Mean"
+	case ScalingEquilibration:
+		return "Equilibration"
+	default:
+		return "Unknown"
+	}
+}
+
+// Pricing selects how the primal simplex chooses its entering column
+type Pricing int
+
+const (
+	PricingDantzig      Pricing = iota // Most attractive reduced cost over every column
+	PricingSteepestEdge                // Reduced cost relative to the exact edge length
+	PricingDevex                       // Reduced cost relative to approximate edge weights
+	PricingPartial                     // Dantzig's rule over one segment of the columns at a time
+)
+
+// String returns the string representation of the Pricing
+func (p Pricing) String() string {
+	switch p {
+	case PricingDantzig:
+		return "Dantzig"
+	case PricingSteepestEdge:
+		return "Steepest Edge"
+	case PricingDevex:
+		return "Devex"
+	case PricingPartial:
+		return "Partial"
+	default:
+		return "Unknown"
+	}
+}
+
+// NodeSelection selects which open node branch and bound branches on next
+type NodeSelection int
+
+const (
+	NodeDepthFirst   NodeSelection = iota // Deepest node, which reaches integer solutions soonest
+	NodeBestFirst                         // Least relaxation objective, which raises the bound fastest
+	NodeBestEstimate                      // Least estimated objective of an integer solution below the node
+)
+
+// String returns the string representation of the NodeSelection
+func (s NodeSelection) String() string {
+	switch s {
+	case NodeDepthFirst:
+		return "Depth First"
+	case NodeBestFirst:
+		return "Best First"
+	case NodeBestEstimate:
+		return "Best Estimate"
+	default:
+		return "Unknown"
+	}
+}
+
+// BasisStatus is the status of a variable or a constraint's slack in a basis
+type BasisStatus int
+
+const (
+	BasisAtLower BasisStatus = iota // Nonbasic at its lower bound, or at zero when it has none
+	BasisAtUpper                    // Nonbasic at its upper bound
+	BasisBasic                      // Basic
+)
+
+// String returns the string representation of the BasisStatus
+func (s BasisStatus) String() string {
+	switch s {
+	case BasisAtLower:
+		return "At Lower"
+	case BasisAtUpper:
+		return "At Upper"
+	case BasisBasic:
+		return "Basic"
+	default:
+		return "Unknown"
+	}
+}
+
+// Basis is a simplex basis of a program as the user wrote it. Constraints
+// holds the status of the slack of each row; the slack of an equality row is
+// fixed at zero, so it is either basic or at its lower bound. A basis has as
+// many basic entries as the program has constraints.
+type Basis struct {
+	Variables   []BasisStatus // One entry per primal variable
+	Constraints []BasisStatus // One entry per constraint
+}
@@ -11,5 +11,55 @@ func TestSolverStatusString(t *testing.T) {
 	assert.Equal(t, SolverStatusOptimal.String(), "Optimal")
 	assert.Equal(t, SolverStatusInfeasible.String(), "Infeasible")
 	assert.Equal(t, SolverStatusUnbounded.String(), "Unbounded")
+	assert.Equal(t, SolverStatusIterationLimit.String(), "Iteration Limit")
+	assert.Equal(t, SolverStatusTimeLimit.String(), "Time Limit")
+	assert.Equal(t, SolverStatusCancelled.String(), "Cancelled")
+	assert.Equal(t, SolverStatusGapLimit.String(), "Gap Limit")
 	assert.Equal(t, SolverStatus(999).String(), "Unknown")
 }
+
+func TestSolverStatusStopped(t *testing.T) {
+	assert.False(t, SolverStatusOptimal.Stopped())
+	assert.False(t, SolverStatusInfeasible.Stopped())
+	assert.True(t, SolverStatusIterationLimit.Stopped())
+	assert.True(t, SolverStatusTimeLimit.Stopped())
+	assert.True(t, SolverStatusCancelled.Stopped())
+	assert.True(t, SolverStatusGapLimit.Stopped())
+}
+
+func TestAlgorithmString(t *testing.T) {
+	assert.Equal(t, AlgorithmPrimal.String(), "Primal Simplex")
+	assert.Equal(t, AlgorithmDual.String(), "Dual Simplex")
+	assert.Equal(t, AlgorithmBarrier.String(), "Barrier")
+	assert.Equal(t, AlgorithmPDLP.String(), "PDLP")
+	assert.Equal(t, Algorithm(999).String(), "Unknown")
+}
+
+func TestScalingString(t *testing.T) {
+	assert.Equal(t, ScalingNone.String(), "None")
+	assert.Equal(t, ScalingGeometric.String(), "Geometric Mean")
+	assert.Equal(t, ScalingEquilibration.String(), "Equilibration")
+	assert.Equal(t, Scaling(999).String(), "Unknown")
+}
+
+func TestPricingString(t *testing.T) {
+	assert.Equal(t, PricingDantzig.String(), "Dantzig")
+	assert.Equal(t, PricingSteepestEdge.String(), "Steepest Edge")
+	assert.Equal(t, PricingDevex.String(), "Devex")
+	assert.Equal(t, PricingPartial.String(), "Partial")
+	assert.Equal(t, Pricing(999).String(), "Unknown")
+}
+
+func TestNodeSelectionString(t *testing.T) {
+	assert.Equal(t, NodeBestFirst.String(), "Best First")
+	assert.Equal(t, NodeDepthFirst.String(), "Depth First")
+	assert.Equal(t, NodeBestEstimate.String(), "Best Estimate")
+	assert.Equal(t, NodeSelection(999).String(), "Unknown")
+}
+
+func TestBasisStatusString(t *testing.T) {
+	assert.Equal(t, BasisAtLower.String(), "At Lower")
+	assert.Equal(t, BasisAtUpper.String(), "At Upper")
+	assert.Equal(t, BasisBasic.String(), "Basic")
+	assert.Equal(t, BasisStatus(999).String(), "Unknown")
+}
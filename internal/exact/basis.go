@@ -0,0 +1,195 @@
+package exact
+
+import "math/big"
+
+// point is a basic solution of a problem together with its duals.
+type point struct {
+	x []*big.Rat // Every column, artificial columns included
+	y []*big.Rat
+	d []*big.Rat // Reduced costs of every column
+
+	basis   []int
+	atUpper []bool
+}
+
+// verify returns the basic solution of basis when it is optimal, and nil when
+// it is not or basis is not a basis of p. It solves with the basis matrix
+// alone, which is far cheaper than forming the tableau.
+func verify(p *problem, basis []int, atUpper []bool) *point {
+	m, n := p.m, p.n
+	if len(basis) != m || (atUpper != nil && len(atUpper) != n) {
+		return nil
+	}
+
+	// Only the bounds and the column state of the tableau are used
+	t := newTableau(p, ones(m))
+	t.T, t.beta = nil, nil
+	for j := range t.isBasic {
+		t.isBasic[j] = false
+	}
+	for i, j := range basis {
+		if j < 0 || j >= n+m || t.isBasic[j] {
+			return nil
+		}
+		t.isBasic[j] = true
+		t.basis[i] = j
+	}
+	for j := range n {
+		t.atUpper[j] = !t.isBasic[j] && t.upper[j] != nil && (t.lower[j] == nil || atUpper != nil && atUpper[j])
+	}
+
+	// column returns entry i of column j of [A | I]
+	column := func(i, j int) *big.Rat {
+		if j < n {
+			return p.A[i][j]
+		}
+		if j-n == i {
+			return big.NewRat(1, 1)
+		}
+		return new(big.Rat)
+	}
+
+	// B x_B = b - N x_N
+	B := make([][]*big.Rat, m)
+	r := make([]*big.Rat, m)
+	tmp := new(big.Rat)
+	for i := range m {
+		B[i] = make([]*big.Rat, m)
+		for k, j := range basis {
+			B[i][k] = new(big.Rat).Set(column(i, j))
+		}
+		r[i] = new(big.Rat).Set(p.b[i])
+		for j := range n {
+			if !t.isBasic[j] && p.A[i][j].Sign() != 0 {
+				r[i].Sub(r[i], tmp.Mul(p.A[i][j], t.nonbasicValue(j)))
+			}
+		}
+	}
+	xB := gauss(B, r)
+	if xB == nil {
+		return nil
+	}
+
+	// B^T y = c_B
+	for i := range m {
+		for k, j := range basis {
+			B[k][i] = new(big.Rat).Set(column(i, j))
+		}
+	}
+	cB := make([]*big.Rat, m)
+	for k, j := range basis {
+		cB[k] = new(big.Rat).Set(t.c[j])
+	}
+	y := gauss(B, cB)
+
+	pt := &point{
+		x:       make([]*big.Rat, n+m),
+		y:       y,
+		d:       make([]*big.Rat, n+m),
+		basis:   basis,
+		atUpper: t.atUpper[:n],
+	}
+	for j := range n + m {
+		if !t.isBasic[j] {
+			pt.x[j] = t.nonbasicValue(j)
+		}
+		pt.d[j] = new(big.Rat).Set(t.c[j])
+		if t.isBasic[j] {
+			pt.d[j].SetInt64(0)
+			continue
+		}
+		for i := range m {
+			if a := column(i, j); a.Sign() != 0 {
+				pt.d[j].Sub(pt.d[j], tmp.Mul(y[i], a))
+			}
+		}
+	}
+	for k, j := range basis {
+		pt.x[j] = xB[k]
+	}
+
+	if !t.primalFeasible(pt.x) || !t.dualFeasible(pt.d) {
+		return nil
+	}
+	return pt
+}
+
+// gauss solves M v = rhs by Gaussian elimination, overwriting both, and
+// returns nil when M is singular. Each step pivots on the column with the
+// fewest nonzeros and, within it, the row with the fewest, which keeps the
+// unit columns of slacks and artificials free of fill.
+func gauss(M [][]*big.Rat, rhs []*big.Rat) []*big.Rat {
+	m := len(M)
+	rowDone := make([]bool, m)
+	colDone := make([]bool, m)
+	pivRow := make([]int, m) // Pivot row of each step
+	pivCol := make([]int, m) // Pivot column of each step
+
+	f, tmp := new(big.Rat), new(big.Rat)
+	for step := range m {
+		c, best := -1, 0
+		for j := range m {
+			if colDone[j] {
+				continue
+			}
+			count := 0
+			for i := range m {
+				if !rowDone[i] && M[i][j].Sign() != 0 {
+					count++
+				}
+			}
+			if count == 0 {
+				return nil
+			}
+			if c < 0 || count < best {
+				c, best = j, count
+			}
+		}
+
+		r := -1
+		for i := range m {
+			if rowDone[i] || M[i][c].Sign() == 0 {
+				continue
+			}
+			count := 0
+			for j := range m {
+				if !colDone[j] && M[i][j].Sign() != 0 {
+					count++
+				}
+			}
+			if r < 0 || count < best {
+				r, best = i, count
+			}
+		}
+
+		for i := range m {
+			if rowDone[i] || i == r || M[i][c].Sign() == 0 {
+				continue
+			}
+			f.Quo(M[i][c], M[r][c])
+			for j := range m {
+				if !colDone[j] && M[r][j].Sign() != 0 {
+					M[i][j].Sub(M[i][j], tmp.Mul(f, M[r][j]))
+				}
+			}
+			rhs[i].Sub(rhs[i], tmp.Mul(f, rhs[r]))
+		}
+		rowDone[r], colDone[c] = true, true
+		pivRow[step], pivCol[step] = r, c
+	}
+
+	// Row r of a step holds nonzeros only in its own column and the columns
+	// pivoted after it
+	v := make([]*big.Rat, m)
+	for step := m - 1; step >= 0; step-- {
+		r, c := pivRow[step], pivCol[step]
+		v[c] = new(big.Rat).Set(rhs[r])
+		for j := range m {
+			if j != c && v[j] != nil && M[r][j].Sign() != 0 {
+				v[c].Sub(v[c], tmp.Mul(M[r][j], v[j]))
+			}
+		}
+		v[c].Quo(v[c], M[r][c])
+	}
+	return v
+}
@@ -0,0 +1,5 @@
+// Package exact solves linear programs in standard computational form in
+// exact rational arithmetic with math/big.Rat.
+//
+// This package is internal and intended for use within the gspl project only.
+package exact
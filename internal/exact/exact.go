@@ -0,0 +1,225 @@
+package exact
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/errors"
+	"github.com/chriso345/gspl/internal/matrix"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Solve solves scf exactly, starting from scf.Basis and scf.AtUpper when
+// they hold a basis of scf.
+//
+// The status of scf is replaced by the exact status. When that is optimal,
+// scf also receives the float64 values nearest the exact solution, duals and
+// reduced costs, and the exact optimal basis. config.MaxIterations bounds the
+// number of exact pivots; a solve stopped by a limit leaves the floating point
+// values of scf in place.
+//
+// An optimal result is its own certificate: the basis gives a primal solution
+// that satisfies Ax = b and the bounds exactly, and duals whose reduced costs
+// have the signs the bounds require, so the two objectives agree exactly.
+func Solve(scf *common.StandardComputationalForm, config *common.SolverConfig) (*Solution, error) {
+	p, err := newProblem(scf)
+	if err != nil {
+		return nil, err
+	}
+
+	pt, status, iterations := solve(p, scf.Basis, scf.AtUpper, config)
+	sol := &Solution{Status: status, Iterations: iterations}
+	*scf.Status = status
+	if status == common.SolverStatusOptimal {
+		sol.extract(p, pt)
+		sol.store(scf)
+	}
+	return sol, nil
+}
+
+// solve returns the optimal point of p and the number of exact pivots taken
+// to reach it. An optimal basis is confirmed without a tableau; any other
+// basis is continued with whichever simplex it is feasible for, and one that
+// is neither, or not a basis at all, is dropped for a two-phase solve.
+func solve(p *problem, basis []int, atUpper []bool, config *common.SolverConfig) (*point, common.SolverStatus, int) {
+	for j := range p.n {
+		if p.lower[j] != nil && p.upper[j] != nil && p.lower[j].Cmp(p.upper[j]) > 0 {
+			return nil, common.SolverStatusInfeasible, 0
+		}
+	}
+	if pt := verify(p, basis, atUpper); pt != nil {
+		return pt, common.SolverStatusOptimal, 0
+	}
+
+	t := newTableau(p, ones(p.m))
+	status := common.SolverStatusNotSolved
+	if t.install(basis, atUpper) {
+		switch {
+		case t.primalFeasible(t.values()):
+			status = t.primal(config)
+		case t.dualFeasible(t.reducedCosts()):
+			status = t.dual(config)
+		}
+	}
+	if status == common.SolverStatusNotSolved {
+		t, status = twoPhase(p, config)
+	}
+	if status != common.SolverStatusOptimal {
+		return nil, status, t.iterations
+	}
+	return t.point(), status, t.iterations
+}
+
+// twoPhase solves p from the basis of artificial columns, first minimising
+// their sum and then the objective with them fixed at zero.
+func twoPhase(p *problem, config *common.SolverConfig) (*tableau, common.SolverStatus) {
+	// Sign the artificial columns by the residual at the starting point so
+	// that they start feasible
+	sign := ones(p.m)
+	x := newTableau(p, sign).values()
+	for i := range p.m {
+		if x[p.n+i].Sign() < 0 {
+			sign[i] = -1
+		}
+	}
+	t := newTableau(p, sign)
+
+	cost := t.c
+	t.c = make([]*big.Rat, p.n+p.m)
+	for j := range t.c {
+		t.c[j] = new(big.Rat)
+		if j >= p.n {
+			t.c[j].SetInt64(1)
+			t.upper[j] = nil
+		}
+	}
+	if status := t.primal(config); status != common.SolverStatusOptimal {
+		return t, status
+	}
+	x = t.values()
+	for i := range p.m {
+		if x[p.n+i].Sign() != 0 {
+			return t, common.SolverStatusInfeasible
+		}
+	}
+
+	t.c = cost
+	for j := p.n; j < p.n+p.m; j++ {
+		t.upper[j] = new(big.Rat)
+	}
+	return t, t.primal(config)
+}
+
+// extract reads the solution at the optimal point pt of p.
+func (sol *Solution) extract(p *problem, pt *point) {
+	sol.X = pt.x[:p.n]
+	sol.Y = pt.y
+	sol.ReducedCosts = pt.d[:p.n]
+
+	sol.Objective = new(big.Rat)
+	tmp := new(big.Rat)
+	for j := range p.n {
+		sol.Objective.Add(sol.Objective, tmp.Mul(p.c[j], pt.x[j]))
+	}
+
+	sol.Basis = make([]int, p.m)
+	copy(sol.Basis, pt.basis)
+	sol.AtUpper = make([]bool, p.n)
+	copy(sol.AtUpper, pt.atUpper)
+}
+
+// store writes the float64 values nearest sol into scf.
+func (sol *Solution) store(scf *common.StandardComputationalForm) {
+	scf.PrimalSolution = float(sol.X)
+	scf.DualSolution = float(sol.Y)
+	scf.ReducedCosts = float(sol.ReducedCosts)
+	obj, _ := sol.Objective.Float64()
+	if scf.ObjectiveValue == nil {
+		scf.ObjectiveValue = new(float64)
+	}
+	*scf.ObjectiveValue = obj
+
+	scf.Basis = make([]int, len(sol.Basis))
+	copy(scf.Basis, sol.Basis)
+	scf.AtUpper = make([]bool, len(sol.AtUpper))
+	copy(scf.AtUpper, sol.AtUpper)
+}
+
+// newProblem reads scf into rational arithmetic.
+func newProblem(scf *common.StandardComputationalForm) (*problem, error) {
+	m, n := scf.Constraints.Dims()
+	p := &problem{
+		m:     m,
+		n:     n,
+		A:     make([][]*big.Rat, m),
+		b:     make([]*big.Rat, m),
+		c:     make([]*big.Rat, n),
+		lower: make([]*big.Rat, n),
+		upper: make([]*big.Rat, n),
+	}
+
+	var err error
+	for i := range m {
+		p.A[i] = make([]*big.Rat, n)
+		for j := range n {
+			p.A[i][j] = new(big.Rat)
+		}
+		if p.b[i], err = rat(scf.RHS.AtVec(i)); err != nil {
+			return nil, err
+		}
+	}
+	A := matrix.AsCSC(scf.Constraints)
+	for j := range n {
+		rows, vals := A.Col(j)
+		for k, i := range rows {
+			if p.A[i][j], err = rat(vals[k]); err != nil {
+				return nil, err
+			}
+		}
+		if p.c[j], err = rat(scf.Objective.AtVec(j)); err != nil {
+			return nil, err
+		}
+		lower, upper := scf.Bounds(j)
+		if !math.IsInf(lower, -1) {
+			if p.lower[j], err = rat(lower); err != nil {
+				return nil, err
+			}
+		}
+		if !math.IsInf(upper, 1) {
+			if p.upper[j], err = rat(upper); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return p, nil
+}
+
+// rat returns the rational with the shortest decimal expansion that rounds
+// to v, so that a coefficient written as 0.1 is exactly 1/10.
+func rat(v float64) (*big.Rat, error) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil, errors.New(errors.ErrInvalidInput, "exact solve requires finite coefficients", nil)
+	}
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(v, 'g', -1, 64))
+	return r, nil
+}
+
+func ones(m int) []int {
+	sign := make([]int, m)
+	for i := range sign {
+		sign[i] = 1
+	}
+	return sign
+}
+
+// float returns the float64 nearest to each entry of v.
+func float(v []*big.Rat) *mat.VecDense {
+	out := mat.NewVecDense(len(v), nil)
+	for i, r := range v {
+		f, _ := r.Float64()
+		out.SetVec(i, f)
+	}
+	return out
+}
@@ -0,0 +1,147 @@
+package exact
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/chriso345/gore/assert"
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/simplex"
+	"gonum.org/v1/gonum/mat"
+)
+
+// exampleSCF returns the form of
+//
+//	minimize -x1 - x2 subject to 3*x1 + x2 <= 5, x1 + 3*x2 <= 4,
+//
+// whose optimum of -9/4 is at (11/8, 7/8) with row duals (-1/4, -1/4).
+func exampleSCF() *common.StandardComputationalForm {
+	return common.NewSCF([]float64{-1, -1, 0, 0}, mat.NewDense(2, 4, []float64{
+		3, 1, 1, 0,
+		1, 3, 0, 1,
+	}), []float64{5, 4})
+}
+
+func assertRat(t *testing.T, got *big.Rat, want string) {
+	t.Helper()
+	w, _ := new(big.Rat).SetString(want)
+	assert.True(t, got.Cmp(w) == 0)
+}
+
+func TestSolve(t *testing.T) {
+	scf := exampleSCF()
+	sol, err := Solve(scf, common.DefaultSolverConfig())
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, common.SolverStatusOptimal)
+	assertRat(t, sol.Objective, "-9/4")
+	assertRat(t, sol.X[0], "11/8")
+	assertRat(t, sol.X[1], "7/8")
+	assertRat(t, sol.Y[0], "-1/4")
+	assertRat(t, sol.Y[1], "-1/4")
+	assertRat(t, sol.ReducedCosts[2], "1/4")
+
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+	assert.Equal(t, *scf.ObjectiveValue, -2.25)
+	assert.Equal(t, scf.DualSolution.AtVec(0), -0.25)
+	assert.Equal(t, len(scf.Basis), 2)
+}
+
+func TestSolveWarmStart(t *testing.T) {
+	scf := exampleSCF()
+	config := common.DefaultSolverConfig()
+	assert.Nil(t, simplex.Solve(scf, config))
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+
+	// The float basis is optimal, so no exact pivot is needed
+	sol, err := Solve(scf, config)
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, common.SolverStatusOptimal)
+	assert.Equal(t, sol.Iterations, 0)
+	assertRat(t, sol.Objective, "-9/4")
+}
+
+func TestSolveDualWarmStart(t *testing.T) {
+	// Minimize: 2*x1 + 3*x2
+	// Subject to: x1 + x2 >= 4, x1 + 3*x2 >= 6
+	// The surplus basis is dual feasible but not primal feasible
+	scf := common.NewSCF([]float64{2, 3, 0, 0}, mat.NewDense(2, 4, []float64{
+		1, 1, -1, 0,
+		1, 3, 0, -1,
+	}), []float64{4, 6})
+	scf.Basis = []int{2, 3}
+	scf.AtUpper = make([]bool, 4)
+
+	sol, err := Solve(scf, common.DefaultSolverConfig())
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, common.SolverStatusOptimal)
+	assert.True(t, sol.Iterations > 0)
+	assertRat(t, sol.Objective, "9")
+}
+
+func TestSolveDecimal(t *testing.T) {
+	// Minimize x1 + x2 subject to 0.1*x1 + 0.2*x2 >= 0.3. In float64 the
+	// ratio 0.3/0.2 is 1.4999999999999998, exactly it is 3/2
+	scf := common.NewSCF([]float64{1, 1, 0}, mat.NewDense(1, 3, []float64{0.1, 0.2, -1}), []float64{0.3})
+	sol, err := Solve(scf, common.DefaultSolverConfig())
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, common.SolverStatusOptimal)
+	assertRat(t, sol.X[1], "3/2")
+	assertRat(t, sol.Objective, "3/2")
+	assertRat(t, sol.Y[0], "5")
+}
+
+func TestSolveBounds(t *testing.T) {
+	// Minimize: -x1 - 2*x2 + x3
+	// Subject to: x1 + x2 + s = 10, x3 - x1 = -2,
+	//             0 <= x1 <= 3, -1 <= x2 <= 4, x3 free
+	inf := math.Inf(1)
+	scf := common.NewSCF([]float64{-1, -2, 1, 0}, mat.NewDense(2, 4, []float64{
+		1, 1, 0, 1,
+		-1, 0, 1, 0,
+	}), []float64{10, -2})
+	scf.Lower = mat.NewVecDense(4, []float64{0, -1, -inf, 0})
+	scf.Upper = mat.NewVecDense(4, []float64{3, 4, inf, inf})
+
+	sol, err := Solve(scf, common.DefaultSolverConfig())
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, common.SolverStatusOptimal)
+	assertRat(t, sol.Objective, "-10")
+	assertRat(t, sol.X[1], "4")
+	assert.True(t, sol.AtUpper[1])
+}
+
+func TestSolveInfeasible(t *testing.T) {
+	// x1 + x2 = -1 with x >= 0
+	scf := common.NewSCF([]float64{1, 1}, mat.NewDense(1, 2, []float64{1, 1}), []float64{-1})
+	sol, err := Solve(scf, common.DefaultSolverConfig())
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, common.SolverStatusInfeasible)
+	assert.Equal(t, *scf.Status, common.SolverStatusInfeasible)
+	assert.True(t, sol.X == nil)
+}
+
+func TestSolveUnbounded(t *testing.T) {
+	// Minimize -x1 subject to x1 - x2 = 1
+	scf := common.NewSCF([]float64{-1, 0}, mat.NewDense(1, 2, []float64{1, -1}), []float64{1})
+	sol, err := Solve(scf, common.DefaultSolverConfig())
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, common.SolverStatusUnbounded)
+}
+
+func TestSolveIterationLimit(t *testing.T) {
+	scf := exampleSCF()
+	config := common.DefaultSolverConfig()
+	config.MaxIterations = 1
+
+	sol, err := Solve(scf, config)
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, common.SolverStatusIterationLimit)
+	assert.Equal(t, *scf.Status, common.SolverStatusIterationLimit)
+}
+
+func TestSolveInvalid(t *testing.T) {
+	scf := common.NewSCF([]float64{math.NaN()}, mat.NewDense(1, 1, []float64{1}), []float64{1})
+	_, err := Solve(scf, common.DefaultSolverConfig())
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,427 @@
+package exact
+
+import (
+	"math/big"
+
+	"github.com/chriso345/gspl/internal/common"
+)
+
+// newTableau returns the tableau of p on the basis of artificial columns,
+// the column of row i being sign[i] times the i-th unit vector. Structural
+// columns start at their lower bound, or at their upper bound when only that
+// is finite, and the artificial columns are fixed at zero.
+func newTableau(p *problem, sign []int) *tableau {
+	m, n := p.m, p.n
+	t := &tableau{
+		m:       m,
+		n:       n,
+		T:       make([][]*big.Rat, m),
+		beta:    make([]*big.Rat, m),
+		c:       make([]*big.Rat, n+m),
+		sign:    sign,
+		lower:   make([]*big.Rat, n+m),
+		upper:   make([]*big.Rat, n+m),
+		basis:   make([]int, m),
+		isBasic: make([]bool, n+m),
+		atUpper: make([]bool, n+m),
+	}
+	for i := range m {
+		s := big.NewRat(int64(sign[i]), 1)
+		t.T[i] = make([]*big.Rat, n+m)
+		for j := range n {
+			t.T[i][j] = new(big.Rat).Mul(p.A[i][j], s)
+		}
+		for j := n; j < n+m; j++ {
+			t.T[i][j] = new(big.Rat)
+		}
+		t.T[i][n+i].SetInt64(1)
+		t.beta[i] = new(big.Rat).Mul(p.b[i], s)
+		t.basis[i] = n + i
+		t.isBasic[n+i] = true
+	}
+	for j := range n {
+		t.c[j] = p.c[j]
+		t.lower[j], t.upper[j] = p.lower[j], p.upper[j]
+		t.atUpper[j] = p.upper[j] != nil && p.lower[j] == nil
+	}
+	for j := n; j < n+m; j++ {
+		t.c[j] = new(big.Rat)
+		t.lower[j], t.upper[j] = new(big.Rat), new(big.Rat)
+	}
+	return t
+}
+
+// install pivots the columns of basis into the tableau, replacing its
+// artificial columns, and places the nonbasic structural columns at the bounds
+// marked by atUpper. It reports false when basis is not a basis of p.
+func (t *tableau) install(basis []int, atUpper []bool) bool {
+	if len(basis) != t.m {
+		return false
+	}
+	target := make([]bool, t.n+t.m)
+	for _, j := range basis {
+		if j < 0 || j >= t.n+t.m || target[j] {
+			return false
+		}
+		target[j] = true
+	}
+
+	// A structural column of a nonsingular basis always has a nonzero in
+	// some row still held by an artificial column outside the basis. Columns
+	// with the fewest such nonzeros go first, each into the sparsest of its
+	// rows, to limit the growth of the tableau entries.
+	var pending []int
+	for _, j := range basis {
+		if j < t.n {
+			pending = append(pending, j)
+		}
+	}
+	free := func(i int) bool {
+		k := t.basis[i]
+		return k >= t.n && !target[k]
+	}
+	for len(pending) > 0 {
+		best, bestCount := -1, 0
+		for idx, j := range pending {
+			count := 0
+			for i := range t.m {
+				if free(i) && t.T[i][j].Sign() != 0 {
+					count++
+				}
+			}
+			if count == 0 {
+				return false
+			}
+			if best < 0 || count < bestCount {
+				best, bestCount = idx, count
+			}
+		}
+		q := pending[best]
+		pending = append(pending[:best], pending[best+1:]...)
+
+		r, rowCount := -1, 0
+		for i := range t.m {
+			if !free(i) || t.T[i][q].Sign() == 0 {
+				continue
+			}
+			count := 0
+			for _, v := range t.T[i] {
+				if v.Sign() != 0 {
+					count++
+				}
+			}
+			if r < 0 || count < rowCount {
+				r, rowCount = i, count
+			}
+		}
+		t.pivot(r, q)
+	}
+
+	if len(atUpper) == t.n {
+		for j := range t.n {
+			if !t.isBasic[j] && t.upper[j] != nil {
+				t.atUpper[j] = atUpper[j] || t.lower[j] == nil
+			}
+		}
+	}
+	t.iterations = 0
+	return true
+}
+
+// pivot makes column q basic in row r.
+func (t *tableau) pivot(r, q int) {
+	row := t.T[r]
+	inv := new(big.Rat).Inv(row[q])
+	for _, v := range row {
+		if v.Sign() != 0 {
+			v.Mul(v, inv)
+		}
+	}
+	t.beta[r].Mul(t.beta[r], inv)
+
+	f, tmp := new(big.Rat), new(big.Rat)
+	for i := range t.m {
+		if i == r || t.T[i][q].Sign() == 0 {
+			continue
+		}
+		f.Set(t.T[i][q])
+		for j, v := range row {
+			if v.Sign() != 0 {
+				t.T[i][j].Sub(t.T[i][j], tmp.Mul(f, v))
+			}
+		}
+		t.beta[i].Sub(t.beta[i], tmp.Mul(f, t.beta[r]))
+	}
+
+	t.isBasic[t.basis[r]] = false
+	t.isBasic[q] = true
+	t.basis[r] = q
+	t.atUpper[q] = false
+	t.iterations++
+}
+
+// nonbasicValue returns the value of the nonbasic column j: the bound it is
+// at, or zero for a free column.
+func (t *tableau) nonbasicValue(j int) *big.Rat {
+	switch {
+	case t.atUpper[j]:
+		return new(big.Rat).Set(t.upper[j])
+	case t.lower[j] != nil:
+		return new(big.Rat).Set(t.lower[j])
+	default:
+		return new(big.Rat)
+	}
+}
+
+// values returns the value of every column at the current basis.
+func (t *tableau) values() []*big.Rat {
+	x := make([]*big.Rat, t.n+t.m)
+	for i := range t.m {
+		x[t.basis[i]] = new(big.Rat).Set(t.beta[i])
+	}
+	tmp := new(big.Rat)
+	for j := range t.n + t.m {
+		if t.isBasic[j] {
+			continue
+		}
+		x[j] = t.nonbasicValue(j)
+		if x[j].Sign() == 0 {
+			continue
+		}
+		for i := range t.m {
+			if t.T[i][j].Sign() != 0 {
+				k := t.basis[i]
+				x[k].Sub(x[k], tmp.Mul(t.T[i][j], x[j]))
+			}
+		}
+	}
+	return x
+}
+
+// reducedCosts returns c_j - c_B^T B^-1 a_j for every column.
+func (t *tableau) reducedCosts() []*big.Rat {
+	d := make([]*big.Rat, t.n+t.m)
+	tmp := new(big.Rat)
+	for j := range d {
+		d[j] = new(big.Rat).Set(t.c[j])
+		if t.isBasic[j] {
+			d[j].SetInt64(0)
+			continue
+		}
+		for i := range t.m {
+			if cb := t.c[t.basis[i]]; cb.Sign() != 0 && t.T[i][j].Sign() != 0 {
+				d[j].Sub(d[j], tmp.Mul(cb, t.T[i][j]))
+			}
+		}
+	}
+	return d
+}
+
+// duals returns y = c_B^T B^-1, read from the artificial columns.
+func (t *tableau) duals() []*big.Rat {
+	y := make([]*big.Rat, t.m)
+	tmp := new(big.Rat)
+	for i := range t.m {
+		y[i] = new(big.Rat)
+		for k := range t.m {
+			if cb := t.c[t.basis[k]]; cb.Sign() != 0 {
+				y[i].Add(y[i], tmp.Mul(cb, t.T[k][t.n+i]))
+			}
+		}
+		if t.sign[i] < 0 {
+			y[i].Neg(y[i])
+		}
+	}
+	return y
+}
+
+// point returns the basic solution at the current basis.
+func (t *tableau) point() *point {
+	return &point{
+		x:       t.values(),
+		y:       t.duals(),
+		d:       t.reducedCosts(),
+		basis:   t.basis,
+		atUpper: t.atUpper[:t.n],
+	}
+}
+
+func (t *tableau) fixed(j int) bool {
+	return t.lower[j] != nil && t.upper[j] != nil && t.lower[j].Cmp(t.upper[j]) == 0
+}
+
+// primalFeasible reports whether every basic value in x is within its bounds.
+func (t *tableau) primalFeasible(x []*big.Rat) bool {
+	for _, k := range t.basis {
+		if (t.lower[k] != nil && x[k].Cmp(t.lower[k]) < 0) || (t.upper[k] != nil && x[k].Cmp(t.upper[k]) > 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// dualFeasible reports whether every reduced cost in d has the sign the
+// bound of its nonbasic column requires.
+func (t *tableau) dualFeasible(d []*big.Rat) bool {
+	for j := range t.n + t.m {
+		if t.isBasic[j] || t.fixed(j) {
+			continue
+		}
+		switch {
+		case t.atUpper[j]:
+			if d[j].Sign() > 0 {
+				return false
+			}
+		case t.lower[j] == nil:
+			if d[j].Sign() != 0 {
+				return false
+			}
+		default:
+			if d[j].Sign() < 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// stop returns the status the exact simplex stops with, or
+// SolverStatusNotSolved while it may continue.
+func (t *tableau) stop(config *common.SolverConfig) common.SolverStatus {
+	if s := common.StopStatus(config.Ctx); s != common.SolverStatusNotSolved {
+		return s
+	}
+	if t.iterations >= config.MaxIterations {
+		return common.SolverStatusIterationLimit
+	}
+	return common.SolverStatusNotSolved
+}
+
+// primal runs the bounded primal simplex from a primal feasible basis. The
+// entering column is the lowest index with an improving reduced cost and
+// ties in the ratio test go to the lowest basic column. This is Bland's rule,
+// so the method cannot cycle.
+func (t *tableau) primal(config *common.SolverConfig) common.SolverStatus {
+	for {
+		d := t.reducedCosts()
+		q, increase := -1, false
+		for j := range t.n + t.m {
+			if t.isBasic[j] || t.fixed(j) {
+				continue
+			}
+			if d[j].Sign() < 0 && !t.atUpper[j] {
+				q, increase = j, true
+				break
+			}
+			if d[j].Sign() > 0 && (t.atUpper[j] || t.lower[j] == nil) {
+				q, increase = j, false
+				break
+			}
+		}
+		if q < 0 {
+			return common.SolverStatusOptimal
+		}
+		if s := t.stop(config); s != common.SolverStatusNotSolved {
+			return s
+		}
+
+		// Basic value k moves by -alpha per unit step of column q
+		x := t.values()
+		r, toUpper := -1, false
+		var step *big.Rat
+		for i := range t.m {
+			alpha := new(big.Rat).Set(t.T[i][q])
+			if !increase {
+				alpha.Neg(alpha)
+			}
+			k := t.basis[i]
+			ratio, hitUpper := new(big.Rat), false
+			switch {
+			case alpha.Sign() > 0 && t.lower[k] != nil:
+				ratio.Sub(x[k], t.lower[k])
+				ratio.Quo(ratio, alpha)
+			case alpha.Sign() < 0 && t.upper[k] != nil:
+				ratio.Sub(x[k], t.upper[k])
+				ratio.Quo(ratio, alpha)
+				hitUpper = true
+			default:
+				continue
+			}
+			if r < 0 || ratio.Cmp(step) < 0 || (ratio.Cmp(step) == 0 && k < t.basis[r]) {
+				r, step, toUpper = i, ratio, hitUpper
+			}
+		}
+
+		if t.lower[q] != nil && t.upper[q] != nil {
+			width := new(big.Rat).Sub(t.upper[q], t.lower[q])
+			if r < 0 || width.Cmp(step) <= 0 {
+				t.atUpper[q] = !t.atUpper[q]
+				t.iterations++
+				continue
+			}
+		}
+		if r < 0 {
+			return common.SolverStatusUnbounded
+		}
+		leaving := t.basis[r]
+		t.pivot(r, q)
+		t.atUpper[leaving] = toUpper
+	}
+}
+
+// dual runs the bounded dual simplex from a dual feasible basis. The leaving
+// row is the one whose basic column has the lowest index among those out of
+// bounds and ties in the ratio test go to the lowest entering column, the dual
+// form of Bland's rule.
+func (t *tableau) dual(config *common.SolverConfig) common.SolverStatus {
+	for {
+		x := t.values()
+		r, below := -1, false
+		for i, k := range t.basis {
+			if r >= 0 && k > t.basis[r] {
+				continue
+			}
+			switch {
+			case t.lower[k] != nil && x[k].Cmp(t.lower[k]) < 0:
+				r, below = i, true
+			case t.upper[k] != nil && x[k].Cmp(t.upper[k]) > 0:
+				r, below = i, false
+			}
+		}
+		if r < 0 {
+			return common.SolverStatusOptimal
+		}
+		if s := t.stop(config); s != common.SolverStatusNotSolved {
+			return s
+		}
+
+		// The basic value of row r moves by -alpha per unit increase of a
+		// nonbasic column, and must rise when below its bound
+		d := t.reducedCosts()
+		q := -1
+		var best *big.Rat
+		for j := range t.n + t.m {
+			alpha := t.T[r][j]
+			if t.isBasic[j] || t.fixed(j) || alpha.Sign() == 0 {
+				continue
+			}
+			rises := alpha.Sign() < 0
+			canIncrease := !t.atUpper[j]
+			canDecrease := t.atUpper[j] || t.lower[j] == nil
+			if !(rises == below && canIncrease) && !(rises != below && canDecrease) {
+				continue
+			}
+			ratio := new(big.Rat).Quo(d[j], alpha)
+			ratio.Abs(ratio)
+			if q < 0 || ratio.Cmp(best) < 0 {
+				q, best = j, ratio
+			}
+		}
+		if q < 0 {
+			return common.SolverStatusInfeasible
+		}
+		leaving := t.basis[r]
+		t.pivot(r, q)
+		t.atUpper[leaving] = !below
+	}
+}
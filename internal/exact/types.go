@@ -0,0 +1,55 @@
+package exact
+
+import (
+	"math/big"
+
+	"github.com/chriso345/gspl/internal/common"
+)
+
+// Solution is the result of an exact solve. The vectors are set only when
+// the status is optimal.
+type Solution struct {
+	Status common.SolverStatus
+
+	X            []*big.Rat // Primal solution, one entry per column of A
+	Y            []*big.Rat // Duals, one entry per row of A
+	ReducedCosts []*big.Rat // c - A^T y, one entry per column of A
+	Objective    *big.Rat   // c^T x, equal to the dual objective
+
+	// Optimal basis in the convention of StandardComputationalForm
+	Basis   []int
+	AtUpper []bool
+
+	Iterations int // Exact simplex pivots, including bound flips
+}
+
+// tableau is the dense simplex tableau B^-1 [A | S] of a problem with m rows
+// and n structural columns, where S = diag(sign) holds the artificial columns
+// n..n+m-1. A nil bound is infinite.
+type tableau struct {
+	m, n int
+
+	T    [][]*big.Rat // m rows of n+m entries
+	beta []*big.Rat   // B^-1 b
+	c    []*big.Rat   // Costs of all n+m columns
+	sign []int        // Sign of each artificial column
+
+	lower []*big.Rat
+	upper []*big.Rat
+
+	basis   []int  // Basic column of each row
+	isBasic []bool // Indexed by column
+	atUpper []bool // Nonbasic columns at their upper bound
+
+	iterations int
+}
+
+// problem holds the rational data read from a StandardComputationalForm.
+type problem struct {
+	m, n  int
+	A     [][]*big.Rat // Dense m x n copy of the constraint matrix
+	b     []*big.Rat
+	c     []*big.Rat
+	lower []*big.Rat // nil for -Inf
+	upper []*big.Rat // nil for +Inf
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math"
 	"strconv"
 	"strings"
 
@@ -87,9 +88,69 @@ func parseExpression(s string) ([]struct {
 	return out, nil
 }
 
+// parseVarDecl parses the remainder of a var statement such as
+// "x >= -5, <= 10" or "y integer, >= 0". As in GMPL, a variable without a
+// lower bound attribute is free.
+func parseVarDecl(s string) (lp.LpVariable, error) {
+	end := strings.IndexAny(s, " \t,<>=")
+	if end < 0 {
+		end = len(s)
+	}
+	name := s[:end]
+	if name == "" {
+		return lp.LpVariable{}, fmt.Errorf("missing variable name in %q", s)
+	}
+
+	lower, upper := math.Inf(-1), math.Inf(1)
+	opts := []lp.LpVariableOption{}
+	rest := strings.ReplaceAll(s[end:], ",", " ")
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		op := ""
+		for _, candidate := range []string{">=", "<=", "="} {
+			if strings.HasPrefix(rest, candidate) {
+				op = candidate
+				break
+			}
+		}
+
+		if op == "" {
+			word, tail, _ := strings.Cut(rest, " ")
+			switch word {
+			case "integer":
+				opts = append(opts, lp.LpCategoryInteger)
+			case "binary":
+				opts = append(opts, lp.LpCategoryBinary)
+				lower, upper = math.Max(lower, 0), math.Min(upper, 1)
+			default:
+				return lp.LpVariable{}, fmt.Errorf("unsupported attribute %q for variable %s", word, name)
+			}
+			rest = tail
+			continue
+		}
+
+		valStr, tail, _ := strings.Cut(strings.TrimSpace(rest[len(op):]), " ")
+		val, err := strconv.ParseFloat(valStr, 64)
+		if err != nil {
+			return lp.LpVariable{}, fmt.Errorf("invalid bound %q for variable %s: %w", valStr, name, err)
+		}
+		switch op {
+		case ">=":
+			lower = val
+		case "<=":
+			upper = val
+		case "=":
+			lower, upper = val, val
+		}
+		rest = tail
+	}
+
+	opts = append(opts, lp.WithBounds(lower, upper))
+	return lp.NewVariable(name, opts...), nil
+}
+
 func parseReaderToLP(r io.Reader, filename string) (*lp.LinearProgram, error) {
 	s := bufio.NewScanner(r)
-	vars := []string{}
+	vars := []lp.LpVariable{}
 	objectiveSense := lp.LpMinimise
 	objectiveExpr := ""
 	constraints := []struct {
@@ -107,11 +168,12 @@ func parseReaderToLP(r io.Reader, filename string) (*lp.LinearProgram, error) {
 		line = strings.TrimSuffix(line, ";")
 		lower := strings.ToLower(line)
 		if strings.HasPrefix(lower, "var ") {
-			// var x1 >= 0;  -> extract variable name
-			parts := strings.Fields(line)
-			if len(parts) >= 2 {
-				vars = append(vars, parts[1])
+			// var x1 >= 0, <= 10;  -> variable name plus attributes
+			v, err := parseVarDecl(strings.TrimSpace(line[len("var "):]))
+			if err != nil {
+				return nil, err
 			}
+			vars = append(vars, v)
 			continue
 		}
 		if strings.HasPrefix(lower, "maximize") || strings.HasPrefix(lower, "minimize") {
@@ -182,11 +244,7 @@ func parseReaderToLP(r io.Reader, filename string) (*lp.LinearProgram, error) {
 	}
 
 	// build LP
-	lpVars := make([]lp.LpVariable, len(vars))
-	for i, v := range vars {
-		lpVars[i] = lp.NewVariable(v)
-	}
-	lprog := lp.NewLinearProgram(filename, lpVars)
+	lprog := lp.NewLinearProgram(filename, vars)
 	// objective
 	if objectiveExpr != "" {
 		parsed, err := parseExpression(objectiveExpr)
@@ -2,6 +2,7 @@ package gmpl
 
 import (
 	"context"
+	"math"
 	"os"
 	"strings"
 	"testing"
@@ -36,8 +37,75 @@ func TestParseExampleFile(t *testing.T) {
 	if !found["x1"] || !found["x2"] {
 		t.Fatalf("expected x1 and x2 to be present, vars: %v", m.LP.Vars)
 	}
+	// the example declares its variables non-negative, as a plain var is free
+	for _, v := range m.LP.Vars {
+		if lower, _ := v.Bounds(); lower != 0 {
+			t.Fatalf("expected %s to be non-negative, got lower bound %v", v.Name, lower)
+		}
+	}
 	// objective should be maximise
 	if m.LP.Sense != lp.LpMaximise {
 		t.Fatalf("expected maximise sense, got %v", m.LP.Sense)
 	}
 }
+
+func TestParseVarBounds(t *testing.T) {
+	src := `var x >= -5, <= 10;
+var y;
+var z integer, >= 1;
+var w = 2;
+
+minimize z: x + y + z + w;
+
+subject to c1: x + y >= 3;
+`
+	node, err := New().Parse(context.Background(), strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	m := node.(*ast.Module)
+
+	want := map[string][2]float64{
+		"x": {-5, 10},
+		"y": {math.Inf(-1), math.Inf(1)},
+		"z": {1, math.Inf(1)},
+		"w": {2, 2},
+	}
+	for _, v := range m.LP.Vars {
+		bounds, ok := want[v.Name]
+		if !ok {
+			continue
+		}
+		lower, upper := v.Bounds()
+		if lower != bounds[0] || upper != bounds[1] {
+			t.Fatalf("variable %s: got bounds [%v, %v], want %v", v.Name, lower, upper, bounds)
+		}
+		if v.Name == "z" && v.Category != lp.LpCategoryInteger {
+			t.Fatalf("expected z to be integer")
+		}
+	}
+
+	if _, err := New().Parse(context.Background(), strings.NewReader("var x >= abc;")); err == nil {
+		t.Fatalf("expected error for invalid bound")
+	}
+}
+
+func TestParseVarDefaultFree(t *testing.T) {
+	// Only an explicit lower bound makes a variable non-negative
+	for _, tc := range []struct {
+		decl         string
+		lower, upper float64
+	}{
+		{"x", math.Inf(-1), math.Inf(1)},
+		{"x >= 0", 0, math.Inf(1)},
+	} {
+		v, err := parseVarDecl(tc.decl)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tc.decl, err)
+		}
+		lower, upper := v.Bounds()
+		if lower != tc.lower || upper != tc.upper {
+			t.Fatalf("var %s: got bounds [%v, %v], want [%v, %v]", tc.decl, lower, upper, tc.lower, tc.upper)
+		}
+	}
+}
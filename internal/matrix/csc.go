@@ -0,0 +1,262 @@
+package matrix
+
+import (
+	"slices"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// CSC is a sparse matrix in compressed sparse column form. Only nonzero
+// entries are stored, with the row indices of each column in increasing order.
+// CSC implements mat.Matrix so it can be used wherever a gonum matrix is read.
+type CSC struct {
+	rows, cols int
+	colPtr     []int // column j occupies [colPtr[j], colPtr[j+1])
+	rowIdx     []int
+	values     []float64
+}
+
+// NewCSC creates an all-zero rows x cols sparse matrix.
+func NewCSC(rows, cols int) *CSC {
+	return &CSC{
+		rows:   rows,
+		cols:   cols,
+		colPtr: make([]int, cols+1),
+	}
+}
+
+// CSCFromMatrix returns a sparse copy of A.
+func CSCFromMatrix(A mat.Matrix) *CSC {
+	if s, ok := A.(*CSC); ok {
+		return s.Clone()
+	}
+	rows, cols := A.Dims()
+	s := NewCSC(rows, cols)
+	for j := range cols {
+		for i := range rows {
+			if v := A.At(i, j); v != 0 {
+				s.rowIdx = append(s.rowIdx, i)
+				s.values = append(s.values, v)
+			}
+		}
+		s.colPtr[j+1] = len(s.values)
+	}
+	return s
+}
+
+// CSCFromColumns builds a rows x len(colRows) sparse matrix from the row
+// indices and values of the entries of each column, in any order.
+func CSCFromColumns(rows int, colRows [][]int, colVals [][]float64) *CSC {
+	s := NewCSC(rows, len(colRows))
+	for j := range colRows {
+		order := make([]int, len(colRows[j]))
+		for k := range order {
+			order[k] = k
+		}
+		sort.Slice(order, func(a, b int) bool { return colRows[j][order[a]] < colRows[j][order[b]] })
+		for _, k := range order {
+			if colVals[j][k] != 0 {
+				s.rowIdx = append(s.rowIdx, colRows[j][k])
+				s.values = append(s.values, colVals[j][k])
+			}
+		}
+		s.colPtr[j+1] = len(s.values)
+	}
+	return s
+}
+
+// AsCSC returns A itself when it is already sparse, or a sparse copy of it.
+func AsCSC(A mat.Matrix) *CSC {
+	if s, ok := A.(*CSC); ok {
+		return s
+	}
+	return CSCFromMatrix(A)
+}
+
+// Dims returns the number of rows and columns of the matrix.
+func (s *CSC) Dims() (int, int) {
+	return s.rows, s.cols
+}
+
+// At returns the element at row i and column j.
+func (s *CSC) At(i, j int) float64 {
+	if i < 0 || i >= s.rows {
+		panic(mat.ErrRowAccess)
+	}
+	if j < 0 || j >= s.cols {
+		panic(mat.ErrColAccess)
+	}
+	rows := s.rowIdx[s.colPtr[j]:s.colPtr[j+1]]
+	k := sort.SearchInts(rows, i)
+	if k < len(rows) && rows[k] == i {
+		return s.values[s.colPtr[j]+k]
+	}
+	return 0
+}
+
+// T returns the transpose of the matrix without copying.
+func (s *CSC) T() mat.Matrix {
+	return mat.Transpose{Matrix: s}
+}
+
+// NNZ returns the number of stored nonzero entries.
+func (s *CSC) NNZ() int {
+	return len(s.values)
+}
+
+// Col returns the row indices and values of the nonzeros in column j. The
+// returned slices share storage with the matrix and must not be modified.
+func (s *CSC) Col(j int) ([]int, []float64) {
+	lo, hi := s.colPtr[j], s.colPtr[j+1]
+	return s.rowIdx[lo:hi], s.values[lo:hi]
+}
+
+// Clone returns a deep copy of the matrix.
+func (s *CSC) Clone() *CSC {
+	return &CSC{
+		rows:   s.rows,
+		cols:   s.cols,
+		colPtr: slices.Clone(s.colPtr),
+		rowIdx: slices.Clone(s.rowIdx),
+		values: slices.Clone(s.values),
+	}
+}
+
+// CSCAppendRow returns a copy of A with the dense row appended below it.
+func CSCAppendRow(A *CSC, row []float64) *CSC {
+	if len(row) != A.cols {
+		panic("Row length must match the number of columns in the matrix")
+	}
+	s := &CSC{
+		rows:   A.rows + 1,
+		cols:   A.cols,
+		colPtr: make([]int, A.cols+1),
+		rowIdx: make([]int, 0, A.NNZ()+A.cols),
+		values: make([]float64, 0, A.NNZ()+A.cols),
+	}
+	for j := range A.cols {
+		rows, vals := A.Col(j)
+		s.rowIdx = append(s.rowIdx, rows...)
+		s.values = append(s.values, vals...)
+		if row[j] != 0 {
+			s.rowIdx = append(s.rowIdx, A.rows)
+			s.values = append(s.values, row[j])
+		}
+		s.colPtr[j+1] = len(s.values)
+	}
+	return s
+}
+
+// CSCAppendColumn returns a copy of A with a column appended to its right. The
+// column is given by the row indices and values of its nonzeros.
+func CSCAppendColumn(A *CSC, rows []int, vals []float64) *CSC {
+	if len(rows) != len(vals) {
+		panic("Row indices and values must have the same length")
+	}
+	s := A.Clone()
+	s.cols++
+
+	order := make([]int, len(rows))
+	for k := range order {
+		order[k] = k
+	}
+	sort.Slice(order, func(a, b int) bool { return rows[order[a]] < rows[order[b]] })
+	for _, k := range order {
+		if rows[k] < 0 || rows[k] >= A.rows {
+			panic(mat.ErrRowAccess)
+		}
+		if vals[k] != 0 {
+			s.rowIdx = append(s.rowIdx, rows[k])
+			s.values = append(s.values, vals[k])
+		}
+	}
+	s.colPtr = append(s.colPtr, len(s.values))
+	return s
+}
+
+// CSCScale returns diag(rowScale) * A * diag(colScale).
+func CSCScale(A *CSC, rowScale, colScale []float64) *CSC {
+	if len(rowScale) != A.rows || len(colScale) != A.cols {
+		panic("Scale lengths must match the dimensions of the matrix")
+	}
+	s := A.Clone()
+	for j := range s.cols {
+		for k := s.colPtr[j]; k < s.colPtr[j+1]; k++ {
+			s.values[k] *= rowScale[s.rowIdx[k]] * colScale[j]
+		}
+	}
+	return s
+}
+
+// ColDot returns the dot product of column j of A with v, touching only the
+// nonzeros when A is sparse.
+func ColDot(A mat.Matrix, j int, v mat.Vector) float64 {
+	if s, ok := A.(*CSC); ok {
+		rows, vals := s.Col(j)
+		dot := 0.
+		for k, i := range rows {
+			dot += vals[k] * v.AtVec(i)
+		}
+		return dot
+	}
+	m, _ := A.Dims()
+	dot := 0.
+	for i := range m {
+		dot += A.At(i, j) * v.AtVec(i)
+	}
+	return dot
+}
+
+// AddScaledCol adds alpha times column j of A to dst.
+func AddScaledCol(dst *mat.VecDense, alpha float64, A mat.Matrix, j int) {
+	if s, ok := A.(*CSC); ok {
+		rows, vals := s.Col(j)
+		for k, i := range rows {
+			dst.SetVec(i, dst.AtVec(i)+alpha*vals[k])
+		}
+		return
+	}
+	m, _ := A.Dims()
+	for i := range m {
+		dst.SetVec(i, dst.AtVec(i)+alpha*A.At(i, j))
+	}
+}
+
+// ColInto copies column j of A into dst.
+func ColInto(dst *mat.VecDense, A mat.Matrix, j int) {
+	dst.Zero()
+	AddScaledCol(dst, 1, A, j)
+}
+
+// CSCDiag returns a square sparse matrix with d on its diagonal.
+func CSCDiag(d []float64) *CSC {
+	s := NewCSC(len(d), len(d))
+	for j, v := range d {
+		if v != 0 {
+			s.rowIdx = append(s.rowIdx, j)
+			s.values = append(s.values, v)
+		}
+		s.colPtr[j+1] = len(s.values)
+	}
+	return s
+}
+
+// CSCHStack returns [A | B], the columns of B placed to the right of A.
+func CSCHStack(A, B *CSC) *CSC {
+	if A.rows != B.rows {
+		panic("Number of rows must match for stacking")
+	}
+	s := &CSC{
+		rows:   A.rows,
+		cols:   A.cols + B.cols,
+		colPtr: make([]int, 0, A.cols+B.cols+1),
+		rowIdx: append(slices.Clone(A.rowIdx), B.rowIdx...),
+		values: append(slices.Clone(A.values), B.values...),
+	}
+	s.colPtr = append(s.colPtr, A.colPtr...)
+	for _, p := range B.colPtr[1:] {
+		s.colPtr = append(s.colPtr, A.NNZ()+p)
+	}
+	return s
+}
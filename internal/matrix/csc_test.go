@@ -0,0 +1,110 @@
+package matrix
+
+import (
+	"testing"
+
+	"github.com/chriso345/gore/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func assertMatrixEqual(t *testing.T, got, want mat.Matrix) {
+	t.Helper()
+	gr, gc := got.Dims()
+	wr, wc := want.Dims()
+	assert.Equal(t, gr, wr)
+	assert.Equal(t, gc, wc)
+	for i := range wr {
+		for j := range wc {
+			assert.Equal(t, got.At(i, j), want.At(i, j))
+		}
+	}
+}
+
+func TestCSCFromMatrix(t *testing.T) {
+	dense := mat.NewDense(3, 3, []float64{
+		1, 0, 2,
+		0, 0, 3,
+		4, 0, 0,
+	})
+	s := CSCFromMatrix(dense)
+	assertMatrixEqual(t, s, dense)
+	assertMatrixEqual(t, s.T(), dense.T())
+	assert.Equal(t, s.NNZ(), 4)
+
+	rows, vals := s.Col(2)
+	assert.Equal(t, len(rows), 2)
+	assert.Equal(t, rows[1], 1)
+	assert.Equal(t, vals[1], 3.0)
+
+	rows, _ = s.Col(1)
+	assert.Equal(t, len(rows), 0)
+}
+
+func TestCSCAsAndClone(t *testing.T) {
+	s := CSCFromMatrix(mat.NewDense(1, 2, []float64{1, 2}))
+	assert.True(t, AsCSC(s) == s)
+
+	c := CSCFromMatrix(s)
+	assert.True(t, c != s)
+	c.values[0] = 9
+	assert.Equal(t, s.At(0, 0), 1.0)
+}
+
+func TestCSCAppendRowAndColumn(t *testing.T) {
+	s := NewCSC(0, 2)
+	s = CSCAppendRow(s, []float64{1, 0})
+	s = CSCAppendRow(s, []float64{0, 2})
+	s = CSCAppendColumn(s, []int{1, 0}, []float64{-1, 3})
+
+	assertMatrixEqual(t, s, mat.NewDense(2, 3, []float64{
+		1, 0, 3,
+		0, 2, -1,
+	}))
+	assert.Equal(t, s.NNZ(), 4)
+}
+
+func TestCSCHStackDiag(t *testing.T) {
+	A := CSCFromMatrix(mat.NewDense(2, 1, []float64{5, 6}))
+	s := CSCHStack(A, CSCDiag([]float64{1, -1}))
+	assertMatrixEqual(t, s, mat.NewDense(2, 3, []float64{
+		5, 1, 0,
+		6, 0, -1,
+	}))
+}
+
+func TestColumnHelpers(t *testing.T) {
+	dense := mat.NewDense(2, 2, []float64{1, 2, 3, 4})
+	v := mat.NewVecDense(2, []float64{1, -1})
+
+	for _, A := range []mat.Matrix{dense, CSCFromMatrix(dense)} {
+		assert.Equal(t, ColDot(A, 1, v), -2.0)
+
+		dst := mat.NewVecDense(2, []float64{1, 1})
+		AddScaledCol(dst, 2, A, 0)
+		assert.Equal(t, dst.AtVec(0), 3.0)
+		assert.Equal(t, dst.AtVec(1), 7.0)
+
+		ColInto(dst, A, 1)
+		assert.Equal(t, dst.AtVec(0), 2.0)
+		assert.Equal(t, dst.AtVec(1), 4.0)
+	}
+}
+
+func TestCSCFromColumns(t *testing.T) {
+	s := CSCFromColumns(3, [][]int{{2, 0}, {}, {1}}, [][]float64{{4, 1}, {}, {3}})
+	assertMatrixEqual(t, s, mat.NewDense(3, 3, []float64{
+		1, 0, 0,
+		0, 0, 3,
+		4, 0, 0,
+	}))
+}
+
+func TestCSCScale(t *testing.T) {
+	A := CSCFromMatrix(mat.NewDense(2, 2, []float64{1, 2, 0, 4}))
+	s := CSCScale(A, []float64{2, 0.5}, []float64{1, 4})
+	assertMatrixEqual(t, s, mat.NewDense(2, 2, []float64{
+		2, 16,
+		0, 8,
+	}))
+	assert.Equal(t, A.At(0, 1), 2.0)
+}
@@ -31,27 +31,24 @@ func ResizeMatDense(m *mat.Dense, newRows, newCols int) *mat.Dense {
 	return newMat
 }
 
-// ExtractColumns extracts columns from a *mat.Dense matrix based on the provided indices.
-func ExtractColumns(A *mat.Dense, indices *mat.VecDense) *mat.Dense {
+// ExtractColumns extracts columns from a matrix based on the provided indices.
+func ExtractColumns(A mat.Matrix, indices *mat.VecDense) *mat.Dense {
 	m, n := A.Dims()
 	numIndices := indices.Len()
-
 	if numIndices == 0 {
 		return mat.NewDense(m, 0, nil) // Return empty matrix if no indices
 	}
 
 	extracted := mat.NewDense(m, numIndices, nil)
-	for i := range m {
-		for j := range numIndices {
-			colIndex := int(indices.AtVec(j))
-			if colIndex >= 0 && colIndex < n {
-				extracted.Set(i, j, A.At(i, colIndex))
-			} else {
-				extracted.Set(i, j, 0.0) // Handle out-of-bounds indices
-			}
+	col := mat.NewVecDense(m, nil)
+	for j := range numIndices {
+		colIndex := int(indices.AtVec(j))
+		if colIndex < 0 || colIndex >= n {
+			continue // Out-of-bounds indices give a zero column
 		}
+		ColInto(col, A, colIndex)
+		extracted.SetCol(j, col.RawVector().Data)
 	}
-
 	return extracted
 }
 
@@ -2,7 +2,9 @@
 // matrix package to support common matrix operations.
 //
 // This package includes functions for extracting columns, creating identity
-// matrices, and resizing dense matrices and vectors.
+// matrices, and resizing dense matrices and vectors. It also provides CSC, a
+// compressed sparse column matrix used for constraint matrices, together with
+// column helpers that take a fast path for sparse input.
 //
 // It is intended for internal use within the gspl project.
 package matrix
@@ -0,0 +1,5 @@
+// Package pdlp provides a first-order primal-dual method for large sparse
+// linear programs in standard computational form.
+//
+// This package is internal and intended for use within the gspl project only.
+package pdlp
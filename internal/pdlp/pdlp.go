@@ -0,0 +1,436 @@
+package pdlp
+
+import (
+	"math"
+	"time"
+
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/matrix"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	// stepFraction is the step size as a share of 1/||A||, below the bound
+	// of one under which PDHG converges.
+	stepFraction = 0.9
+
+	// powerIterations is the number of power iterations used to estimate
+	// ||A||.
+	powerIterations = 30
+
+	// iterationsPerPivot is the number of PDHG iterations allowed for each
+	// pivot of config.MaxIterations, which counts simplex pivots. An
+	// iteration is only two products with A, far cheaper than a pivot, and
+	// PDHG needs many more of them.
+	iterationsPerPivot = 100
+
+	// restartFrequency is the number of iterations between evaluations of
+	// termination, infeasibility and restarts.
+	restartFrequency = 64
+
+	// sufficientReduction, necessaryReduction and artificialRestart are the
+	// restart criteria of PDLP: restart once the KKT error has fallen to this
+	// share of its value at the last restart, or to the larger share while no
+	// longer falling, or after this share of all iterations without one.
+	sufficientReduction = 0.2
+	necessaryReduction  = 0.8
+	artificialRestart   = 0.36
+
+	// primalWeightSmoothing is the weight of the new estimate when the primal
+	// weight is updated at a restart.
+	primalWeightSmoothing = 0.5
+
+	// infeasibilityTolerance is the relative residual below which the
+	// normalised difference of iterates is accepted as a certificate of
+	// infeasibility or unboundedness.
+	infeasibilityTolerance = 1e-8
+)
+
+// Solve solves scf approximately with restarted, preconditioned PDHG in the
+// form used by PDLP. Each iteration takes one product with A and one with
+// A^T, so no basis or factorisation is held, and the primal weight that
+// balances the two step sizes is re-estimated at every restart.
+//
+// The status is optimal once the relative primal and dual residuals and the
+// relative duality gap are within config.Tolerance; scf then holds the primal
+// and dual solution and the reduced costs, with Basis and AtUpper nil.
+// config.MaxIterations bounds the number of PDHG iterations at
+// iterationsPerPivot times its value, so that the default limit suits both
+// methods. A solve stopped by a limit records its last point without duals.
+// An improving ray found before any feasible point leaves the status
+// SolverStatusNotSolved, as the ray alone cannot tell an unbounded problem
+// from an infeasible one.
+func Solve(scf *common.StandardComputationalForm, config *common.SolverConfig) error {
+	orig, ok := newProblem(scf, config.Tolerance)
+	if !ok {
+		*scf.Status = common.SolverStatusInfeasible
+		return nil
+	}
+	p, rowScale, colScale := precondition(orig)
+
+	eta := stepFraction / operatorNorm(p.A)
+	omega := 1.
+	if nb, nc := floats.Norm(p.b, 2), floats.Norm(p.c, 2); nb > 0 && nc > 0 {
+		omega = nc / nb
+	}
+
+	cur := iterate{x: make([]float64, p.n), y: make([]float64, p.m)}
+	for j := range p.n {
+		cur.x[j] = clamp(0, p.lower[j], p.upper[j])
+	}
+	last := cur.clone() // Point of the last restart
+	sum := iterate{x: make([]float64, p.n), y: make([]float64, p.m)}
+	count := 0 // Iterations since the last restart, averaged in sum
+	lastKKT := p.kktError(cur, omega)
+	prevKKT := math.Inf(1) // Candidate KKT error at the previous evaluation
+
+	aty := make([]float64, p.n)
+	ax := make([]float64, p.m)
+	extrapolated := make([]float64, p.n)
+	next := iterate{x: make([]float64, p.n), y: make([]float64, p.m)}
+
+	iterations := 0
+	start := time.Now()
+	defer func() { scf.Stats.AddInterior(iterations, time.Since(start)) }()
+	for {
+		// Restarts go to the current or the average iterate, whichever has
+		// the smaller KKT error, once that error has fallen far enough
+		if iterations%restartFrequency == 0 {
+			candidate := cur
+			if count > 0 {
+				avg := sum.scaled(1 / float64(count))
+				if p.kktError(avg, omega) < p.kktError(cur, omega) {
+					candidate = avg
+				}
+			}
+
+			for _, it := range []iterate{cur, candidate} {
+				if sol := orig.unscale(it, rowScale, colScale); orig.converged(sol, config.Tolerance) {
+					orig.store(scf, sol, common.SolverStatusOptimal)
+					return nil
+				}
+			}
+
+			// The difference of successive restart points tends to a Farkas
+			// certificate or an improving ray when there is no optimum
+			if count > 0 {
+				dx, dy := difference(cur.x, last.x), difference(cur.y, last.y)
+				switch {
+				case p.primalInfeasible(dy):
+					*scf.Status = common.SolverStatusInfeasible
+					scf.FarkasRay = unscaleRay(dy, rowScale)
+					return nil
+				case p.dualInfeasible(dx):
+					// An improving ray only proves unboundedness alongside a
+					// feasible point; without one the problem may be
+					// infeasible, which is left to the simplex method
+					if !orig.primalFeasible(orig.unscale(cur, rowScale, colScale), config.Tolerance) {
+						return nil
+					}
+					*scf.Status = common.SolverStatusUnbounded
+					scf.UnboundedRay = unscaleRay(dx, colScale)
+					return nil
+				}
+
+				kkt := p.kktError(candidate, omega)
+				if kkt <= sufficientReduction*lastKKT ||
+					(kkt <= necessaryReduction*lastKKT && kkt > prevKKT) ||
+					float64(count) >= artificialRestart*float64(iterations) {
+					cur = candidate.clone()
+					omega = updateWeight(omega, cur, last)
+					last = cur.clone()
+					sum = iterate{x: make([]float64, p.n), y: make([]float64, p.m)}
+					count = 0
+					lastKKT = p.kktError(cur, omega)
+					kkt = math.Inf(1)
+				}
+				prevKKT = kkt
+			}
+		}
+
+		stop := common.StopStatus(config.Ctx)
+		if config.MaxIterations > 0 && iterations >= config.MaxIterations*iterationsPerPivot {
+			stop = common.SolverStatusIterationLimit
+		}
+		if stop != common.SolverStatusNotSolved {
+			orig.store(scf, orig.unscale(cur, rowScale, colScale), stop)
+			return nil
+		}
+		iterations++
+
+		// x' = proj(x - tau (c - A^T y)), y' = y + sigma (b - A (2x' - x))
+		tau, sigma := eta/omega, eta*omega
+		p.mulTrans(cur.y, aty)
+		for j := range p.n {
+			next.x[j] = clamp(cur.x[j]-tau*(p.c[j]-aty[j]), p.lower[j], p.upper[j])
+			extrapolated[j] = 2*next.x[j] - cur.x[j]
+		}
+		p.mul(extrapolated, ax)
+		for i := range p.m {
+			next.y[i] = cur.y[i] + sigma*(p.b[i]-ax[i])
+		}
+		copy(cur.x, next.x)
+		copy(cur.y, next.y)
+
+		floats.Add(sum.x, cur.x)
+		floats.Add(sum.y, cur.y)
+		count++
+	}
+}
+
+// newProblem extracts the data of scf. It reports false when a column has
+// inconsistent bounds.
+func newProblem(scf *common.StandardComputationalForm, tol float64) (*problem, bool) {
+	m, n := scf.Constraints.Dims()
+	p := &problem{
+		A:     matrix.AsCSC(scf.Constraints),
+		b:     make([]float64, m),
+		c:     make([]float64, n),
+		lower: make([]float64, n),
+		upper: make([]float64, n),
+		m:     m,
+		n:     n,
+	}
+	for i := range m {
+		p.b[i] = scf.RHS.AtVec(i)
+	}
+	for j := range n {
+		p.c[j] = scf.Objective.AtVec(j)
+		p.lower[j], p.upper[j] = scf.Bounds(j)
+		if p.lower[j] > p.upper[j]+tol {
+			return nil, false
+		}
+	}
+	return p, true
+}
+
+// mul sets dst = A x.
+func (p *problem) mul(x, dst []float64) {
+	for i := range dst {
+		dst[i] = 0
+	}
+	for j := range p.n {
+		if x[j] == 0 {
+			continue
+		}
+		rows, vals := p.A.Col(j)
+		for k, i := range rows {
+			dst[i] += vals[k] * x[j]
+		}
+	}
+}
+
+// mulTrans sets dst = A^T y.
+func (p *problem) mulTrans(y, dst []float64) {
+	for j := range p.n {
+		rows, vals := p.A.Col(j)
+		dot := 0.
+		for k, i := range rows {
+			dot += vals[k] * y[i]
+		}
+		dst[j] = dot
+	}
+}
+
+// residuals returns the residuals of the pair it. The reduced cost of each
+// column is split into the part its bound duals absorb, which enters the dual
+// objective, and the part that no bound can absorb, which is dual infeasible.
+func (p *problem) residuals(it iterate) residuals {
+	var r residuals
+	ax := make([]float64, p.m)
+	p.mul(it.x, ax)
+	for i := range p.m {
+		d := ax[i] - p.b[i]
+		r.primal += d * d
+		r.dobj += p.b[i] * it.y[i]
+	}
+	r.primal = math.Sqrt(r.primal)
+
+	aty := make([]float64, p.n)
+	p.mulTrans(it.y, aty)
+	for j := range p.n {
+		r.pobj += p.c[j] * it.x[j]
+		d := p.c[j] - aty[j]
+		hasLower, hasUpper := !math.IsInf(p.lower[j], -1), !math.IsInf(p.upper[j], 1)
+		switch {
+		case d > 0 && hasLower:
+			r.dobj += p.lower[j] * d
+		case d < 0 && hasUpper:
+			r.dobj += p.upper[j] * d
+		default:
+			r.dual += d * d
+		}
+	}
+	r.dual = math.Sqrt(r.dual)
+	return r
+}
+
+// kktError combines the residuals of it into one measure, weighting the
+// primal and dual parts by the primal weight omega as PDLP does.
+func (p *problem) kktError(it iterate, omega float64) float64 {
+	r := p.residuals(it)
+	gap := r.pobj - r.dobj
+	return math.Sqrt(omega*r.primal*r.primal + r.dual*r.dual/omega + gap*gap)
+}
+
+// converged reports whether it solves p to the relative tolerance tol.
+func (p *problem) converged(it iterate, tol float64) bool {
+	r := p.residuals(it)
+	return r.primal <= tol*(1+floats.Norm(p.b, 2)) &&
+		r.dual <= tol*(1+floats.Norm(p.c, 2)) &&
+		math.Abs(r.pobj-r.dobj) <= tol*(1+math.Abs(r.pobj)+math.Abs(r.dobj))
+}
+
+// primalFeasible reports whether the primal part of it meets the constraints
+// of p to the relative tolerance tol. Its bounds hold after unscale.
+func (p *problem) primalFeasible(it iterate, tol float64) bool {
+	return p.residuals(it).primal <= tol*(1+floats.Norm(p.b, 2))
+}
+
+// primalInfeasible reports whether the dual step dy is, once normalised, a
+// Farkas certificate: a y with b^T y greater than the largest value of
+// (A^T y)^T x over the bounds.
+func (p *problem) primalInfeasible(dy []float64) bool {
+	norm := floats.Norm(dy, 2)
+	if norm == 0 {
+		return false
+	}
+	q := make([]float64, p.n)
+	p.mulTrans(dy, q)
+
+	obj := floats.Dot(p.b, dy) / norm
+	violation := 0.
+	for j := range p.n {
+		v := q[j] / norm
+		switch {
+		case v > 0 && !math.IsInf(p.upper[j], 1):
+			obj -= v * p.upper[j]
+		case v < 0 && !math.IsInf(p.lower[j], -1):
+			obj -= v * p.lower[j]
+		default:
+			violation = math.Max(violation, math.Abs(v))
+		}
+	}
+	return obj > 0 && violation <= infeasibilityTolerance*obj
+}
+
+// dualInfeasible reports whether the primal step dx is, once normalised, an
+// improving ray: a direction d with Ad = 0 and c^T d < 0 that no bound stops.
+func (p *problem) dualInfeasible(dx []float64) bool {
+	norm := floats.Norm(dx, 2)
+	if norm == 0 {
+		return false
+	}
+	improvement := -floats.Dot(p.c, dx) / norm
+	if improvement <= 0 {
+		return false
+	}
+
+	ad := make([]float64, p.m)
+	p.mul(dx, ad)
+	violation := floats.Norm(ad, math.Inf(1)) / norm
+	for j := range p.n {
+		d := dx[j] / norm
+		if (d > 0 && !math.IsInf(p.upper[j], 1)) || (d < 0 && !math.IsInf(p.lower[j], -1)) {
+			violation = math.Max(violation, math.Abs(d))
+		}
+	}
+	return violation <= infeasibilityTolerance*improvement
+}
+
+// unscale maps a point of the preconditioned problem back onto p, clamping x
+// into the bounds of p.
+func (p *problem) unscale(it iterate, rowScale, colScale []float64) iterate {
+	out := iterate{x: make([]float64, p.n), y: make([]float64, p.m)}
+	for j := range p.n {
+		out.x[j] = clamp(it.x[j]*colScale[j], p.lower[j], p.upper[j])
+	}
+	for i := range p.m {
+		out.y[i] = it.y[i] * rowScale[i]
+	}
+	return out
+}
+
+// store writes it to scf with the given status. Only an optimal point carries
+// duals and reduced costs.
+func (p *problem) store(scf *common.StandardComputationalForm, it iterate, status common.SolverStatus) {
+	*scf.Status = status
+	scf.PrimalSolution = mat.NewVecDense(p.n, it.x)
+	*scf.ObjectiveValue = floats.Dot(p.c, it.x)
+	scf.Basis = nil
+	scf.AtUpper = nil
+	if status != common.SolverStatusOptimal {
+		return
+	}
+
+	aty := make([]float64, p.n)
+	p.mulTrans(it.y, aty)
+	floats.SubTo(aty, p.c, aty)
+	scf.DualSolution = mat.NewVecDense(p.m, it.y)
+	scf.ReducedCosts = mat.NewVecDense(p.n, aty)
+}
+
+// operatorNorm estimates the spectral norm of A by power iteration on A^T A.
+func operatorNorm(A *matrix.CSC) float64 {
+	m, n := A.Dims()
+	p := &problem{A: A, m: m, n: n}
+	v := make([]float64, n)
+	for j := range v {
+		v[j] = 1 / math.Sqrt(float64(n))
+	}
+	av := make([]float64, m)
+	lambda := 0.
+	for range powerIterations {
+		p.mul(v, av)
+		p.mulTrans(av, v)
+		lambda = floats.Norm(v, 2)
+		if lambda == 0 {
+			return 1
+		}
+		floats.Scale(1/lambda, v)
+	}
+	return math.Sqrt(lambda)
+}
+
+// updateWeight re-estimates the primal weight from how far the dual and
+// primal iterates moved since the last restart, smoothed in log space.
+func updateWeight(omega float64, cur, last iterate) float64 {
+	dx := floats.Distance(cur.x, last.x, 2)
+	dy := floats.Distance(cur.y, last.y, 2)
+	if dx < 1e-10 || dy < 1e-10 {
+		return omega
+	}
+	return math.Exp(primalWeightSmoothing*math.Log(dy/dx) + (1-primalWeightSmoothing)*math.Log(omega))
+}
+
+func (it iterate) clone() iterate {
+	return iterate{x: append([]float64(nil), it.x...), y: append([]float64(nil), it.y...)}
+}
+
+// scaled returns a copy of it multiplied by s.
+func (it iterate) scaled(s float64) iterate {
+	out := it.clone()
+	floats.Scale(s, out.x)
+	floats.Scale(s, out.y)
+	return out
+}
+
+// unscaleRay maps a ray of the preconditioned problem back onto the original
+// one, normalised to unit length.
+func unscaleRay(ray, scale []float64) *mat.VecDense {
+	out := make([]float64, len(ray))
+	floats.MulTo(out, ray, scale)
+	floats.Scale(1/floats.Norm(out, 2), out)
+	return mat.NewVecDense(len(out), out)
+}
+
+func difference(a, b []float64) []float64 {
+	d := make([]float64, len(a))
+	floats.SubTo(d, a, b)
+	return d
+}
+
+func clamp(v, lower, upper float64) float64 {
+	return math.Min(math.Max(v, lower), upper)
+}
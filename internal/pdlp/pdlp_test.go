@@ -0,0 +1,131 @@
+package pdlp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chriso345/gore/assert"
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/matrix"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSolve(t *testing.T) {
+	// Transportation from supplies of 5 and 6 to demands of 6 and 4
+	// Minimize: x11 + 3*x12 + 4*x21 + 2*x22
+	// Subject to: x11 + x12 <= 5, x21 + x22 <= 6, x11 + x21 = 6, x12 + x22 = 4
+	scf := common.NewSCF([]float64{1, 3, 4, 2, 0, 0}, mat.NewDense(4, 6, []float64{
+		1, 1, 0, 0, 1, 0,
+		0, 0, 1, 1, 0, 1,
+		1, 0, 1, 0, 0, 0,
+		0, 1, 0, 1, 0, 0,
+	}), []float64{5, 6, 6, 4})
+
+	assert.Nil(t, Solve(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, *scf.ObjectiveValue, 17, 1e-4)
+	for j, want := range []float64{5, 0, 1, 4} {
+		assert.IsClose(t, scf.PrimalSolution.AtVec(j), want, 1e-4)
+	}
+	assert.IsClose(t, scf.DualSolution.AtVec(0), -3, 1e-4)
+	assert.IsClose(t, scf.DualSolution.AtVec(1), 0, 1e-4)
+	assert.True(t, scf.Basis == nil)
+}
+
+func TestSolveBounds(t *testing.T) {
+	// Minimize: -x1 - 2*x2 + x3
+	// Subject to: x1 + x2 + s = 10, x3 - x1 = -2,
+	//             0 <= x1 <= 3, -1 <= x2 <= 4, x3 free
+	inf := math.Inf(1)
+	scf := common.NewSCF([]float64{-1, -2, 1, 0}, mat.NewDense(2, 4, []float64{
+		1, 1, 0, 1,
+		-1, 0, 1, 0,
+	}), []float64{10, -2})
+	scf.Lower = mat.NewVecDense(4, []float64{0, -1, -inf, 0})
+	scf.Upper = mat.NewVecDense(4, []float64{3, 4, inf, inf})
+
+	// x3 = x1 - 2, so the objective is -2*x2 - 2 at its optimum x2 = 4
+	assert.Nil(t, Solve(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, *scf.ObjectiveValue, -10, 1e-4)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(1), 4, 1e-4)
+}
+
+func TestSolveInfeasible(t *testing.T) {
+	// x1 + x2 = -1 with x >= 0
+	scf := common.NewSCF([]float64{1, 1}, mat.NewDense(1, 2, []float64{1, 1}), []float64{-1})
+	assert.Nil(t, Solve(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, *scf.Status, common.SolverStatusInfeasible)
+	assert.NotNil(t, scf.FarkasRay)
+	assert.True(t, scf.FarkasRay.AtVec(0) < 0)
+
+	scf = common.NewSCF([]float64{1}, mat.NewDense(1, 1, []float64{1}), []float64{1})
+	scf.Lower = mat.NewVecDense(1, []float64{2})
+	scf.Upper = mat.NewVecDense(1, []float64{1})
+	assert.Nil(t, Solve(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, *scf.Status, common.SolverStatusInfeasible)
+}
+
+func TestSolveUnbounded(t *testing.T) {
+	// Minimize -x1 subject to x1 - x2 = 1
+	scf := common.NewSCF([]float64{-1, 0}, mat.NewDense(1, 2, []float64{1, -1}), []float64{1})
+	assert.Nil(t, Solve(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, *scf.Status, common.SolverStatusUnbounded)
+	assert.NotNil(t, scf.UnboundedRay)
+	assert.True(t, scf.UnboundedRay.AtVec(0) > 0)
+	assert.IsClose(t, scf.UnboundedRay.AtVec(0), scf.UnboundedRay.AtVec(1), 1e-4)
+}
+
+func TestSolveRayWithoutFeasiblePoint(t *testing.T) {
+	// Minimize: 3*x1 - x2 - 5*x3 - 3*x4
+	// Subject to: -3*x1 - x2 - x4 = -1, x1 - 2*x4 = 2, -x1 + 3*x2 = 5
+	// The objective improves along x3 without limit, but the first and last
+	// rows need x2 <= 1 and x2 >= 5/3, so no point is feasible
+	scf := common.NewSCF([]float64{3, -1, -5, -3}, mat.NewDense(3, 4, []float64{
+		-3, -1, 0, -1,
+		1, 0, 0, -2,
+		-1, 3, 0, 0,
+	}), []float64{-1, 2, 5})
+	assert.Nil(t, Solve(scf, common.DefaultSolverConfig()))
+	assert.NotEqual(t, *scf.Status, common.SolverStatusUnbounded)
+	assert.True(t, scf.UnboundedRay == nil)
+}
+
+func TestSolveIterationLimit(t *testing.T) {
+	scf := common.NewSCF([]float64{1, 3, 4, 2, 0, 0}, mat.NewDense(4, 6, []float64{
+		1, 1, 0, 0, 1, 0,
+		0, 0, 1, 1, 0, 1,
+		1, 0, 1, 0, 0, 0,
+		0, 1, 0, 1, 0, 0,
+	}), []float64{5, 6, 6, 4})
+	config := common.DefaultSolverConfig()
+	config.MaxIterations = 1
+
+	assert.Nil(t, Solve(scf, config))
+	assert.Equal(t, *scf.Status, common.SolverStatusIterationLimit)
+	assert.NotNil(t, scf.PrimalSolution)
+	assert.True(t, scf.DualSolution == nil)
+}
+
+func TestPrecondition(t *testing.T) {
+	p := &problem{
+		A:     matrix.CSCFromMatrix(mat.NewDense(2, 2, []float64{1000, 1, 0, 0.001})),
+		b:     []float64{1, 1},
+		c:     []float64{1, 1},
+		lower: []float64{0, 0},
+		upper: []float64{math.Inf(1), 4},
+		m:     2,
+		n:     2,
+	}
+	scaled, rowScale, colScale := precondition(p)
+
+	// Every entry of the scaled matrix is within a small factor of one
+	for j := range 2 {
+		_, vals := scaled.A.Col(j)
+		for _, v := range vals {
+			assert.True(t, math.Abs(v) > 0.1 && math.Abs(v) < 10)
+		}
+	}
+	assert.Equal(t, scaled.b[1], rowScale[1])
+	assert.Equal(t, scaled.upper[1], 4/colScale[1])
+}
@@ -0,0 +1,103 @@
+package pdlp
+
+import (
+	"math"
+
+	"github.com/chriso345/gspl/internal/matrix"
+)
+
+// ruizIterations is the number of rounds of Ruiz equilibration applied before
+// the Pock-Chambolle scaling.
+const ruizIterations = 10
+
+// precondition returns p rescaled to R*A*C, with c, b and the bounds scaled
+// to match, together with the row scales R and column scales C. A point x, y
+// of the scaled problem corresponds to C*x, R*y of p.
+func precondition(p *problem) (*problem, []float64, []float64) {
+	rowScale := make([]float64, p.m)
+	colScale := make([]float64, p.n)
+	for i := range rowScale {
+		rowScale[i] = 1
+	}
+	for j := range colScale {
+		colScale[j] = 1
+	}
+
+	A := p.A
+	r := make([]float64, p.m)
+	c := make([]float64, p.n)
+
+	// Ruiz equilibration drives the largest entry of every row and column
+	// towards one
+	for range ruizIterations {
+		for i := range r {
+			r[i] = 0
+		}
+		for j := range p.n {
+			rows, vals := A.Col(j)
+			c[j] = 0
+			for k, i := range rows {
+				v := math.Abs(vals[k])
+				r[i] = math.Max(r[i], v)
+				c[j] = math.Max(c[j], v)
+			}
+		}
+		A = rescale(A, r, c, rowScale, colScale)
+	}
+
+	// Pock-Chambolle scaling with alpha = 1 balances the row and column sums
+	for i := range r {
+		r[i] = 0
+	}
+	for j := range p.n {
+		rows, vals := A.Col(j)
+		c[j] = 0
+		for k, i := range rows {
+			v := math.Abs(vals[k])
+			r[i] += v
+			c[j] += v
+		}
+	}
+	A = rescale(A, r, c, rowScale, colScale)
+
+	scaled := &problem{
+		A:     A,
+		b:     make([]float64, p.m),
+		c:     make([]float64, p.n),
+		lower: make([]float64, p.n),
+		upper: make([]float64, p.n),
+		m:     p.m,
+		n:     p.n,
+	}
+	for i := range p.m {
+		scaled.b[i] = p.b[i] * rowScale[i]
+	}
+	for j := range p.n {
+		scaled.c[j] = p.c[j] * colScale[j]
+		scaled.lower[j] = p.lower[j] / colScale[j]
+		scaled.upper[j] = p.upper[j] / colScale[j]
+	}
+	return scaled, rowScale, colScale
+}
+
+// rescale divides every row and column of A by the square root of its norm
+// in r and c, folding the factors into rowScale and colScale. Empty rows and
+// columns are left alone.
+func rescale(A *matrix.CSC, r, c, rowScale, colScale []float64) *matrix.CSC {
+	for i := range r {
+		r[i] = invSqrt(r[i])
+		rowScale[i] *= r[i]
+	}
+	for j := range c {
+		c[j] = invSqrt(c[j])
+		colScale[j] *= c[j]
+	}
+	return matrix.CSCScale(A, r, c)
+}
+
+func invSqrt(v float64) float64 {
+	if v == 0 {
+		return 1
+	}
+	return 1 / math.Sqrt(v)
+}
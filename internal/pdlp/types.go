@@ -0,0 +1,31 @@
+package pdlp
+
+import "github.com/chriso345/gspl/internal/matrix"
+
+// problem is the linear program min c^T x subject to Ax = b and l <= x <= u,
+// held as plain slices for the iteration.
+type problem struct {
+	A *matrix.CSC
+	b []float64
+	c []float64
+
+	lower []float64
+	upper []float64
+
+	m int
+	n int
+}
+
+// residuals measures how far a primal-dual pair is from optimality.
+type residuals struct {
+	primal float64 // ||Ax - b||
+	dual   float64 // Part of c - A^T y that no bound dual can absorb
+	pobj   float64 // c^T x
+	dobj   float64 // b^T y plus the bound terms of the reduced costs
+}
+
+// iterate is a primal-dual pair.
+type iterate struct {
+	x []float64
+	y []float64
+}
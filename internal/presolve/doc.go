@@ -0,0 +1,5 @@
+// Package presolve reduces a linear program in standard computational form
+// before it is solved and maps the solution back onto the original problem.
+//
+// This package is internal and intended for use within the gspl project only.
+package presolve
@@ -0,0 +1,101 @@
+package presolve
+
+import (
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/matrix"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Postsolve writes the outcome onto the original problem. When Reduced is
+// non-nil it must have been solved; an optimal solution is expanded into the
+// primal and dual solutions, reduced costs and basis of the original problem,
+// or without a basis when the reduced problem was solved to an interior point.
+// A solve stopped by a limit only has its last point expanded.
+func (p *Presolved) Postsolve() {
+	status := p.status
+	if p.Reduced != nil {
+		status = *p.Reduced.Status
+	}
+	if p.unbounded && status == common.SolverStatusOptimal {
+		status = common.SolverStatusUnbounded
+	}
+
+	scf := p.original
+	*scf.Status = status
+	if status != common.SolverStatusOptimal && !status.Stopped() {
+		return
+	}
+
+	m, n := p.A.Dims()
+	x := mat.NewVecDense(n, p.x)
+	y := mat.NewVecDense(m, nil)
+	atUpper := make([]bool, n)
+	basis := make([]int, 0, m)
+
+	if p.Reduced != nil {
+		for jr, j := range p.colMap {
+			x.SetVec(j, p.Reduced.PrimalSolution.AtVec(jr))
+		}
+	}
+	if status.Stopped() {
+		*scf.ObjectiveValue = mat.Dot(scf.Objective, x)
+		scf.PrimalSolution = x
+		return
+	}
+
+	// An interior point solution has no basis to expand
+	interior := p.Reduced != nil && p.Reduced.Basis == nil
+
+	if p.Reduced != nil {
+		nr := len(p.colMap)
+		if !interior {
+			for jr, j := range p.colMap {
+				atUpper[j] = p.Reduced.AtUpper[jr]
+			}
+		}
+		for ir, i := range p.rowMap {
+			y.SetVec(i, p.Reduced.DualSolution.AtVec(ir))
+		}
+		for _, jr := range p.Reduced.Basis {
+			if jr < nr {
+				basis = append(basis, p.colMap[jr])
+			} else {
+				basis = append(basis, n+p.rowMap[jr-nr])
+			}
+		}
+	}
+
+	// Undo the reductions newest first. A row removed before a singleton row
+	// has no entry in the singleton's column, except duplicate rows whose dual
+	// is zero, so every dual the formula needs is already known.
+	for k := len(p.ops) - 1; k >= 0; k-- {
+		op := p.ops[k]
+		switch op.kind {
+		case opFixColumn:
+			atUpper[op.col] = op.atUpper
+		case opEmptyRow, opDuplicateRow:
+			basis = append(basis, n+op.row)
+		case opSingletonRow:
+			// The fixed column becomes basic in its row with a zero reduced cost
+			a := p.A.At(op.row, op.col)
+			d := scf.Objective.AtVec(op.col) - matrix.ColDot(p.A, op.col, y)
+			y.SetVec(op.row, d/a)
+			basis = append(basis, op.col)
+		}
+	}
+
+	reducedCosts := mat.NewVecDense(n, nil)
+	for j := range n {
+		reducedCosts.SetVec(j, scf.Objective.AtVec(j)-matrix.ColDot(p.A, j, y))
+	}
+
+	*scf.ObjectiveValue = mat.Dot(scf.Objective, x)
+	scf.PrimalSolution = x
+	scf.DualSolution = y
+	scf.ReducedCosts = reducedCosts
+	scf.Basis = basis
+	scf.AtUpper = atUpper
+	if interior {
+		scf.Basis, scf.AtUpper = nil, nil
+	}
+}
@@ -0,0 +1,467 @@
+package presolve
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/matrix"
+	"gonum.org/v1/gonum/mat"
+)
+
+// presolver is the working state of a presolve pass. Rows and columns are
+// never moved; removing one only clears its active flag.
+type presolver struct {
+	*Presolved
+
+	m, n int
+	tol  float64
+
+	rowCols [][]int // Row-wise copy of A
+	rowVals [][]float64
+
+	rowActive []bool
+	colActive []bool
+	rowCount  []int // Active entries in each row
+	colCount  []int // Active entries in each column
+
+	b     []float64 // RHS less the contribution of removed columns
+	c     []float64
+	lower []float64
+	upper []float64
+}
+
+// Presolve applies the reductions to scf until none applies: it removes empty
+// rows and columns, fixed columns, singleton rows, duplicate rows and
+// dominated columns, and detects trivial infeasibility and unboundedness.
+// Each reduction is recorded so that postsolve can restore the primal values,
+// dual values, reduced costs and an optimal basis. scf is not modified; call
+// Postsolve once Reduced has been solved to write the solution back onto it.
+func Presolve(scf *common.StandardComputationalForm, tol float64) *Presolved {
+	A := matrix.AsCSC(scf.Constraints)
+	m, n := A.Dims()
+
+	p := &presolver{
+		Presolved: &Presolved{
+			original: scf,
+			A:        A,
+			status:   common.SolverStatusNotSolved,
+			x:        make([]float64, n),
+		},
+		m:         m,
+		n:         n,
+		tol:       tol,
+		rowCols:   make([][]int, m),
+		rowVals:   make([][]float64, m),
+		rowActive: make([]bool, m),
+		colActive: make([]bool, n),
+		rowCount:  make([]int, m),
+		colCount:  make([]int, n),
+		b:         make([]float64, m),
+		c:         make([]float64, n),
+		lower:     make([]float64, n),
+		upper:     make([]float64, n),
+	}
+
+	for i := range m {
+		p.rowActive[i] = true
+		p.b[i] = scf.RHS.AtVec(i)
+	}
+	for j := range n {
+		p.colActive[j] = true
+		p.c[j] = scf.Objective.AtVec(j)
+		p.lower[j], p.upper[j] = scf.Bounds(j)
+		rows, vals := A.Col(j)
+		p.colCount[j] = len(rows)
+		for k, i := range rows {
+			p.rowCols[i] = append(p.rowCols[i], j)
+			p.rowVals[i] = append(p.rowVals[i], vals[k])
+			p.rowCount[i]++
+		}
+	}
+
+	for p.status == common.SolverStatusNotSolved && p.pass() {
+	}
+
+	if p.status == common.SolverStatusNotSolved {
+		p.buildReduced()
+	}
+	return p.Presolved
+}
+
+// pass applies every reduction once and reports whether anything changed.
+func (p *presolver) pass() bool {
+	changed := p.removeColumns()
+	changed = p.removeRows() || changed
+	if p.status != common.SolverStatusNotSolved {
+		return false
+	}
+	p.checkActivity()
+	if p.status != common.SolverStatusNotSolved {
+		return false
+	}
+	changed = p.removeDominatedColumns() || changed
+	changed = p.removeDuplicateRows() || changed
+	return changed
+}
+
+// fixColumn removes column j at value, moving its contribution to the RHS.
+func (p *presolver) fixColumn(j int, value float64) {
+	p.colActive[j] = false
+	p.x[j] = value
+	p.ColsRemoved++
+	rows, vals := p.A.Col(j)
+	for k, i := range rows {
+		if p.rowActive[i] {
+			p.b[i] -= vals[k] * value
+			p.rowCount[i]--
+		}
+	}
+}
+
+// removeRow removes row i from the active problem.
+func (p *presolver) removeRow(i int) {
+	p.rowActive[i] = false
+	p.RowsRemoved++
+	for _, j := range p.rowCols[i] {
+		if p.colActive[j] {
+			p.colCount[j]--
+		}
+	}
+}
+
+// removeColumns removes fixed and empty columns.
+func (p *presolver) removeColumns() bool {
+	changed := false
+	for j := range p.n {
+		if !p.colActive[j] {
+			continue
+		}
+		lower, upper := p.lower[j], p.upper[j]
+		if lower > upper+p.tol {
+			p.status = common.SolverStatusInfeasible
+			return false
+		}
+
+		switch {
+		case upper-lower <= p.tol:
+			p.fixColumn(j, lower)
+			p.ops = append(p.ops, operation{kind: opFixColumn, col: j})
+		case p.colCount[j] == 0:
+			// An empty column only affects the objective: it moves to its best bound
+			value, atUpper := 0., false
+			switch {
+			case p.c[j] > p.tol:
+				value = lower
+			case p.c[j] < -p.tol:
+				value, atUpper = upper, true
+			case !math.IsInf(lower, -1):
+				value = lower
+			case !math.IsInf(upper, 1):
+				value, atUpper = upper, true
+			}
+			if math.IsInf(value, 0) {
+				p.unbounded = true
+				value, atUpper = 0, false
+				if !math.IsInf(lower, -1) {
+					value = lower
+				} else if !math.IsInf(upper, 1) {
+					value, atUpper = upper, true
+				}
+			}
+			p.fixColumn(j, value)
+			p.ops = append(p.ops, operation{kind: opFixColumn, col: j, atUpper: atUpper})
+		default:
+			continue
+		}
+		changed = true
+	}
+	return changed
+}
+
+// removeRows removes empty rows and turns singleton rows into fixed columns.
+func (p *presolver) removeRows() bool {
+	changed := false
+	for i := range p.m {
+		if !p.rowActive[i] {
+			continue
+		}
+		switch p.rowCount[i] {
+		case 0:
+			if math.Abs(p.b[i]) > p.tol {
+				p.status = common.SolverStatusInfeasible
+				return false
+			}
+			p.removeRow(i)
+			p.ops = append(p.ops, operation{kind: opEmptyRow, row: i})
+		case 1:
+			// a_ik x_k = b_i fixes x_k
+			k, a := p.activeEntry(i)
+			value := p.b[i] / a
+			if value < p.lower[k]-p.tol || value > p.upper[k]+p.tol {
+				p.status = common.SolverStatusInfeasible
+				return false
+			}
+			value = math.Min(math.Max(value, p.lower[k]), p.upper[k])
+			p.removeRow(i)
+			p.fixColumn(k, value)
+			p.ops = append(p.ops, operation{kind: opSingletonRow, row: i, col: k})
+		default:
+			continue
+		}
+		changed = true
+	}
+	return changed
+}
+
+// activeEntry returns the first active column of row i and its coefficient.
+func (p *presolver) activeEntry(i int) (int, float64) {
+	for k, j := range p.rowCols[i] {
+		if p.colActive[j] {
+			return j, p.rowVals[i][k]
+		}
+	}
+	return -1, 0
+}
+
+// checkActivity declares the problem infeasible when some row cannot reach
+// its RHS within the column bounds.
+func (p *presolver) checkActivity() {
+	for i := range p.m {
+		if !p.rowActive[i] {
+			continue
+		}
+		minAct, maxAct := 0., 0.
+		for k, j := range p.rowCols[i] {
+			if !p.colActive[j] {
+				continue
+			}
+			a := p.rowVals[i][k]
+			lo, hi := a*p.lower[j], a*p.upper[j]
+			if a < 0 {
+				lo, hi = hi, lo
+			}
+			minAct += lo
+			maxAct += hi
+		}
+		slack := p.tol * (1 + math.Abs(p.b[i]))
+		if minAct > p.b[i]+slack || maxAct < p.b[i]-slack {
+			p.status = common.SolverStatusInfeasible
+			return
+		}
+	}
+}
+
+// removeDominatedColumns fixes columns whose reduced cost has a known sign
+// at every dual feasible point. Singleton columns bound the duals of their
+// rows: a column with a_ik > 0, cost c_k and no upper bound needs
+// c_k - a_ik y_i >= 0, so y_i <= c_k / a_ik, and similarly for the other signs.
+func (p *presolver) removeDominatedColumns() bool {
+	yLower := make([]float64, p.m)
+	yUpper := make([]float64, p.m)
+	for i := range p.m {
+		yLower[i], yUpper[i] = math.Inf(-1), math.Inf(1)
+	}
+	for j := range p.n {
+		if !p.colActive[j] || p.colCount[j] != 1 {
+			continue
+		}
+		i, a := p.activeRow(j)
+		bound := p.c[j] / a
+		// No upper bound: a y_i <= c_j. No lower bound: a y_i >= c_j.
+		if math.IsInf(p.upper[j], 1) {
+			if a > 0 {
+				yUpper[i] = math.Min(yUpper[i], bound)
+			} else {
+				yLower[i] = math.Max(yLower[i], bound)
+			}
+		}
+		if math.IsInf(p.lower[j], -1) {
+			if a > 0 {
+				yLower[i] = math.Max(yLower[i], bound)
+			} else {
+				yUpper[i] = math.Min(yUpper[i], bound)
+			}
+		}
+	}
+
+	for i := range p.m {
+		if yLower[i] > yUpper[i] {
+			return false // No dual feasible point, leave it to the simplex method
+		}
+	}
+
+	changed := false
+	for j := range p.n {
+		if !p.colActive[j] {
+			continue
+		}
+		// Range of sum_i a_ij y_i over the dual box
+		sumMin, sumMax := 0., 0.
+		rows, vals := p.A.Col(j)
+		for k, i := range rows {
+			if !p.rowActive[i] {
+				continue
+			}
+			lo, hi := vals[k]*yLower[i], vals[k]*yUpper[i]
+			if vals[k] < 0 {
+				lo, hi = hi, lo
+			}
+			sumMin += lo
+			sumMax += hi
+		}
+		dMin, dMax := p.c[j]-sumMax, p.c[j]-sumMin
+
+		switch {
+		case dMin > p.tol && !math.IsInf(p.lower[j], -1):
+			p.fixColumn(j, p.lower[j])
+			p.ops = append(p.ops, operation{kind: opFixColumn, col: j})
+		case dMax < -p.tol && !math.IsInf(p.upper[j], 1):
+			p.fixColumn(j, p.upper[j])
+			p.ops = append(p.ops, operation{kind: opFixColumn, col: j, atUpper: true})
+		default:
+			continue
+		}
+		changed = true
+	}
+	return changed
+}
+
+// activeRow returns the first active row of column j and its coefficient.
+func (p *presolver) activeRow(j int) (int, float64) {
+	rows, vals := p.A.Col(j)
+	for k, i := range rows {
+		if p.rowActive[i] {
+			return i, vals[k]
+		}
+	}
+	return -1, 0
+}
+
+// removeDuplicateRows removes rows that are a multiple of an earlier row,
+// or declares infeasibility when their right-hand sides disagree.
+func (p *presolver) removeDuplicateRows() bool {
+	changed := false
+	seen := make(map[string]int)
+	for i := range p.m {
+		if !p.rowActive[i] || p.rowCount[i] < 2 {
+			continue
+		}
+
+		// Key the row by its pattern scaled so that the first entry is one
+		var key strings.Builder
+		first := 0.
+		for k, j := range p.rowCols[i] {
+			if !p.colActive[j] {
+				continue
+			}
+			if first == 0 {
+				first = p.rowVals[i][k]
+			}
+			fmt.Fprintf(&key, "%d:%.10g;", j, p.rowVals[i][k]/first)
+		}
+
+		r, ok := seen[key.String()]
+		if !ok {
+			seen[key.String()] = i
+			continue
+		}
+
+		// Row i is ratio times row r, up to the rounding in the key
+		_, firstR := p.activeEntry(r)
+		ratio := first / firstR
+		if !p.proportional(i, r, ratio) {
+			continue
+		}
+		if math.Abs(p.b[i]-ratio*p.b[r]) > p.tol*(1+math.Abs(p.b[i])) {
+			p.status = common.SolverStatusInfeasible
+			return false
+		}
+		p.removeRow(i)
+		p.ops = append(p.ops, operation{kind: opDuplicateRow, row: i})
+		changed = true
+	}
+	return changed
+}
+
+// proportional reports whether the active entries of row i are ratio times
+// those of row r. Both rows must share the same active pattern.
+func (p *presolver) proportional(i, r int, ratio float64) bool {
+	values := make(map[int]float64, p.rowCount[r])
+	for k, j := range p.rowCols[r] {
+		if p.colActive[j] {
+			values[j] = p.rowVals[r][k]
+		}
+	}
+	for k, j := range p.rowCols[i] {
+		if !p.colActive[j] {
+			continue
+		}
+		want := ratio * values[j]
+		if math.Abs(p.rowVals[i][k]-want) > p.tol*(1+math.Abs(want)) {
+			return false
+		}
+	}
+	return true
+}
+
+// buildReduced assembles the remaining rows and columns into Reduced.
+func (p *presolver) buildReduced() {
+	newRow := make([]int, p.m)
+	for i := range p.m {
+		newRow[i] = -1
+		if p.rowActive[i] {
+			newRow[i] = len(p.rowMap)
+			p.rowMap = append(p.rowMap, i)
+		}
+	}
+	for j := range p.n {
+		if p.colActive[j] {
+			p.colMap = append(p.colMap, j)
+		}
+	}
+
+	mr, nr := len(p.rowMap), len(p.colMap)
+	if mr == 0 || nr == 0 {
+		// Every row and column was removed, so presolve solved the problem
+		p.status = common.SolverStatusOptimal
+		return
+	}
+
+	colRows := make([][]int, nr)
+	colVals := make([][]float64, nr)
+	objective := mat.NewVecDense(nr, nil)
+	lower := mat.NewVecDense(nr, nil)
+	upper := mat.NewVecDense(nr, nil)
+	for jr, j := range p.colMap {
+		rows, vals := p.A.Col(j)
+		for k, i := range rows {
+			if p.rowActive[i] {
+				colRows[jr] = append(colRows[jr], newRow[i])
+				colVals[jr] = append(colVals[jr], vals[k])
+			}
+		}
+		objective.SetVec(jr, p.c[j])
+		lower.SetVec(jr, p.lower[j])
+		upper.SetVec(jr, p.upper[j])
+	}
+	rhs := mat.NewVecDense(mr, nil)
+	for ir, i := range p.rowMap {
+		rhs.SetVec(ir, p.b[i])
+	}
+
+	objVal := 0.
+	status := common.SolverStatusNotSolved
+	p.Reduced = &common.StandardComputationalForm{
+		Objective:      objective,
+		Constraints:    matrix.CSCFromColumns(mr, colRows, colVals),
+		RHS:            rhs,
+		Lower:          lower,
+		Upper:          upper,
+		ObjectiveValue: &objVal,
+		Status:         &status,
+		Stats:          p.original.Stats,
+		IsMaximization: p.original.IsMaximization,
+	}
+}
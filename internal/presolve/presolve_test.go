@@ -0,0 +1,185 @@
+package presolve
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chriso345/gore/assert"
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/simplex"
+	"gonum.org/v1/gonum/mat"
+)
+
+func newTestSCF(c []float64, A []float64, b []float64) *common.StandardComputationalForm {
+	objVal := 0.
+	status := common.SolverStatusNotSolved
+	return &common.StandardComputationalForm{
+		Objective:      mat.NewVecDense(len(c), c),
+		Constraints:    mat.NewDense(len(b), len(c), A),
+		RHS:            mat.NewVecDense(len(b), b),
+		ObjectiveValue: &objVal,
+		Status:         &status,
+	}
+}
+
+// solve runs presolve, the simplex method on what remains, and postsolve.
+func solve(t *testing.T, scf *common.StandardComputationalForm) *Presolved {
+	t.Helper()
+	ps := Presolve(scf, 1e-9)
+	if ps.Reduced != nil {
+		assert.Nil(t, simplex.Simplex(ps.Reduced, common.DefaultSolverConfig()))
+	}
+	ps.Postsolve()
+	return ps
+}
+
+func TestPresolveSingletonRow(t *testing.T) {
+	// min x1 + 2x2 s.t. 2x1 = 4, x1 + x2 - s = 5
+	scf := newTestSCF([]float64{1, 2, 0}, []float64{
+		2, 0, 0,
+		1, 1, -1,
+	}, []float64{4, 5})
+
+	ps := solve(t, scf)
+	assert.Equal(t, ps.RowsRemoved, 1)
+	assert.Equal(t, ps.ColsRemoved, 1)
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, *scf.ObjectiveValue, 8, 1e-9)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(0), 2, 1e-9)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(1), 3, 1e-9)
+}
+
+func TestPresolveFixedColumn(t *testing.T) {
+	// min x1 + x2 s.t. x1 + x2 - s = 3 with x1 fixed at 1
+	scf := newTestSCF([]float64{1, 1, 0}, []float64{1, 1, -1}, []float64{3})
+	scf.SetBounds(0, 1, 1)
+
+	ps := solve(t, scf)
+	assert.True(t, ps.ColsRemoved >= 1)
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, *scf.ObjectiveValue, 3, 1e-9)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(0), 1, 1e-9)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(1), 2, 1e-9)
+}
+
+func TestPresolveEmptyRowInfeasible(t *testing.T) {
+	scf := newTestSCF([]float64{1, 1}, []float64{
+		1, 1,
+		0, 0,
+	}, []float64{2, 1})
+
+	ps := Presolve(scf, 1e-9)
+	assert.True(t, ps.Reduced == nil)
+	ps.Postsolve()
+	assert.Equal(t, *scf.Status, common.SolverStatusInfeasible)
+}
+
+func TestPresolveDuplicateRows(t *testing.T) {
+	A := []float64{
+		1, 2, 1, 0,
+		2, 4, 2, 0,
+		1, 0, 0, 1,
+	}
+
+	scf := newTestSCF([]float64{-1, -1, 0, 0}, A, []float64{4, 8, 3})
+	ps := solve(t, scf)
+	assert.True(t, ps.RowsRemoved >= 1)
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, *scf.ObjectiveValue, -3.5, 1e-9)
+
+	scf = newTestSCF([]float64{-1, -1, 0, 0}, A, []float64{4, 9, 3})
+	ps = Presolve(scf, 1e-9)
+	ps.Postsolve()
+	assert.Equal(t, *scf.Status, common.SolverStatusInfeasible)
+}
+
+func TestPresolveDominatedColumn(t *testing.T) {
+	// min x1 + x2 + 3x3 s.t. x1 + x2 + x3 - s = 2. Every dual feasible y lies
+	// in [0, 1], so x3 always has a positive reduced cost and sits at zero.
+	scf := newTestSCF([]float64{1, 1, 3, 0}, []float64{1, 1, 1, -1}, []float64{2})
+
+	ps := solve(t, scf)
+	assert.True(t, ps.ColsRemoved >= 1)
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, *scf.ObjectiveValue, 2, 1e-9)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(2), 0, 1e-9)
+}
+
+func TestPresolveEmptyColumnUnbounded(t *testing.T) {
+	// x2 appears in no row and its cost improves without limit
+	scf := newTestSCF([]float64{1, -1}, []float64{1, 0}, []float64{1})
+	ps := solve(t, scf)
+	assert.Equal(t, ps.ColsRemoved, 2)
+	assert.Equal(t, *scf.Status, common.SolverStatusUnbounded)
+
+	// Unless the rest of the problem is infeasible
+	scf = newTestSCF([]float64{1, -1}, []float64{1, 0}, []float64{1})
+	scf.SetBounds(0, 2, 3)
+	solve(t, scf)
+	assert.Equal(t, *scf.Status, common.SolverStatusInfeasible)
+}
+
+// newMixedSCF has singleton, duplicate and ordinary rows and a boxed column.
+func newMixedSCF() *common.StandardComputationalForm {
+	scf := newTestSCF([]float64{2, 3, 1, 4, 0, 0}, []float64{
+		1, 1, 1, 0, -1, 0,
+		0, 0, 0, 2, 0, 0,
+		2, 2, 2, 0, -2, 0,
+		1, 3, 0, 1, 0, -1,
+	}, []float64{4, 2, 8, 6})
+	scf.SetBounds(2, 0, 1)
+	return scf
+}
+
+func TestPostsolveMatchesSimplex(t *testing.T) {
+	build := newMixedSCF
+
+	direct := build()
+	assert.Nil(t, simplex.Simplex(direct, common.DefaultSolverConfig()))
+
+	scf := build()
+	ps := solve(t, scf)
+	assert.True(t, ps.RowsRemoved >= 2)
+	assert.Equal(t, *scf.Status, *direct.Status)
+	assert.IsClose(t, *scf.ObjectiveValue, *direct.ObjectiveValue, 1e-9)
+
+	// Duals and reduced costs must certify optimality of the postsolved point
+	m, n := scf.Constraints.Dims()
+	dualObj := 0.
+	for i := range m {
+		dualObj += scf.RHS.AtVec(i) * scf.DualSolution.AtVec(i)
+	}
+	for j := range n {
+		d := scf.ReducedCosts.AtVec(j)
+		lower, upper := scf.Bounds(j)
+		x := scf.PrimalSolution.AtVec(j)
+		switch {
+		case d > 1e-9:
+			assert.IsClose(t, x, lower, 1e-9)
+		case d < -1e-9:
+			assert.IsClose(t, x, upper, 1e-9)
+		}
+		if d > 0 {
+			dualObj += d * lower
+		} else if d < 0 && !math.IsInf(upper, 1) {
+			dualObj += d * upper
+		}
+	}
+	assert.IsClose(t, dualObj, *scf.ObjectiveValue, 1e-9)
+	assert.Equal(t, len(scf.Basis), m)
+}
+
+func TestPostsolveStopped(t *testing.T) {
+	config := common.DefaultSolverConfig()
+	config.MaxIterations = 1
+
+	scf := newMixedSCF()
+	ps := Presolve(scf, 1e-9)
+	assert.Nil(t, simplex.Simplex(ps.Reduced, config))
+	ps.Postsolve()
+
+	assert.Equal(t, *scf.Status, common.SolverStatusIterationLimit)
+	assert.Equal(t, scf.PrimalSolution.Len(), 6)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(3), 1, 1e-9) // Fixed by its singleton row
+	assert.True(t, scf.DualSolution == nil)
+}
@@ -0,0 +1,48 @@
+package presolve
+
+import (
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/matrix"
+)
+
+// Presolved holds a reduced problem together with the record needed to map
+// its solution back onto the original problem.
+type Presolved struct {
+	// Reduced is the problem left for the simplex method, or nil when presolve
+	// has already decided the outcome.
+	Reduced *common.StandardComputationalForm
+
+	RowsRemoved int
+	ColsRemoved int
+
+	original *common.StandardComputationalForm
+	A        *matrix.CSC
+	status   common.SolverStatus // Outcome decided by presolve, if any
+
+	// unbounded records an empty column whose cost improves without limit.
+	// It is parked at a finite value so that feasibility of the remaining
+	// problem still decides between infeasible and unbounded.
+	unbounded bool
+
+	x      []float64 // Values of removed columns
+	rowMap []int     // Original row of each reduced row
+	colMap []int     // Original column of each reduced column
+	ops    []operation
+}
+
+type operationKind int
+
+const (
+	opFixColumn    operationKind = iota // Column removed at a fixed value
+	opEmptyRow                          // Row with no remaining entries removed
+	opDuplicateRow                      // Row that is a multiple of another removed
+	opSingletonRow                      // Row fixing its only column, both removed
+)
+
+// operation is one reduction, undone in reverse order by Postsolve.
+type operation struct {
+	kind    operationKind
+	row     int
+	col     int
+	atUpper bool // For opFixColumn, the column rests at its upper bound
+}
@@ -0,0 +1,6 @@
+// Package scaling rescales the rows and columns of a linear program in
+// standard computational form before it is solved, and maps the solution of
+// the scaled problem back onto the original one.
+//
+// This package is internal and intended for use within the gspl project only.
+package scaling
@@ -0,0 +1,253 @@
+package scaling
+
+import (
+	"math"
+	"slices"
+
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/matrix"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	// geometricPasses bounds the number of geometric mean passes.
+	geometricPasses = 8
+
+	// geometricImprovement is the relative reduction in the spread of the
+	// entries a pass must achieve for another pass to be made.
+	geometricImprovement = 0.9
+)
+
+// Scaled holds a scaled copy of a problem together with the factors needed to
+// map its solution back onto the original problem.
+type Scaled struct {
+	// Problem is the scaled problem, min (Cc)^T x' s.t. RAC x' = Rb with
+	// bounds C^-1 l <= x' <= C^-1 u, so that x = C x'.
+	Problem *common.StandardComputationalForm
+
+	RowScale []float64 // Diagonal of R
+	ColScale []float64 // Diagonal of C
+
+	original *common.StandardComputationalForm
+}
+
+// Scale returns a scaled copy of scf using the given method, which brings the
+// entries of A close to one in magnitude, as badly scaled coefficients make
+// the basis ill-conditioned. A basis or starting point held on scf carries
+// over to Problem. scf is not modified; call Unscale once Problem has been
+// solved to write the solution back onto it. ScalingNone yields unit factors.
+func Scale(scf *common.StandardComputationalForm, method common.Scaling) *Scaled {
+	A := matrix.AsCSC(scf.Constraints)
+	m, n := A.Dims()
+
+	r, c := ones(m), ones(n)
+	switch method {
+	case common.ScalingGeometric:
+		// Geometric passes even out the entries; a final equilibration
+		// brings the largest entry of each row and column to one.
+		geometric(A, r, c)
+		equilibrate(A, r, c)
+	case common.ScalingEquilibration:
+		equilibrate(A, r, c)
+	}
+	roundToPowerOfTwo(r)
+	roundToPowerOfTwo(c)
+
+	objective := mat.NewVecDense(n, nil)
+	lower := mat.NewVecDense(n, nil)
+	upper := mat.NewVecDense(n, nil)
+	for j := range n {
+		objective.SetVec(j, scf.Objective.AtVec(j)*c[j])
+		l, u := scf.Bounds(j)
+		lower.SetVec(j, l/c[j])
+		upper.SetVec(j, u/c[j])
+	}
+	rhs := mat.NewVecDense(m, nil)
+	for i := range m {
+		rhs.SetVec(i, scf.RHS.AtVec(i)*r[i])
+	}
+
+	// A starting point is scaled like the bounds
+	var x *mat.VecDense
+	if scf.PrimalSolution != nil {
+		x = mat.NewVecDense(n, nil)
+		x.DivElemVec(scf.PrimalSolution, mat.NewVecDense(n, c))
+	}
+
+	objVal := 0.
+	status := common.SolverStatusNotSolved
+	return &Scaled{
+		Problem: &common.StandardComputationalForm{
+			Objective:      objective,
+			Constraints:    matrix.CSCScale(A, r, c),
+			RHS:            rhs,
+			Lower:          lower,
+			Upper:          upper,
+			Basis:          slices.Clone(scf.Basis), // Scaling leaves a basis unchanged
+			AtUpper:        slices.Clone(scf.AtUpper),
+			PrimalSolution: x,
+			ObjectiveValue: &objVal,
+			Status:         &status,
+			Stats:          scf.Stats,
+			SlackIndices:   scf.SlackIndices,
+			NumPrimals:     scf.NumPrimals,
+			IsMaximization: scf.IsMaximization,
+		},
+		RowScale: r,
+		ColScale: c,
+		original: scf,
+	}
+}
+
+// Unscale writes the outcome of the solved Problem onto the original problem.
+// Primal values and unbounded rays are multiplied by C, duals and Farkas rays
+// by R, and reduced costs by C^-1.
+func (s *Scaled) Unscale() {
+	scf, p := s.original, s.Problem
+	*scf.Status = *p.Status
+	*scf.ObjectiveValue = *p.ObjectiveValue
+	scf.Basis = p.Basis
+	scf.AtUpper = p.AtUpper
+
+	if p.PrimalSolution != nil {
+		scf.PrimalSolution = mat.NewVecDense(len(s.ColScale), nil)
+		scf.PrimalSolution.MulElemVec(p.PrimalSolution, mat.NewVecDense(len(s.ColScale), s.ColScale))
+	}
+	if p.DualSolution != nil {
+		scf.DualSolution = mat.NewVecDense(len(s.RowScale), nil)
+		scf.DualSolution.MulElemVec(p.DualSolution, mat.NewVecDense(len(s.RowScale), s.RowScale))
+	}
+	if p.ReducedCosts != nil {
+		scf.ReducedCosts = mat.NewVecDense(len(s.ColScale), nil)
+		scf.ReducedCosts.DivElemVec(p.ReducedCosts, mat.NewVecDense(len(s.ColScale), s.ColScale))
+	}
+
+	scf.FarkasRay, scf.UnboundedRay = nil, nil
+	if p.FarkasRay != nil {
+		scf.FarkasRay = mat.NewVecDense(len(s.RowScale), nil)
+		scf.FarkasRay.MulElemVec(p.FarkasRay, mat.NewVecDense(len(s.RowScale), s.RowScale))
+	}
+	if p.UnboundedRay != nil {
+		scf.UnboundedRay = mat.NewVecDense(len(s.ColScale), nil)
+		scf.UnboundedRay.MulElemVec(p.UnboundedRay, mat.NewVecDense(len(s.ColScale), s.ColScale))
+	}
+}
+
+// geometric repeatedly divides each row, then each column, by the geometric
+// mean of its smallest and largest entry, until the spread of the entries
+// stops improving.
+func geometric(A *matrix.CSC, r, c []float64) {
+	m, n := A.Dims()
+	spread := math.Inf(1)
+	for range geometricPasses {
+		rowMin, rowMax := rowRange(A, r, c)
+		for i := range m {
+			if rowMax[i] > 0 {
+				r[i] /= math.Sqrt(rowMin[i] * rowMax[i])
+			}
+		}
+		for j := range n {
+			lo, hi := colRange(A, r, c, j)
+			if hi > 0 {
+				c[j] /= math.Sqrt(lo * hi)
+			}
+		}
+
+		next := ratio(A, r, c)
+		if next > geometricImprovement*spread {
+			break
+		}
+		spread = next
+	}
+}
+
+// equilibrate scales every row, then every column, so that its largest entry
+// has magnitude one.
+func equilibrate(A *matrix.CSC, r, c []float64) {
+	m, n := A.Dims()
+	_, rowMax := rowRange(A, r, c)
+	for i := range m {
+		if rowMax[i] > 0 {
+			r[i] /= rowMax[i]
+		}
+	}
+	for j := range n {
+		if _, hi := colRange(A, r, c, j); hi > 0 {
+			c[j] /= hi
+		}
+	}
+}
+
+// rowRange returns the smallest and largest magnitude of the nonzeros of each
+// row of RAC. Empty rows report zero for both.
+func rowRange(A *matrix.CSC, r, c []float64) ([]float64, []float64) {
+	m, n := A.Dims()
+	lo, hi := make([]float64, m), make([]float64, m)
+	for i := range m {
+		lo[i] = math.Inf(1)
+	}
+	for j := range n {
+		rows, vals := A.Col(j)
+		for k, i := range rows {
+			v := math.Abs(vals[k] * r[i] * c[j])
+			lo[i] = math.Min(lo[i], v)
+			hi[i] = math.Max(hi[i], v)
+		}
+	}
+	for i := range m {
+		if hi[i] == 0 {
+			lo[i] = 0
+		}
+	}
+	return lo, hi
+}
+
+// colRange returns the smallest and largest magnitude of the nonzeros of
+// column j of RAC. An empty column reports zero for both.
+func colRange(A *matrix.CSC, r, c []float64, j int) (float64, float64) {
+	rows, vals := A.Col(j)
+	if len(rows) == 0 {
+		return 0, 0
+	}
+	lo, hi := math.Inf(1), 0.
+	for k, i := range rows {
+		v := math.Abs(vals[k] * r[i] * c[j])
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	return lo, hi
+}
+
+// ratio returns the ratio of the largest to the smallest nonzero magnitude of
+// RAC, or one when the matrix is empty.
+func ratio(A *matrix.CSC, r, c []float64) float64 {
+	_, n := A.Dims()
+	lo, hi := math.Inf(1), 0.
+	for j := range n {
+		l, h := colRange(A, r, c, j)
+		if h > 0 {
+			lo = math.Min(lo, l)
+			hi = math.Max(hi, h)
+		}
+	}
+	if hi == 0 {
+		return 1
+	}
+	return hi / lo
+}
+
+// roundToPowerOfTwo replaces each factor by the nearest power of two, so that
+// scaling and unscaling introduce no rounding error.
+func roundToPowerOfTwo(f []float64) {
+	for i, v := range f {
+		f[i] = math.Exp2(math.Round(math.Log2(v)))
+	}
+}
+
+func ones(n int) []float64 {
+	f := make([]float64, n)
+	for i := range f {
+		f[i] = 1
+	}
+	return f
+}
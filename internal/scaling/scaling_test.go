@@ -0,0 +1,97 @@
+package scaling
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chriso345/gore/assert"
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/matrix"
+	"github.com/chriso345/gspl/internal/simplex"
+	"gonum.org/v1/gonum/mat"
+)
+
+// newBadlyScaledSCF builds min x1 + 1e6 x2 s.t. 1e-4 x1 + 1e6 x2 - s1 = 1,
+// 2e-4 x1 + 1e4 x2 - s2 = 1e-2, whose optimum is x1 = 0, x2 = 1e-6.
+func newBadlyScaledSCF() *common.StandardComputationalForm {
+	objVal := 0.
+	status := common.SolverStatusNotSolved
+	return &common.StandardComputationalForm{
+		Objective: mat.NewVecDense(4, []float64{1, 1e6, 0, 0}),
+		Constraints: mat.NewDense(2, 4, []float64{
+			1e-4, 1e6, -1, 0,
+			2e-4, 1e4, 0, -1,
+		}),
+		RHS:            mat.NewVecDense(2, []float64{1, 1e-2}),
+		ObjectiveValue: &objVal,
+		Status:         &status,
+	}
+}
+
+func TestScaleReducesSpread(t *testing.T) {
+	scf := newBadlyScaledSCF()
+	A := matrix.AsCSC(scf.Constraints)
+	before := ratio(A, ones(2), ones(4))
+
+	for _, method := range []common.Scaling{common.ScalingGeometric, common.ScalingEquilibration} {
+		sc := Scale(scf, method)
+		for _, f := range append(sc.RowScale, sc.ColScale...) {
+			_, exp := math.Frexp(f)
+			assert.Equal(t, f, math.Ldexp(0.5, exp)) // A power of two
+		}
+		after := ratio(A, sc.RowScale, sc.ColScale)
+		assert.True(t, after < before/100)
+	}
+
+	// The original problem is untouched
+	assert.Equal(t, scf.Constraints.At(0, 0), 1e-4)
+}
+
+func TestScaleNone(t *testing.T) {
+	sc := Scale(newBadlyScaledSCF(), common.ScalingNone)
+	for _, f := range append(sc.RowScale, sc.ColScale...) {
+		assert.Equal(t, f, 1.)
+	}
+}
+
+func TestScaleStartPoint(t *testing.T) {
+	scf := newBadlyScaledSCF()
+	scf.PrimalSolution = mat.NewVecDense(4, []float64{1, 2, 3, 4})
+	sc := Scale(scf, common.ScalingGeometric)
+	for j := range 4 {
+		assert.IsClose(t, sc.Problem.PrimalSolution.AtVec(j)*sc.ColScale[j], float64(j+1), 1e-12)
+	}
+}
+
+func TestUnscale(t *testing.T) {
+	direct := newBadlyScaledSCF()
+	assert.Nil(t, simplex.Simplex(direct, common.DefaultSolverConfig()))
+
+	scf := newBadlyScaledSCF()
+	sc := Scale(scf, common.ScalingGeometric)
+	assert.Nil(t, simplex.Simplex(sc.Problem, common.DefaultSolverConfig()))
+	sc.Unscale()
+
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, *scf.ObjectiveValue, 1, 1e-9)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(0), 0, 1e-9)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(1), 1e-6, 1e-15)
+	for i := range 2 {
+		assert.IsClose(t, scf.DualSolution.AtVec(i), direct.DualSolution.AtVec(i), 1e-6)
+	}
+	for j := range 4 {
+		assert.IsClose(t, scf.ReducedCosts.AtVec(j), direct.ReducedCosts.AtVec(j), 1e-6)
+	}
+}
+
+func TestUnscaleRays(t *testing.T) {
+	scf := newBadlyScaledSCF()
+	sc := Scale(scf, common.ScalingGeometric)
+	*sc.Problem.Status = common.SolverStatusInfeasible
+	sc.Problem.FarkasRay = mat.NewVecDense(2, []float64{1, -1})
+	sc.Unscale()
+
+	assert.Equal(t, scf.FarkasRay.AtVec(0), sc.RowScale[0])
+	assert.Equal(t, scf.FarkasRay.AtVec(1), -sc.RowScale[1])
+	assert.True(t, scf.UnboundedRay == nil)
+}
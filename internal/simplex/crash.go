@@ -0,0 +1,53 @@
+package simplex
+
+import (
+	"github.com/chriso345/gspl/internal/matrix"
+	"gonum.org/v1/gonum/mat"
+)
+
+// crashBasis picks, for each row, a column of A that can start basic in place
+// of the artificial column of that row, or -1 when the row needs its
+// artificial. A candidate has a single nonzero, so the columns chosen form a
+// diagonal basis, and the value it takes when basic must lie within its
+// bounds. Slack columns of <= rows qualify, as do surplus columns of >= rows
+// whose right-hand side is not positive. Among the candidates of a row a
+// column without cost is preferred, as a slack leaves Phase 2 less to do.
+//
+// residual is b - N*xN with every column of A nonbasic, as used to sign the
+// artificial columns.
+func (sm *simplexMethod) crashBasis(A *matrix.CSC, c, residual *mat.VecDense, tol float64) []int {
+	basis := make([]int, sm.m)
+	for i := range basis {
+		basis[i] = -1
+	}
+
+	for j := range sm.n {
+		rows, vals := A.Col(j)
+		row, a := -1, 0.
+		for k, v := range vals {
+			if v == 0 {
+				continue
+			}
+			if row != -1 {
+				row = -1
+				break
+			}
+			row, a = rows[k], v
+		}
+		if row == -1 {
+			continue
+		}
+		if cur := basis[row]; cur != -1 && (c.AtVec(cur) == 0 || c.AtVec(j) != 0) {
+			continue
+		}
+
+		// Column j itself is part of the residual while it is nonbasic
+		x := (residual.AtVec(row) + a*sm.nonbasicValue(j)) / a
+		lower, upper := columnBounds(sm.lower, sm.upper, j)
+		if x < lower-tol || x > upper+tol {
+			continue
+		}
+		basis[row] = j
+	}
+	return basis
+}
@@ -0,0 +1,78 @@
+package simplex
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chriso345/gore/assert"
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/matrix"
+	"gonum.org/v1/gonum/mat"
+)
+
+// newCrashTestSCF builds
+//
+//	Minimize: -x1 - x2
+//	Subject to: x1 + 2*x2 <= 8, x2 >= 2, x1 >= -1, x1 <= 4
+//
+// with slack s1 and surplus columns s2 and s3.
+func newCrashTestSCF() *common.StandardComputationalForm {
+	objVal := 0.
+	status := common.SolverStatusNotSolved
+	inf := math.Inf(1)
+	return &common.StandardComputationalForm{
+		Objective: mat.NewVecDense(5, []float64{-1, -1, 0, 0, 0}),
+		Constraints: mat.NewDense(3, 5, []float64{
+			1, 2, 1, 0, 0,
+			0, 1, 0, -1, 0,
+			1, 0, 0, 0, -1,
+		}),
+		RHS:            mat.NewVecDense(3, []float64{8, 2, -1}),
+		Lower:          mat.NewVecDense(5, []float64{-inf, 0, 0, 0, 0}),
+		Upper:          mat.NewVecDense(5, []float64{4, inf, inf, inf, inf}),
+		ObjectiveValue: &objVal,
+		Status:         &status,
+	}
+}
+
+func TestCrashBasis(t *testing.T) {
+	scf := newCrashTestSCF()
+	sm := &simplexMethod{m: 3, n: 5, b: scf.RHS, lower: scf.Lower, upper: scf.Upper}
+	sm.atUpper = []bool{true, false, false, false, false}
+
+	// x1 rests at its upper bound of 4, leaving residuals 4, 2 and -5
+	residual := sm.nonbasicRHS(scf.Constraints, 5)
+	basis := sm.crashBasis(matrix.AsCSC(scf.Constraints), scf.Objective, residual, 1e-9)
+
+	// The slack of the <= row and the surplus of x1 >= -1 start basic; the
+	// surplus of x2 >= 2 would be negative, so that row needs an artificial
+	assert.Equal(t, basis[0], 2)
+	assert.Equal(t, basis[1], -1)
+	assert.Equal(t, basis[2], 4)
+}
+
+func TestSimplexCrashStart(t *testing.T) {
+	scf := newCrashTestSCF()
+	assert.Nil(t, Simplex(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, *scf.ObjectiveValue, -6, 1e-9)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(0), 4, 1e-9)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(1), 2, 1e-9)
+
+	// From the slack basis a problem of <= rows needs only its Phase 2 pivot,
+	// where starting from the artificial would take a Phase 1 pivot as well
+	objVal := 0.
+	status := common.SolverStatusNotSolved
+	scf = &common.StandardComputationalForm{
+		Objective:      mat.NewVecDense(3, []float64{-1, -2, 0}),
+		Constraints:    mat.NewDense(1, 3, []float64{1, 1, 1}),
+		RHS:            mat.NewVecDense(1, []float64{5}),
+		ObjectiveValue: &objVal,
+		Status:         &status,
+	}
+	config := common.DefaultSolverConfig()
+	config.MaxIterations = 2
+	assert.Nil(t, Simplex(scf, config))
+	assert.Equal(t, status, common.SolverStatusOptimal)
+	assert.IsClose(t, objVal, -10, 1e-9)
+}
@@ -0,0 +1,311 @@
+package simplex
+
+import (
+	"math"
+	"sort"
+
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/errors"
+	"github.com/chriso345/gspl/internal/matrix"
+	"gonum.org/v1/gonum/mat"
+)
+
+// crossoverPivot is the smallest entry accepted as a pivot when a column
+// replaces an artificial column in the starting basis of crossover.
+const crossoverPivot = 1e-7
+
+// Crossover moves the primal solution held on scf, such as the interior point
+// found by the barrier method, to an optimal basic solution.
+//
+// Columns strictly between their bounds are pivoted into a basis of
+// artificial columns first, furthest from their bounds first. When
+// scf.DualSolution is set, columns at a bound whose reduced cost under it is
+// zero follow, since an optimal basis is likely to contain them. Columns that
+// could not enter are then pushed to a bound one at a time, in the direction
+// that does not worsen the objective, pivoting whenever a basic variable
+// reaches a bound first. The resulting vertex is made optimal by the primal
+// simplex, or by the dual simplex from the same basis when rounding has left
+// it slightly infeasible.
+//
+// On return scf holds the status and, when optimal, the basis, primal and dual
+// vertex solution and reduced costs, exactly as after Simplex.
+func Crossover(scf *common.StandardComputationalForm, config *common.SolverConfig) error {
+	m, n := scf.Constraints.Dims()
+	if scf.PrimalSolution == nil || scf.PrimalSolution.Len() != n {
+		return errors.New(errors.ErrInvalidInput, "crossover needs a primal solution with one entry per column", nil)
+	}
+	sm := &simplexMethod{
+		m:     m,
+		n:     n,
+		stats: scf.Stats,
+		b:     scf.RHS,
+	}
+
+	// Artificial columns are fixed at zero, so they can only ever leave the basis
+	sm.lower = mat.NewVecDense(n+m, nil)
+	sm.upper = mat.NewVecDense(n+m, nil)
+	sm.atUpper = make([]bool, n+m)
+	x := make([]float64, n+m) // Value of every column, read for nonbasic ones
+	for j := range n {
+		lower, upper := scf.Bounds(j)
+		if lower > upper+config.Tolerance {
+			*scf.Status = common.SolverStatusInfeasible
+			return nil
+		}
+		sm.lower.SetVec(j, lower)
+		sm.upper.SetVec(j, upper)
+
+		// Snap values within the tolerance onto their bound
+		v := math.Min(math.Max(scf.PrimalSolution.AtVec(j), lower), upper)
+		switch {
+		case v-lower <= config.Tolerance:
+			v = lower
+		case upper-v <= config.Tolerance:
+			v = upper
+			sm.atUpper[j] = true
+		}
+		x[j] = v
+	}
+
+	signs := make([]float64, m)
+	for i := range m {
+		signs[i] = 1.
+	}
+	sm.A = auxiliaryMatrix(scf.Constraints, signs)
+	sm.c = mat.NewVecDense(n+m, nil)
+	for j := range n {
+		sm.c.SetVec(j, scf.Objective.AtVec(j))
+	}
+
+	sm.indices = mat.NewVecDense(m, nil)
+	for i := range m {
+		sm.indices.SetVec(i, float64(n+i))
+	}
+	sm.markBasic()
+	factor, err := newBasisFactor(sm.A, sm.indices)
+	if err != nil {
+		return errors.New(errors.ErrNumericalFailure, "error factorising basis matrix", err)
+	}
+
+	for _, j := range sm.crossoverCandidates(scf, x, config.Tolerance) {
+		if err := sm.crossoverEnter(j, factor); err != nil {
+			return err
+		}
+	}
+
+	for j := range n {
+		if sm.isbasic[j] || sm.atBound(j, x[j]) {
+			continue
+		}
+		unbounded, err := sm.push(j, x, factor, config.Tolerance)
+		if err != nil {
+			return err
+		}
+		if unbounded {
+			*scf.Status = common.SolverStatusUnbounded
+			return nil
+		}
+	}
+
+	scf.Basis = make([]int, m)
+	for i := range m {
+		scf.Basis[i] = int(sm.indices.AtVec(i))
+	}
+	scf.AtUpper = make([]bool, n)
+	copy(scf.AtUpper, sm.atUpper)
+
+	xb, err := factor.ftran(sm.nonbasicRHS(sm.A, n))
+	if err != nil {
+		return errors.New(errors.ErrNumericalFailure, "error solving for basic solution", err)
+	}
+	for i := range m {
+		lower, upper := columnBounds(sm.lower, sm.upper, int(sm.indices.AtVec(i)))
+		if xb.AtVec(i) < lower-config.Tolerance || xb.AtVec(i) > upper+config.Tolerance {
+			return DualSimplex(scf, config)
+		}
+	}
+
+	sm.cb = sm.indices
+	if err := RSM(sm, 2, config); err != nil {
+		return errors.New(errors.ErrNumericalFailure, "error in Crossover", err)
+	}
+	sm.storeSolution(scf)
+	return nil
+}
+
+// crossoverCandidates returns the columns to try in the starting basis:
+// those strictly between their bounds, furthest from a bound first, then
+// those at a bound with a zero reduced cost under scf.DualSolution.
+func (sm *simplexMethod) crossoverCandidates(scf *common.StandardComputationalForm, x []float64, tol float64) []int {
+	var interior, degenerate []int
+	distance := make([]float64, sm.n)
+	y := scf.DualSolution
+	for j := range sm.n {
+		lower, upper := columnBounds(sm.lower, sm.upper, j)
+		switch {
+		case !sm.atBound(j, x[j]):
+			distance[j] = math.Min(x[j]-lower, upper-x[j])
+			interior = append(interior, j)
+		case lower == upper:
+		case y != nil && y.Len() == sm.m:
+			if math.Abs(scf.Objective.AtVec(j)-matrix.ColDot(scf.Constraints, j, y)) <= tol {
+				degenerate = append(degenerate, j)
+			}
+		}
+	}
+	sort.SliceStable(interior, func(a, b int) bool {
+		return distance[interior[a]] > distance[interior[b]]
+	})
+	return append(interior, degenerate...)
+}
+
+// atBound reports whether value v of column j lies at one of its bounds, or
+// at zero for a free column, where it can be nonbasic.
+func (sm *simplexMethod) atBound(j int, v float64) bool {
+	lower, upper := columnBounds(sm.lower, sm.upper, j)
+	if math.IsInf(lower, -1) && math.IsInf(upper, 1) {
+		return v == 0
+	}
+	return v == lower || v == upper
+}
+
+// crossoverEnter replaces the basic artificial column with the largest entry
+// in the direction of column j by j itself. As long as the point satisfies
+// Ax = b the basic solution does not change. A column dependent on the
+// current basis is left out.
+func (sm *simplexMethod) crossoverEnter(j int, factor *basisFactor) error {
+	as := mat.NewVecDense(sm.m, nil)
+	matrix.ColInto(as, sm.A, j)
+	d, err := factor.ftran(as)
+	if err != nil {
+		return errors.New(errors.ErrNumericalFailure, "error solving for the entering direction", err)
+	}
+
+	r, best := -1, crossoverPivot
+	for i := range sm.m {
+		if int(sm.indices.AtVec(i)) >= sm.n && math.Abs(d.AtVec(i)) > best {
+			r, best = i, math.Abs(d.AtVec(i))
+		}
+	}
+	if r == -1 {
+		return nil
+	}
+	return sm.enterBasis(r, j, d, factor)
+}
+
+// push moves nonbasic column q from its value in x to a bound, or to zero for
+// a free column, without worsening the objective. If a basic variable reaches
+// a bound first, q takes its place in the basis and it becomes nonbasic at
+// that bound. push reports true when q can improve the objective without
+// limit.
+func (sm *simplexMethod) push(q int, x []float64, factor *basisFactor, tol float64) (bool, error) {
+	rhs := mat.VecDenseCopyOf(sm.b)
+	for j := range sm.n + sm.m {
+		if x[j] != 0 && !sm.isbasic[j] {
+			matrix.AddScaledCol(rhs, -x[j], sm.A, j)
+		}
+	}
+	xb, err := factor.ftran(rhs)
+	if err != nil {
+		return false, errors.New(errors.ErrNumericalFailure, "error solving for basic solution", err)
+	}
+
+	cb := mat.NewVecDense(sm.m, nil)
+	for i := range sm.m {
+		cb.SetVec(i, sm.c.AtVec(int(sm.indices.AtVec(i))))
+	}
+	pi, err := factor.btran(cb)
+	if err != nil {
+		return false, errors.New(errors.ErrNumericalFailure, "error solving for dual variables", err)
+	}
+	rc := sm.c.AtVec(q) - matrix.ColDot(sm.A, q, pi)
+
+	// The distance q can travel in each direction before it reaches the
+	// position it may rest at
+	lower, upper := columnBounds(sm.lower, sm.upper, q)
+	up, down := upper-x[q], x[q]-lower
+	if math.IsInf(lower, -1) && math.IsInf(upper, 1) {
+		up, down = math.Inf(1), math.Inf(1)
+		if x[q] < 0 {
+			up = -x[q]
+		} else {
+			down = x[q]
+		}
+	}
+
+	var directions []bool // Whether each direction to try decreases q
+	switch {
+	case rc < -tol:
+		directions = []bool{false}
+	case rc > tol:
+		directions = []bool{true}
+	case down < up:
+		directions = []bool{true, false}
+	default:
+		directions = []bool{false, true}
+	}
+
+	as := mat.NewVecDense(sm.m, nil)
+	matrix.ColInto(as, sm.A, q)
+	for _, decreasing := range directions {
+		fl := leavingVariable{
+			factor:     factor,
+			indices:    sm.indices,
+			as:         as,
+			xb:         xb,
+			phase:      2,
+			n:          sm.n,
+			lower:      sm.lower,
+			upper:      sm.upper,
+			s:          q,
+			decreasing: decreasing,
+		}
+		if err := findLeave(&fl); err != nil {
+			return false, errors.New(errors.ErrNumericalFailure, "error finding leaving variable", err)
+		}
+
+		own := up
+		if decreasing {
+			own = down
+		}
+		switch {
+		case fl.r != -1 && fl.theta < own:
+			leaving := int(sm.indices.AtVec(fl.r))
+			x[leaving] = 0
+			if l, u := columnBounds(sm.lower, sm.upper, leaving); fl.toUpper {
+				x[leaving] = u
+			} else if !math.IsInf(l, -1) {
+				x[leaving] = l
+			}
+			sm.atUpper[leaving] = fl.toUpper
+			return false, sm.enterBasis(fl.r, q, fl.direction, factor)
+		case !math.IsInf(own, 1):
+			// Land exactly on the bound, or on zero for a free column
+			switch {
+			case decreasing && !math.IsInf(lower, -1):
+				x[q] = lower
+			case !decreasing && !math.IsInf(upper, 1):
+				x[q] = upper
+			default:
+				x[q] = 0
+			}
+			sm.atUpper[q] = !decreasing && !math.IsInf(upper, 1)
+			return false, nil
+		}
+	}
+	// Only an attractive column has a single direction to try, and a column
+	// at neither bound can always reach one in some direction
+	return true, nil
+}
+
+// enterBasis makes column j basic in row r, where d = B^-1 a_j is its
+// direction in terms of the current basis.
+func (sm *simplexMethod) enterBasis(r, j int, d *mat.VecDense, factor *basisFactor) error {
+	sm.isbasic[int(sm.indices.AtVec(r))], sm.isbasic[j] = false, true
+	sm.indices.SetVec(r, float64(j))
+	sm.atUpper[j] = false
+	if err := factor.update(r, d, sm.A, sm.indices); err != nil {
+		return errors.New(errors.ErrNumericalFailure, "error updating basis factorisation", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package simplex
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/chriso345/gore/assert"
+	"github.com/chriso345/gspl/internal/common"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCrossoverBounded(t *testing.T) {
+	// Minimize: -x1 - 2*x2
+	// Subject to: x1 + x2 + s = 10, 0 <= x1 <= 3, -1 <= x2 <= 4
+	objVal := 0.
+	status := common.SolverStatusNotSolved
+	scf := &common.StandardComputationalForm{
+		Objective:      mat.NewVecDense(3, []float64{-1, -2, 0}),
+		Constraints:    mat.NewDense(1, 3, []float64{1, 1, 1}),
+		RHS:            mat.NewVecDense(1, []float64{10}),
+		Lower:          mat.NewVecDense(3, []float64{0, -1, 0}),
+		Upper:          mat.NewVecDense(3, []float64{3, 4, math.Inf(1)}),
+		PrimalSolution: mat.NewVecDense(3, []float64{1.5, 1.5, 7}),
+		ObjectiveValue: &objVal,
+		Status:         &status,
+	}
+
+	assert.Nil(t, Crossover(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, status, common.SolverStatusOptimal)
+	assert.IsClose(t, objVal, -11, 1e-9)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(0), 3, 1e-9)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(1), 4, 1e-9)
+	assert.Equal(t, len(scf.Basis), 1)
+	assert.Equal(t, scf.Basis[0], 2)
+	assert.True(t, scf.AtUpper[0] && scf.AtUpper[1])
+	assert.IsClose(t, scf.DualSolution.AtVec(0), 0, 1e-9)
+}
+
+func TestCrossoverOptimalFace(t *testing.T) {
+	// Minimize: -x1 - x2
+	// Subject to: x1 + x2 + s1 = 4, x1 + s2 = 3
+	// Every point of the edge x1 + x2 = 4 with x1 <= 3 is optimal
+	objVal := 0.
+	status := common.SolverStatusNotSolved
+	scf := &common.StandardComputationalForm{
+		Objective: mat.NewVecDense(4, []float64{-1, -1, 0, 0}),
+		Constraints: mat.NewDense(2, 4, []float64{
+			1, 1, 1, 0,
+			1, 0, 0, 1,
+		}),
+		RHS:            mat.NewVecDense(2, []float64{4, 3}),
+		PrimalSolution: mat.NewVecDense(4, []float64{2, 2, 0, 1}),
+		DualSolution:   mat.NewVecDense(2, []float64{-1, 0}),
+		ObjectiveValue: &objVal,
+		Status:         &status,
+	}
+
+	assert.Nil(t, Crossover(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, status, common.SolverStatusOptimal)
+	assert.IsClose(t, objVal, -4, 1e-9)
+	assert.Equal(t, len(scf.Basis), 2)
+
+	// The result is a vertex: the nonbasic columns sit at zero
+	for j := range 4 {
+		if !slices.Contains(scf.Basis, j) {
+			assert.Equal(t, scf.PrimalSolution.AtVec(j), 0.)
+		}
+	}
+	assert.IsClose(t, scf.DualSolution.AtVec(0), -1, 1e-9)
+}
+
+func TestCrossoverNotOptimal(t *testing.T) {
+	// A feasible point that is not optimal is finished by the simplex
+	scf := newDualTestSCF()
+	scf.PrimalSolution = mat.NewVecDense(4, []float64{5, 5, 6, 14})
+
+	assert.Nil(t, Crossover(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, *scf.ObjectiveValue, 9, 1e-9)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(0), 3, 1e-9)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(1), 1, 1e-9)
+}
+
+func TestCrossoverInfeasiblePoint(t *testing.T) {
+	// A point that violates Ax = b is repaired by the dual simplex
+	scf := newDualTestSCF()
+	scf.PrimalSolution = mat.NewVecDense(4, []float64{0, 0, 0, 0})
+
+	assert.Nil(t, Crossover(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, *scf.ObjectiveValue, 9, 1e-9)
+}
+
+func TestCrossoverMissingPoint(t *testing.T) {
+	scf := newDualTestSCF()
+	assert.NotNil(t, Crossover(scf, common.DefaultSolverConfig()))
+}
@@ -1,8 +1,8 @@
 // Package simplex provides core implementations of the simplex algorithm for
 // solving linear programming problems.
 //
-// It includes both the standard and revised simplex methods implemented over
-// dense matrix representations.
+// It includes both the standard and revised simplex methods, working on the
+// sparse constraint matrix and a sparse LU factorisation of the basis.
 //
 // This package is internal and intended for use within the gspl project only.
 package simplex
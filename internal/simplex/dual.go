@@ -0,0 +1,404 @@
+package simplex
+
+import (
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/errors"
+	"github.com/chriso345/gspl/internal/matrix"
+	"gonum.org/v1/gonum/mat"
+)
+
+// artificialBound is the width of the box placed on a column that has no
+// bound on the side its reduced cost requires for dual feasibility.
+const artificialBound = 1e7
+
+// Solve runs the algorithm selected by config.Algorithm on scf.
+func Solve(scf *common.StandardComputationalForm, config *common.SolverConfig) error {
+	if config.Algorithm == common.AlgorithmDual {
+		return DualSimplex(scf, config)
+	}
+	return Simplex(scf, config)
+}
+
+// DualSimplex solves scf with the bounded dual revised simplex method.
+//
+// Every row carries an artificial column fixed at zero. When scf holds the
+// basis of an earlier optimal solve, as the children of a branch-and-bound
+// node do, the method warm starts from it; otherwise it starts from the basis
+// of artificial columns. Nonbasic columns are placed at the bound that makes
+// their reduced cost dual feasible, boxing any column that lacks that bound.
+// If a box turns out to be binding, or the boxed problem is infeasible, the
+// answer may not hold for the original problem and Simplex is used instead.
+func DualSimplex(scf *common.StandardComputationalForm, config *common.SolverConfig) error {
+	m, n := scf.Constraints.Dims()
+	sm := &simplexMethod{
+		m:     m,
+		n:     n,
+		stats: scf.Stats,
+		b:     scf.RHS,
+	}
+
+	// Artificial columns are fixed at zero, so they can only ever leave the basis
+	sm.lower = mat.NewVecDense(n+m, nil)
+	sm.upper = mat.NewVecDense(n+m, nil)
+	for j := range n {
+		lower, upper := scf.Bounds(j)
+		if lower > upper+config.Tolerance {
+			*scf.Status = common.SolverStatusInfeasible
+			return nil
+		}
+		sm.lower.SetVec(j, lower)
+		sm.upper.SetVec(j, upper)
+	}
+
+	signs := make([]float64, m)
+	for i := range m {
+		signs[i] = 1.
+	}
+	sm.A = auxiliaryMatrix(scf.Constraints, signs)
+
+	sm.c = mat.NewVecDense(n+m, nil)
+	for j := range n {
+		sm.c.SetVec(j, scf.Objective.AtVec(j))
+	}
+
+	if !sm.warmStart(scf) {
+		sm.indices = mat.NewVecDense(m, nil)
+		for i := range m {
+			sm.indices.SetVec(i, float64(n+i))
+		}
+		sm.atUpper = make([]bool, n+m)
+	}
+
+	boxed, err := sm.dualFeasibleStart(config.Tolerance)
+	if err != nil {
+		return errors.New(errors.ErrNumericalFailure, "error pricing the starting basis", err)
+	}
+
+	if err := dualRSM(sm, config); err != nil {
+		return errors.New(errors.ErrNumericalFailure, "error in Dual Simplex", err)
+	}
+
+	if len(boxed) > 0 {
+		switch {
+		case sm.flag == common.SolverStatusOptimal:
+			for _, j := range boxed {
+				lower, upper := columnBounds(sm.lower, sm.upper, j)
+				origLower, origUpper := scf.Bounds(j)
+				x := sm.x.AtVec(j)
+				if (math.IsInf(origLower, -1) && x <= lower+config.Tolerance) ||
+					(math.IsInf(origUpper, 1) && x >= upper-config.Tolerance) {
+					return sm.fallBack(scf, config)
+				}
+			}
+		case !sm.flag.Stopped():
+			return sm.fallBack(scf, config)
+		}
+	}
+
+	sm.storeSolution(scf)
+	return nil
+}
+
+// fallBack solves scf with Simplex on the iterations the dual simplex left.
+func (sm *simplexMethod) fallBack(scf *common.StandardComputationalForm, config *common.SolverConfig) error {
+	cfg := *config
+	if cfg.MaxIterations > 0 {
+		cfg.MaxIterations -= sm.iterations
+		if cfg.MaxIterations <= 0 {
+			sm.flag = common.SolverStatusIterationLimit
+			sm.storeSolution(scf)
+			return nil
+		}
+	}
+	return Simplex(scf, &cfg)
+}
+
+// warmStart adopts the basis recorded on scf by an earlier solve. It reports
+// false when there is no usable basis.
+func (sm *simplexMethod) warmStart(scf *common.StandardComputationalForm) bool {
+	if len(scf.Basis) != sm.m || len(scf.AtUpper) != sm.n {
+		return false
+	}
+
+	seen := make(map[int]bool, sm.m)
+	sm.indices = mat.NewVecDense(sm.m, nil)
+	for i, j := range scf.Basis {
+		if j < 0 || j >= sm.n+sm.m || seen[j] {
+			return false
+		}
+		seen[j] = true
+		sm.indices.SetVec(i, float64(j))
+	}
+	if _, err := newBasisFactor(sm.A, sm.indices); err != nil {
+		return false
+	}
+
+	sm.atUpper = make([]bool, sm.n+sm.m)
+	copy(sm.atUpper, scf.AtUpper)
+	sm.markBasic()
+	return true
+}
+
+// dualFeasibleStart moves every nonbasic column to the bound its reduced cost
+// requires. A column without that bound is boxed at artificialBound from its
+// other bound (or from zero) and returned in boxed.
+func (sm *simplexMethod) dualFeasibleStart(tol float64) (boxed []int, err error) {
+	sm.cb = mat.NewVecDense(sm.m, nil)
+	for i := range sm.m {
+		sm.cb.SetVec(i, sm.c.AtVec(int(sm.indices.AtVec(i))))
+	}
+	factor, err := newBasisFactor(sm.A, sm.indices)
+	if err != nil {
+		return nil, err
+	}
+	if sm.pi, err = factor.btran(sm.cb); err != nil {
+		return nil, err
+	}
+
+	sm.markBasic()
+	for j := range sm.n {
+		if sm.isbasic[j] {
+			continue
+		}
+		lower, upper := columnBounds(sm.lower, sm.upper, j)
+		if lower == upper {
+			sm.atUpper[j] = false
+			continue
+		}
+
+		d := sm.c.AtVec(j) - matrix.ColDot(sm.A, j, sm.pi)
+		switch {
+		case d > tol:
+			if math.IsInf(lower, -1) {
+				base := 0.
+				if !math.IsInf(upper, 1) {
+					base = upper
+				}
+				sm.lower.SetVec(j, base-artificialBound)
+				boxed = append(boxed, j)
+			}
+			sm.atUpper[j] = false
+		case d < -tol:
+			if math.IsInf(upper, 1) {
+				base := 0.
+				if !math.IsInf(lower, -1) {
+					base = lower
+				}
+				sm.upper.SetVec(j, base+artificialBound)
+				boxed = append(boxed, j)
+			}
+			sm.atUpper[j] = true
+		default:
+			// A zero reduced cost is feasible at either bound, so keep any valid position
+			if math.IsInf(upper, 1) {
+				sm.atUpper[j] = false
+			} else if math.IsInf(lower, -1) {
+				sm.atUpper[j] = true
+			}
+		}
+	}
+	return boxed, nil
+}
+
+// dualRSM runs the bounded dual simplex from a dual feasible basis. Each
+// iteration picks the basic variable furthest outside its bounds to leave and
+// the entering column by the dual ratio test, which keeps every reduced cost
+// at the sign optimality requires.
+func dualRSM(sm *simplexMethod, config *common.SolverConfig) error {
+	sm.flag = common.SolverStatusNotSolved
+	sm.value = 0.
+	sm.x = mat.NewVecDense(sm.n, nil)
+
+	start, pivots := time.Now(), sm.iterations
+	var factor *basisFactor
+	defer func() {
+		elapsed := time.Since(start)
+		sm.record(phaseDual, elapsed, sm.iterations-pivots, factor)
+		sm.logFinish(config, phaseDual, elapsed, sm.iterations-pivots)
+	}()
+	config.Notify(common.Event{Kind: common.EventPhase, Phase: common.PhaseDual, Iterations: sm.iterations})
+	config.Log().LogAttrs(config.Ctx, slog.LevelDebug, "simplex phase started",
+		slog.String("phase", common.PhaseDual.String()),
+		slog.Int("rows", sm.m),
+		slog.Int("columns", sm.n),
+	)
+
+	factor, err := newBasisFactor(sm.A, sm.indices)
+	if err != nil {
+		return errors.New(errors.ErrNumericalFailure, "error factorising basis matrix", err)
+	}
+
+	// As in RSM, the basic solution is only solved for afresh after a
+	// refactorisation and otherwise moved along each step
+	sm.markBasic()
+	xb, err := factor.ftran(sm.nonbasicRHS(sm.A, sm.n))
+	if err != nil {
+		return errors.New(errors.ErrNumericalFailure, "error solving for basic solution", err)
+	}
+
+	as := mat.NewVecDense(sm.m, nil)
+	degenerate := 0 // Consecutive pivots with a zero dual step
+	for {
+		bland := degenerate >= degenerateLimit
+		if stop := sm.stopStatus(config); stop != common.SolverStatusNotSolved {
+			sm.flag = stop
+			sm.setPoint(xb, sm.n)
+			return nil
+		}
+
+		sm.pi, err = factor.btran(sm.cb)
+		if err != nil {
+			return errors.New(errors.ErrNumericalFailure, "error solving for dual variables", err)
+		}
+
+		// Leaving row: the largest primal infeasibility, or under Bland's rule
+		// the infeasible basic column with the smallest index
+		r, delta := -1, 0.
+		for i := range sm.m {
+			j := int(sm.indices.AtVec(i))
+			l, u := columnBounds(sm.lower, sm.upper, j)
+			v, infeas := xb.AtVec(i), 0.
+			switch {
+			case v < l-config.Tolerance:
+				infeas = v - l
+			case v > u+config.Tolerance:
+				infeas = v - u
+			default:
+				continue
+			}
+			if bland {
+				if r == -1 || j < int(sm.indices.AtVec(r)) {
+					r, delta = i, infeas
+				}
+			} else if math.Abs(infeas) > math.Abs(delta) {
+				r, delta = i, infeas
+			}
+		}
+
+		if r == -1 {
+			sm.flag = common.SolverStatusOptimal
+			for j := range sm.n {
+				if !sm.isbasic[j] {
+					sm.x.SetVec(j, sm.nonbasicValue(j))
+				}
+			}
+			for i := range sm.m {
+				if j := int(sm.indices.AtVec(i)); j < sm.n {
+					// Snap values that sit within tolerance of a bound onto it
+					l, u := columnBounds(sm.lower, sm.upper, j)
+					sm.x.SetVec(j, math.Min(math.Max(xb.AtVec(i), l), u))
+				}
+			}
+			sm.value = 0.
+			for j := range sm.n {
+				sm.value += sm.c.AtVec(j) * sm.x.AtVec(j)
+			}
+			return nil
+		}
+
+		// Row r of B^-1
+		er := mat.NewVecDense(sm.m, nil)
+		er.SetVec(r, 1.)
+		rho, err := factor.btran(er)
+		if err != nil {
+			return errors.New(errors.ErrNumericalFailure, "error solving for pivot row", err)
+		}
+
+		// Dual ratio test. The leaving variable moves towards the bound it
+		// violates, so an entering column must push row r in that direction.
+		s, best, bestAlpha := -1, math.Inf(1), 0.
+		for j := range sm.n {
+			if sm.isbasic[j] {
+				continue
+			}
+			lower, upper := columnBounds(sm.lower, sm.upper, j)
+			if lower == upper {
+				continue
+			}
+
+			alpha := matrix.ColDot(sm.A, j, rho)
+			if delta < 0 {
+				alpha = -alpha
+			}
+			if math.Abs(alpha) < pivotTolerance {
+				continue
+			}
+			free := math.IsInf(lower, -1) && math.IsInf(upper, 1)
+			if !free && (sm.atUpper[j] != (alpha < 0)) {
+				continue
+			}
+
+			d := sm.c.AtVec(j) - matrix.ColDot(sm.A, j, sm.pi)
+			ratio := math.Max(d/alpha, 0)
+			if free {
+				ratio = 0
+			}
+			// Ties favour the largest pivot, or under Bland's rule the first column
+			tie := ratio == best && !bland && math.Abs(alpha) > math.Abs(bestAlpha)
+			if ratio < best || tie {
+				s, best, bestAlpha = j, ratio, alpha
+			}
+		}
+
+		if s == -1 {
+			// The dual is unbounded, so no primal feasible point exists. Row
+			// r of B^-1, signed towards the violated bound, is a Farkas ray.
+			sm.flag = common.SolverStatusInfeasible
+			sm.ray = mat.VecDenseCopyOf(rho)
+			if delta < 0 {
+				sm.ray.ScaleVec(-1, sm.ray)
+			}
+			return nil
+		}
+		sm.iterations++
+		sm.progress(config, phaseDual, xb, sm.n)
+
+		if best < pivotTolerance {
+			degenerate++
+		} else {
+			degenerate = 0
+		}
+
+		matrix.ColInto(as, sm.A, s)
+		direction, err := factor.ftran(as)
+		if err != nil {
+			return errors.New(errors.ErrNumericalFailure, "error solving for the entering direction", err)
+		}
+
+		// The leaving variable moves onto the bound it violated, which takes
+		// a step of t in the entering variable
+		t := delta / direction.AtVec(r)
+		entering := sm.nonbasicValue(s) + t
+		xb.AddScaledVec(xb, -t, direction)
+		xb.SetVec(r, entering)
+
+		// The leaving variable becomes nonbasic at the bound it violated
+		leaving := int(sm.indices.AtVec(r))
+		sm.atUpper[leaving] = delta > 0
+		sm.atUpper[s] = false
+		sm.isbasic[leaving], sm.isbasic[s] = false, true
+
+		bu := basisUpdate{
+			indices: sm.indices,
+			cb:      sm.cb,
+			s:       s,
+			r:       r,
+			cs:      sm.c.AtVec(s),
+		}
+		if err := updateB(&bu); err != nil {
+			return errors.New(errors.ErrNumericalFailure, "error updating basis", err)
+		}
+		if err := factor.update(r, direction, sm.A, sm.indices); err != nil {
+			return errors.New(errors.ErrNumericalFailure, "error updating basis factorisation", err)
+		}
+		if factor.fresh() {
+			if xb, err = factor.ftran(sm.nonbasicRHS(sm.A, sm.n)); err != nil {
+				return errors.New(errors.ErrNumericalFailure, "error solving for basic solution", err)
+			}
+		}
+	}
+}
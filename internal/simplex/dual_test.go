@@ -0,0 +1,144 @@
+package simplex
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chriso345/gore/assert"
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/matrix"
+	"gonum.org/v1/gonum/mat"
+)
+
+// newDualTestSCF builds min 2x1 + 3x2 s.t. x1 + x2 - s1 = 4, x1 + 3x2 - s2 = 6.
+func newDualTestSCF() *common.StandardComputationalForm {
+	objVal := 0.
+	status := common.SolverStatusNotSolved
+	return &common.StandardComputationalForm{
+		Objective: mat.NewVecDense(4, []float64{2, 3, 0, 0}),
+		Constraints: mat.NewDense(2, 4, []float64{
+			1, 1, -1, 0,
+			1, 3, 0, -1,
+		}),
+		RHS:            mat.NewVecDense(2, []float64{4, 6}),
+		ObjectiveValue: &objVal,
+		Status:         &status,
+	}
+}
+
+func TestDualSimplex(t *testing.T) {
+	scf := newDualTestSCF()
+	assert.Nil(t, DualSimplex(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, *scf.ObjectiveValue, 9, 1e-9)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(0), 3, 1e-9)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(1), 1, 1e-9)
+	assert.IsClose(t, scf.DualSolution.AtVec(0), 1.5, 1e-9)
+	assert.IsClose(t, scf.DualSolution.AtVec(1), 0.5, 1e-9)
+}
+
+func TestDualSimplexWarmStart(t *testing.T) {
+	scf := newDualTestSCF()
+	assert.Nil(t, Simplex(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, len(scf.Basis), 2)
+
+	// Branch on x2 <= 0 and re-optimise from the parent's basis
+	child := scf.Copy()
+	child.SetBounds(1, 0, 0)
+	assert.Nil(t, DualSimplex(child, common.DefaultSolverConfig()))
+	assert.Equal(t, *child.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, *child.ObjectiveValue, 12, 1e-9)
+	assert.IsClose(t, child.PrimalSolution.AtVec(0), 6, 1e-9)
+	assert.IsClose(t, child.PrimalSolution.AtVec(1), 0, 1e-9)
+
+	// The parent is untouched
+	assert.IsClose(t, *scf.ObjectiveValue, 9, 1e-9)
+}
+
+func TestDualSimplexInfeasible(t *testing.T) {
+	scf := newDualTestSCF()
+	scf.SetBounds(0, 0, 1)
+	scf.SetBounds(1, 0, 1)
+	assert.Nil(t, DualSimplex(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, *scf.Status, common.SolverStatusInfeasible)
+}
+
+func TestDualSimplexUnbounded(t *testing.T) {
+	// Minimize -x1 s.t. x1 - x2 = 1; x1 has no upper bound to box against
+	objVal := 0.
+	status := common.SolverStatusNotSolved
+	scf := &common.StandardComputationalForm{
+		Objective:      mat.NewVecDense(2, []float64{-1, 0}),
+		Constraints:    mat.NewDense(1, 2, []float64{1, -1}),
+		RHS:            mat.NewVecDense(1, []float64{1}),
+		ObjectiveValue: &objVal,
+		Status:         &status,
+	}
+	assert.Nil(t, DualSimplex(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, status, common.SolverStatusUnbounded)
+}
+
+func TestDualSimplexBoxedFreeVariable(t *testing.T) {
+	// Minimize x1 subject to x1 - x2 = -4 with x1 free and 0 <= x2 <= 1
+	objVal := 0.
+	status := common.SolverStatusNotSolved
+	scf := &common.StandardComputationalForm{
+		Objective:      mat.NewVecDense(2, []float64{1, 0}),
+		Constraints:    mat.NewDense(1, 2, []float64{1, -1}),
+		RHS:            mat.NewVecDense(1, []float64{-4}),
+		Lower:          mat.NewVecDense(2, []float64{math.Inf(-1), 0}),
+		Upper:          mat.NewVecDense(2, []float64{math.Inf(1), 1}),
+		ObjectiveValue: &objVal,
+		Status:         &status,
+	}
+	assert.Nil(t, DualSimplex(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, status, common.SolverStatusOptimal)
+	assert.IsClose(t, objVal, -4, 1e-9)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(0), -4, 1e-9)
+}
+
+func TestSolveSelectsAlgorithm(t *testing.T) {
+	config := common.DefaultSolverConfig()
+	config.Algorithm = common.AlgorithmDual
+
+	scf := newDualTestSCF()
+	assert.Nil(t, Solve(scf, config))
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, *scf.ObjectiveValue, 9, 1e-9)
+}
+
+func TestDualSimplexIterationLimit(t *testing.T) {
+	config := common.DefaultSolverConfig()
+	config.MaxIterations = 1
+
+	scf := newDualTestSCF()
+	assert.Nil(t, DualSimplex(scf, config))
+	assert.Equal(t, *scf.Status, common.SolverStatusIterationLimit)
+	assert.Equal(t, len(scf.Basis), 2)
+	assert.Equal(t, scf.PrimalSolution.Len(), 4)
+	assert.True(t, scf.DualSolution == nil)
+}
+
+func TestDualSimplexFarkasRay(t *testing.T) {
+	// x1 + x2 >= 4 cannot hold with both variables at most 1
+	scf := newDualTestSCF()
+	scf.SetBounds(0, 0, 1)
+	scf.SetBounds(1, 0, 1)
+	assert.Nil(t, DualSimplex(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, *scf.Status, common.SolverStatusInfeasible)
+
+	y := scf.FarkasRay
+	assert.NotNil(t, y)
+	best := 0.
+	for j := range 4 {
+		aty := matrix.ColDot(scf.Constraints, j, y)
+		lower, upper := scf.Bounds(j)
+		if aty > 1e-9 {
+			assert.False(t, math.IsInf(upper, 1))
+			best += aty * upper
+		} else if aty < -1e-9 {
+			best += aty * lower
+		}
+	}
+	assert.True(t, mat.Dot(y, scf.RHS) > best+1e-9)
+}
@@ -0,0 +1,284 @@
+package simplex
+
+import (
+	"math"
+	"slices"
+
+	"github.com/chriso345/gspl/internal/errors"
+	"github.com/chriso345/gspl/internal/matrix"
+	"gonum.org/v1/gonum/mat"
+)
+
+// refactorInterval is the number of basis updates applied to a factorisation
+// before it is rebuilt from the basis columns.
+const refactorInterval = 64
+
+// singularTolerance is the smallest pivot accepted when factorising a basis.
+const singularTolerance = 1e-11
+
+// eta is one product-form update: column r of the identity replaced by d,
+// where d = B^-1 a_s is the entering column in terms of the old basis.
+type eta struct {
+	r int
+	d []float64
+}
+
+// basisFactor maintains the inverse of the basis as a sparse LU factorisation
+// of some earlier basis B0 followed by eta updates, so that B = B0 E1 ... Ek.
+// B0 is factorised straight from the sparse columns of the constraint matrix,
+// so the factors take space in proportion to their nonzeros rather than m^2.
+type basisFactor struct {
+	m int
+
+	// Step k of the elimination pivots on row prow[k] of basis position
+	// pcol[k]. Column k of L holds the multipliers below that pivot, keyed by
+	// row, and column k of U its entries above the diagonal, keyed by step.
+	prow, pcol   []int
+	lrows, urows [][]int
+	lvals, uvals [][]float64
+	diag         []float64
+
+	etas []eta
+
+	refactors int // Factorisations made, the first included
+}
+
+// newBasisFactor factorises the basis formed by the columns of A listed in
+// basis.
+func newBasisFactor(A mat.Matrix, basis *mat.VecDense) (*basisFactor, error) {
+	bf := &basisFactor{}
+	if err := bf.refactor(A, basis); err != nil {
+		return nil, err
+	}
+	return bf, nil
+}
+
+// refactor discards the eta file and factorises the basis afresh.
+//
+// The elimination is left-looking: each basis column is reduced by the earlier
+// columns of L that reach its nonzeros, then pivots on its largest remaining
+// entry. Sparse columns go first, so the slack and artificial columns that
+// make up most bases pivot without fill.
+func (bf *basisFactor) refactor(A mat.Matrix, basis *mat.VecDense) error {
+	S := matrix.AsCSC(A)
+	m := basis.Len()
+	_, n := S.Dims()
+
+	bf.m = m
+	bf.etas = bf.etas[:0]
+	bf.refactors++
+	bf.prow = make([]int, m)
+	bf.lrows, bf.lvals = make([][]int, m), make([][]float64, m)
+	bf.urows, bf.uvals = make([][]int, m), make([][]float64, m)
+	bf.diag = make([]float64, m)
+
+	column := func(k int) ([]int, []float64) {
+		if j := int(basis.AtVec(k)); j >= 0 && j < n {
+			return S.Col(j)
+		}
+		return nil, nil
+	}
+	bf.pcol = make([]int, m)
+	for k := range m {
+		bf.pcol[k] = k
+	}
+	slices.SortStableFunc(bf.pcol, func(a, b int) int {
+		ra, _ := column(a)
+		rb, _ := column(b)
+		return len(ra) - len(rb)
+	})
+
+	step := make([]int, m) // Elimination step that pivoted on each row, or -1
+	for i := range step {
+		step[i] = -1
+	}
+	x := make([]float64, m)
+	inPattern := make([]bool, m)
+	reached := make([]bool, m)
+	var pattern, steps []int
+
+	for k, pos := range bf.pcol {
+		pattern, steps = pattern[:0], steps[:0]
+		rows, vals := column(pos)
+		for p, i := range rows {
+			x[i] = vals[p]
+			inPattern[i] = true
+			pattern = append(pattern, i)
+		}
+
+		// Every earlier step reachable from the nonzeros of the column through
+		// L. Its multipliers only reach rows pivoted later, so applying the
+		// steps in order respects their dependencies.
+		for p := 0; p < len(pattern); p++ {
+			t := step[pattern[p]]
+			if t < 0 || reached[t] {
+				continue
+			}
+			reached[t] = true
+			steps = append(steps, t)
+			for _, r := range bf.lrows[t] {
+				if !inPattern[r] {
+					inPattern[r] = true
+					pattern = append(pattern, r)
+				}
+			}
+		}
+		slices.Sort(steps)
+
+		var urows []int
+		var uvals []float64
+		for _, t := range steps {
+			reached[t] = false
+			v := x[bf.prow[t]]
+			if v == 0 {
+				continue
+			}
+			urows = append(urows, t)
+			uvals = append(uvals, v)
+			for p, r := range bf.lrows[t] {
+				x[r] -= bf.lvals[t][p] * v
+			}
+		}
+
+		// Partial pivoting over the rows not yet pivoted on
+		pivot := -1
+		for _, i := range pattern {
+			if step[i] < 0 && (pivot == -1 || math.Abs(x[i]) > math.Abs(x[pivot])) {
+				pivot = i
+			}
+		}
+		if pivot == -1 || math.Abs(x[pivot]) < singularTolerance {
+			for _, i := range pattern {
+				x[i], inPattern[i] = 0, false
+			}
+			return errors.New(errors.ErrNumericalFailure, "basis matrix is singular", nil)
+		}
+
+		d := x[pivot]
+		var lrows []int
+		var lvals []float64
+		for _, i := range pattern {
+			if step[i] < 0 && i != pivot && x[i] != 0 {
+				lrows = append(lrows, i)
+				lvals = append(lvals, x[i]/d)
+			}
+			x[i], inPattern[i] = 0, false
+		}
+
+		step[pivot] = k
+		bf.prow[k] = pivot
+		bf.diag[k] = d
+		bf.lrows[k], bf.lvals[k] = lrows, lvals
+		bf.urows[k], bf.uvals[k] = urows, uvals
+	}
+	return nil
+}
+
+// ftran solves B x = v.
+func (bf *basisFactor) ftran(v mat.Vector) (*mat.VecDense, error) {
+	if v.Len() != bf.m {
+		return nil, errors.New(errors.ErrNumericalFailure, "error in forward transformation", mat.ErrShape)
+	}
+
+	// L y = v, then U z = y, with z over the steps
+	w := make([]float64, bf.m)
+	for i := range w {
+		w[i] = v.AtVec(i)
+	}
+	y := make([]float64, bf.m)
+	for t, i := range bf.prow {
+		y[t] = w[i]
+		if y[t] == 0 {
+			continue
+		}
+		for p, r := range bf.lrows[t] {
+			w[r] -= bf.lvals[t][p] * y[t]
+		}
+	}
+	x := mat.NewVecDense(bf.m, nil)
+	raw := x.RawVector().Data
+	for k := bf.m - 1; k >= 0; k-- {
+		z := y[k] / bf.diag[k]
+		raw[bf.pcol[k]] = z
+		if z == 0 {
+			continue
+		}
+		for p, t := range bf.urows[k] {
+			y[t] -= bf.uvals[k][p] * z
+		}
+	}
+
+	// Apply E_k^-1 ... E_1^-1 in the order the updates were made
+	for _, e := range bf.etas {
+		xr := raw[e.r] / e.d[e.r]
+		for i, di := range e.d {
+			raw[i] -= di * xr
+		}
+		raw[e.r] = xr
+	}
+	return x, nil
+}
+
+// btran solves B^T y = v.
+func (bf *basisFactor) btran(v mat.Vector) (*mat.VecDense, error) {
+	if v.Len() != bf.m {
+		return nil, errors.New(errors.ErrNumericalFailure, "error in backward transformation", mat.ErrShape)
+	}
+	z := mat.VecDenseCopyOf(v)
+
+	// Apply E_k^-T ... E_1^-T, newest first; each only changes entry r
+	raw := z.RawVector().Data
+	for k := len(bf.etas) - 1; k >= 0; k-- {
+		e := bf.etas[k]
+		sum := raw[e.r]
+		for i, di := range e.d {
+			if i != e.r {
+				sum -= di * raw[i]
+			}
+		}
+		raw[e.r] = sum / e.d[e.r]
+	}
+
+	// U^T w = z over the steps, then L^T y = w
+	w := make([]float64, bf.m)
+	for k, pos := range bf.pcol {
+		sum := raw[pos]
+		for p, t := range bf.urows[k] {
+			sum -= bf.uvals[k][p] * w[t]
+		}
+		w[k] = sum / bf.diag[k]
+	}
+	y := mat.NewVecDense(bf.m, nil)
+	out := y.RawVector().Data
+	for t := bf.m - 1; t >= 0; t-- {
+		sum := w[t]
+		for p, r := range bf.lrows[t] {
+			sum -= bf.lvals[t][p] * out[r]
+		}
+		out[bf.prow[t]] = sum
+	}
+	return y, nil
+}
+
+// fresh reports whether the factorisation has just been rebuilt, with no
+// updates since.
+func (bf *basisFactor) fresh() bool {
+	return len(bf.etas) == 0
+}
+
+// update records that basis column r has been replaced by a column whose
+// forward transformation is d. The caller keeps basis current and passes it
+// with A so that the factorisation can be rebuilt once the eta file grows too
+// long.
+func (bf *basisFactor) update(r int, d *mat.VecDense, A mat.Matrix, basis *mat.VecDense) error {
+	if len(bf.etas) >= refactorInterval {
+		return bf.refactor(A, basis)
+	}
+	if d.AtVec(r) == 0 {
+		return errors.New(errors.ErrNumericalFailure, "zero pivot in basis update", nil)
+	}
+	col := make([]float64, bf.m)
+	copy(col, d.RawVector().Data)
+	bf.etas = append(bf.etas, eta{r: r, d: col})
+	return nil
+}
@@ -0,0 +1,110 @@
+package simplex
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/chriso345/gore/assert"
+	"github.com/chriso345/gspl/internal/matrix"
+	"gonum.org/v1/gonum/mat"
+)
+
+// columns returns the basis of the first m columns, in order.
+func columns(m int) *mat.VecDense {
+	basis := mat.NewVecDense(m, nil)
+	for i := range m {
+		basis.SetVec(i, float64(i))
+	}
+	return basis
+}
+
+// assertSolves checks ftran and btran of bf against dense solves with B.
+func assertSolves(t *testing.T, bf *basisFactor, B *mat.Dense, v *mat.VecDense) {
+	t.Helper()
+	m := v.Len()
+
+	x, err := bf.ftran(v)
+	assert.Nil(t, err)
+	want := mat.NewVecDense(m, nil)
+	assert.Nil(t, want.SolveVec(B, v))
+	for i := range m {
+		assert.IsClose(t, x.AtVec(i), want.AtVec(i), 1e-8)
+	}
+
+	y, err := bf.btran(v)
+	assert.Nil(t, err)
+	assert.Nil(t, want.SolveVec(B.T(), v))
+	for i := range m {
+		assert.IsClose(t, y.AtVec(i), want.AtVec(i), 1e-8)
+	}
+}
+
+func TestBasisFactorSolves(t *testing.T) {
+	B := mat.NewDense(3, 3, []float64{
+		2, 1, 0,
+		1, 3, 1,
+		0, 1, 4,
+	})
+	bf, err := newBasisFactor(B, columns(3))
+	assert.Nil(t, err)
+	assertSolves(t, bf, B, mat.NewVecDense(3, []float64{1, 2, 3}))
+}
+
+func TestBasisFactorSparseColumns(t *testing.T) {
+	// Every diagonal entry of the basis is zero, so each step must pivot off it
+	A := matrix.CSCFromMatrix(mat.NewDense(4, 6, []float64{
+		0, 1, 0, 0, 2, 1,
+		3, 0, 0, 1, 0, 0,
+		0, 0, 5, 0, 1, 0,
+		1, 0, 0, 0, 0, 4,
+	}))
+	basis := mat.NewVecDense(4, []float64{3, 5, 4, 2})
+	bf, err := newBasisFactor(A, basis)
+	assert.Nil(t, err)
+	assertSolves(t, bf, matrix.ExtractColumns(A, basis), mat.NewVecDense(4, []float64{1, -2, 3, -4}))
+
+	// A column repeated in the basis makes it singular
+	basis.SetVec(3, 5)
+	_, err = newBasisFactor(A, basis)
+	assert.NotNil(t, err)
+}
+
+func TestBasisFactorUpdates(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	m := 6
+
+	// A diagonally dominant basis keeps every replacement well conditioned
+	B := mat.NewDense(m, m, nil)
+	for i := range m {
+		for j := range m {
+			B.Set(i, j, rng.Float64())
+		}
+		B.Set(i, i, float64(m)+1)
+	}
+	bf, err := newBasisFactor(B, columns(m))
+	assert.Nil(t, err)
+
+	// Run past refactorInterval so both the eta file and a refactorisation are exercised
+	for k := range refactorInterval + 10 {
+		r := k % m
+		col := mat.NewVecDense(m, nil)
+		for i := range m {
+			col.SetVec(i, rng.Float64())
+		}
+		col.SetVec(r, float64(m)+1)
+
+		d, err := bf.ftran(col)
+		assert.Nil(t, err)
+		B.SetCol(r, col.RawVector().Data)
+		assert.Nil(t, bf.update(r, d, B, columns(m)))
+
+		assertSolves(t, bf, B, mat.NewVecDense(m, []float64{1, -2, 3, -4, 5, -6}))
+	}
+	assert.True(t, len(bf.etas) < refactorInterval)
+}
+
+func TestBasisFactorSingular(t *testing.T) {
+	B := mat.NewDense(2, 2, []float64{1, 2, 2, 4})
+	_, err := newBasisFactor(B, columns(2))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,216 @@
+package simplex
+
+import (
+	"math"
+
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/errors"
+	"github.com/chriso345/gspl/internal/matrix"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	// partialSegments is the number of segments partial pricing splits the
+	// columns into.
+	partialSegments = 8
+
+	// partialMinimum is the smallest segment partial pricing scans, so that
+	// small problems are priced in full.
+	partialMinimum = 32
+)
+
+// pricer chooses the entering column of the primal simplex.
+type pricer interface {
+	// price returns the column to enter among the first n, or -1 when none is
+	// attractive. score returns the rate at which column j improves the
+	// objective, and is at most epsilon for columns that cannot enter.
+	price(n int, score func(j int) float64, epsilon float64) int
+
+	// update adjusts the pricer to a pivot before the factorisation of the
+	// basis is updated.
+	update(pu *pricingUpdate) error
+}
+
+// newPricer returns the pricer for rule: Dantzig's rule, steepest edge, Devex
+// or partial pricing. Pricers that weight columns start from the reference
+// framework of the basis factorised in factor.
+func newPricer(rule common.Pricing, A mat.Matrix, factor *basisFactor, isbasic []bool) (pricer, error) {
+	switch rule {
+	case common.PricingSteepestEdge:
+		return newSteepestEdge(A, factor, isbasic)
+	case common.PricingDevex:
+		return newDevex(len(isbasic)), nil
+	case common.PricingPartial:
+		return &partialPricer{}, nil
+	default:
+		return dantzigPricer{}, nil
+	}
+}
+
+// dantzigPricer takes the column with the most attractive reduced cost.
+type dantzigPricer struct{}
+
+func (dantzigPricer) price(n int, score func(j int) float64, epsilon float64) int {
+	s, best := -1, epsilon
+	for j := range n {
+		if v := score(j); v > best {
+			s, best = j, v
+		}
+	}
+	return s
+}
+
+func (dantzigPricer) update(*pricingUpdate) error { return nil }
+
+// partialPricer applies Dantzig's rule to one segment of the columns at a
+// time, moving on to the next segment only when the current one has no
+// attractive column. Optimality is declared after a full pass finds none.
+type partialPricer struct {
+	start int // First column of the segment priced next
+}
+
+func (p *partialPricer) price(n int, score func(j int) float64, epsilon float64) int {
+	if n == 0 {
+		return -1
+	}
+	size := max((n+partialSegments-1)/partialSegments, partialMinimum)
+	for scanned := 0; scanned < n; scanned += size {
+		s, best := -1, epsilon
+		for k := range min(size, n-scanned) {
+			j := (p.start + scanned + k) % n
+			if v := score(j); v > best {
+				s, best = j, v
+			}
+		}
+		if s != -1 {
+			p.start = (p.start + scanned) % n
+			return s
+		}
+	}
+	return -1
+}
+
+func (p *partialPricer) update(*pricingUpdate) error { return nil }
+
+// devexPricer divides each squared reduced cost by an approximate squared
+// edge length measured in a reference framework that starts as the nonbasic
+// columns, following Forrest and Goldfarb.
+type devexPricer struct {
+	weights []float64
+}
+
+func newDevex(n int) *devexPricer {
+	w := make([]float64, n)
+	for j := range w {
+		w[j] = 1
+	}
+	return &devexPricer{weights: w}
+}
+
+func (p *devexPricer) price(n int, score func(j int) float64, epsilon float64) int {
+	return weightedPrice(p.weights, n, score, epsilon)
+}
+
+func (p *devexPricer) update(pu *pricingUpdate) error {
+	rho, pivot, err := pu.pivotRow()
+	if err != nil {
+		return err
+	}
+	wq := p.weights[pu.s]
+	for j := range pu.isbasic {
+		if pu.isbasic[j] || j == pu.s {
+			continue
+		}
+		ratio := matrix.ColDot(pu.A, j, rho) / pivot
+		p.weights[j] = math.Max(p.weights[j], ratio*ratio*wq)
+	}
+	if pu.leaving < len(p.weights) {
+		p.weights[pu.leaving] = math.Max(wq/(pivot*pivot), 1)
+	}
+	return nil
+}
+
+// steepestEdgePricer divides each squared reduced cost by the exact squared
+// length 1 + ||B^-1 a_j||^2 of the edge along which column j enters. The
+// weights are kept up to date with the recurrences of Goldfarb and Reid.
+type steepestEdgePricer struct {
+	weights []float64
+}
+
+func newSteepestEdge(A mat.Matrix, factor *basisFactor, isbasic []bool) (*steepestEdgePricer, error) {
+	m, _ := A.Dims()
+	w := make([]float64, len(isbasic))
+	col := mat.NewVecDense(m, nil)
+	for j := range isbasic {
+		w[j] = 1
+		if isbasic[j] {
+			continue
+		}
+		matrix.ColInto(col, A, j)
+		d, err := factor.ftran(col)
+		if err != nil {
+			return nil, errors.New(errors.ErrNumericalFailure, "error computing steepest edge weights", err)
+		}
+		w[j] += mat.Dot(d, d)
+	}
+	return &steepestEdgePricer{weights: w}, nil
+}
+
+func (p *steepestEdgePricer) price(n int, score func(j int) float64, epsilon float64) int {
+	return weightedPrice(p.weights, n, score, epsilon)
+}
+
+func (p *steepestEdgePricer) update(pu *pricingUpdate) error {
+	rho, pivot, err := pu.pivotRow()
+	if err != nil {
+		return err
+	}
+	// w = B^-T B^-1 a_s gives a_j^T w = (B^-1 a_j)^T (B^-1 a_s)
+	w, err := pu.factor.btran(pu.direction)
+	if err != nil {
+		return errors.New(errors.ErrNumericalFailure, "error updating steepest edge weights", err)
+	}
+
+	gq := 1 + mat.Dot(pu.direction, pu.direction)
+	for j := range pu.isbasic {
+		if pu.isbasic[j] || j == pu.s {
+			continue
+		}
+		ratio := matrix.ColDot(pu.A, j, rho) / pivot
+		if ratio == 0 {
+			continue
+		}
+		g := p.weights[j] - 2*ratio*matrix.ColDot(pu.A, j, w) + ratio*ratio*gq
+		p.weights[j] = math.Max(g, 1+ratio*ratio)
+	}
+	if pu.leaving < len(p.weights) {
+		p.weights[pu.leaving] = math.Max(gq/(pivot*pivot), 1)
+	}
+	return nil
+}
+
+// weightedPrice takes the column maximising score^2 / weight.
+func weightedPrice(weights []float64, n int, score func(j int) float64, epsilon float64) int {
+	s, best := -1, 0.
+	for j := range n {
+		v := score(j)
+		if v <= epsilon {
+			continue
+		}
+		if v = v * v / weights[j]; v > best {
+			s, best = j, v
+		}
+	}
+	return s
+}
+
+// pivotRow returns row r of B^-1 and the pivot element of the update.
+func (pu *pricingUpdate) pivotRow() (*mat.VecDense, float64, error) {
+	er := mat.NewVecDense(pu.direction.Len(), nil)
+	er.SetVec(pu.r, 1)
+	rho, err := pu.factor.btran(er)
+	if err != nil {
+		return nil, 0, errors.New(errors.ErrNumericalFailure, "error solving for pivot row", err)
+	}
+	return rho, pu.direction.AtVec(pu.r), nil
+}
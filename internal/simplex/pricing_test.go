@@ -0,0 +1,93 @@
+package simplex
+
+import (
+	"testing"
+
+	"github.com/chriso345/gore/assert"
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/matrix"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestPartialPricer(t *testing.T) {
+	scores := make([]float64, 100)
+	scores[10] = 1
+	scores[20] = 3
+	scores[70] = 5
+	score := func(j int) float64 { return scores[j] }
+
+	// The first segment holds columns 0-31, so column 70 is not seen
+	p := &partialPricer{}
+	assert.Equal(t, p.price(100, score, 1e-9), 20)
+
+	// Once that segment is exhausted the next ones are scanned
+	scores[10], scores[20] = 0, 0
+	assert.Equal(t, p.price(100, score, 1e-9), 70)
+	assert.Equal(t, p.start, 64)
+
+	scores[70] = 0
+	assert.Equal(t, p.price(100, score, 1e-9), -1)
+}
+
+func TestWeightedPrice(t *testing.T) {
+	score := func(j int) float64 { return []float64{2, 3, 0}[j] }
+	assert.Equal(t, weightedPrice([]float64{1, 1, 1}, 3, score, 1e-9), 1)
+	assert.Equal(t, weightedPrice([]float64{1, 4, 1}, 3, score, 1e-9), 0)
+}
+
+func TestSteepestEdgeUpdate(t *testing.T) {
+	A := mat.NewDense(2, 4, []float64{
+		1, 2, 1, 0,
+		3, 1, 0, 1,
+	})
+	isbasic := []bool{false, false, true, true}
+	factor, err := newBasisFactor(A, mat.NewVecDense(2, []float64{2, 3}))
+	assert.Nil(t, err)
+
+	se, err := newSteepestEdge(A, factor, isbasic)
+	assert.Nil(t, err)
+	assert.Equal(t, se.weights[0], 11.)
+	assert.Equal(t, se.weights[1], 6.)
+
+	// Column 0 replaces column 3 in row 1
+	as := mat.NewVecDense(2, []float64{1, 3})
+	direction, err := factor.ftran(as)
+	assert.Nil(t, err)
+	assert.Nil(t, se.update(&pricingUpdate{
+		A:         A,
+		factor:    factor,
+		isbasic:   isbasic,
+		direction: direction,
+		s:         0,
+		r:         1,
+		leaving:   3,
+	}))
+
+	// The updated weights match 1 + ||B^-1 a_j||^2 for the new basis
+	B := matrix.ExtractColumns(A, mat.NewVecDense(2, []float64{2, 0}))
+	col := mat.NewVecDense(2, nil)
+	for _, j := range []int{1, 3} {
+		matrix.ColInto(col, A, j)
+		var d mat.VecDense
+		assert.Nil(t, d.SolveVec(B, col))
+		assert.IsClose(t, se.weights[j], 1+mat.Dot(&d, &d), 1e-9)
+	}
+}
+
+func TestRSMPricingRules(t *testing.T) {
+	rules := []common.Pricing{
+		common.PricingDantzig,
+		common.PricingSteepestEdge,
+		common.PricingDevex,
+		common.PricingPartial,
+	}
+	for _, rule := range rules {
+		config := common.DefaultSolverConfig()
+		config.Pricing = rule
+
+		scf := newDualTestSCF()
+		assert.Nil(t, Simplex(scf, config))
+		assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+		assert.IsClose(t, *scf.ObjectiveValue, 9, 1e-9)
+	}
+}
@@ -0,0 +1,147 @@
+package simplex
+
+import (
+	"math"
+
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/errors"
+	"github.com/chriso345/gspl/internal/matrix"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Ranging holds the allowable decrease and increase of each objective
+// coefficient and right-hand side of a minimisation over which the optimal
+// basis stays optimal. All entries are non-negative and may be +Inf.
+type Ranging struct {
+	CostDecrease *mat.VecDense // one entry per column of A
+	CostIncrease *mat.VecDense
+	RHSDecrease  *mat.VecDense // one entry per row of A
+	RHSIncrease  *mat.VecDense
+}
+
+// Sensitivity performs objective and right-hand side ranging on the final
+// basis that Simplex recorded on scf.
+//
+// Objective ranging keeps every nonbasic reduced cost at the sign required
+// for optimality; RHS ranging keeps every basic variable within its bounds.
+func Sensitivity(scf *common.StandardComputationalForm) (*Ranging, error) {
+	if scf.Basis == nil || scf.ReducedCosts == nil || scf.PrimalSolution == nil {
+		return nil, errors.New(errors.ErrInvalidInput, "no optimal basis available for sensitivity analysis", nil)
+	}
+	m, n := scf.Constraints.Dims()
+
+	// Factorise B. Artificial columns are unit vectors; their sign only scales
+	// the matching row of B^-1, which never affects the ranges below.
+	signs := make([]float64, m)
+	for i := range m {
+		signs[i] = 1.
+	}
+	basis := mat.NewVecDense(m, nil)
+	position := make([]int, n) // basis row of each column, or -1 when nonbasic
+	for j := range n {
+		position[j] = -1
+	}
+	for r, j := range scf.Basis {
+		basis.SetVec(r, float64(j))
+		if j < n {
+			position[j] = r
+		}
+	}
+	factor, err := newBasisFactor(auxiliaryMatrix(scf.Constraints, signs), basis)
+	if err != nil {
+		return nil, errors.New(errors.ErrNumericalFailure, "final basis is singular", err)
+	}
+	unit := mat.NewVecDense(m, nil)
+
+	rg := &Ranging{
+		CostDecrease: mat.NewVecDense(n, nil),
+		CostIncrease: mat.NewVecDense(n, nil),
+		RHSDecrease:  mat.NewVecDense(m, nil),
+		RHSIncrease:  mat.NewVecDense(m, nil),
+	}
+
+	for j := range n {
+		dec, inc := math.Inf(1), math.Inf(1)
+		lower, upper := scf.Bounds(j)
+
+		if r := position[j]; r < 0 {
+			// A nonbasic cost only moves its own reduced cost
+			d := scf.ReducedCosts.AtVec(j)
+			switch {
+			case lower == upper:
+			case math.IsInf(lower, -1) && math.IsInf(upper, 1):
+				dec, inc = 0, 0
+			case scf.AtUpper[j]:
+				inc = math.Max(-d, 0)
+			default:
+				dec = math.Max(d, 0)
+			}
+		} else {
+			// A basic cost shifts every nonbasic reduced cost by -delta*alpha_rk,
+			// where alpha_r = e_r^T B^-1 A is the tableau row of the basic variable
+			unit.SetVec(r, 1.)
+			row, err := factor.btran(unit)
+			unit.SetVec(r, 0.)
+			if err != nil {
+				return nil, errors.New(errors.ErrNumericalFailure, "error solving for a row of the basis inverse", err)
+			}
+			for k := range n {
+				if position[k] >= 0 {
+					continue
+				}
+				kl, ku := scf.Bounds(k)
+				if kl == ku {
+					continue
+				}
+				a := matrix.ColDot(scf.Constraints, k, row)
+				if math.Abs(a) < pivotTolerance {
+					continue
+				}
+				if math.IsInf(kl, -1) && math.IsInf(ku, 1) {
+					dec, inc = 0, 0
+					break
+				}
+				limit := math.Abs(scf.ReducedCosts.AtVec(k) / a)
+				if (a > 0) != scf.AtUpper[k] {
+					inc = math.Min(inc, limit)
+				} else {
+					dec = math.Min(dec, limit)
+				}
+			}
+		}
+		rg.CostDecrease.SetVec(j, dec)
+		rg.CostIncrease.SetVec(j, inc)
+	}
+
+	for i := range m {
+		// Moving b_i by delta moves the basic variables by delta * B^-1 e_i
+		unit.SetVec(i, 1.)
+		col, err := factor.ftran(unit)
+		unit.SetVec(i, 0.)
+		if err != nil {
+			return nil, errors.New(errors.ErrNumericalFailure, "error solving for a column of the basis inverse", err)
+		}
+		dec, inc := math.Inf(1), math.Inf(1)
+		for r, j := range scf.Basis {
+			beta := col.AtVec(r)
+			if math.Abs(beta) < pivotTolerance {
+				continue
+			}
+			x, lower, upper := 0., 0., 0. // basic artificials must stay at zero
+			if j < n {
+				x = scf.PrimalSolution.AtVec(j)
+				lower, upper = scf.Bounds(j)
+			}
+			up, down := (upper-x)/beta, (x-lower)/beta
+			if beta < 0 {
+				up, down = (x-lower)/-beta, (upper-x)/-beta
+			}
+			inc = math.Min(inc, math.Max(up, 0))
+			dec = math.Min(dec, math.Max(down, 0))
+		}
+		rg.RHSDecrease.SetVec(i, dec)
+		rg.RHSIncrease.SetVec(i, inc)
+	}
+
+	return rg, nil
+}
@@ -0,0 +1,53 @@
+package simplex
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chriso345/gore/assert"
+	"github.com/chriso345/gspl/internal/common"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSensitivityBoundedVariables(t *testing.T) {
+	// Minimize: -x1 - 2*x2
+	// Subject to: x1 + x2 + s = 10, 0 <= x1 <= 3, -1 <= x2 <= 4
+	objVal := 0.
+	status := common.SolverStatusNotSolved
+	scf := &common.StandardComputationalForm{
+		Objective:      mat.NewVecDense(3, []float64{-1, -2, 0}),
+		Constraints:    mat.NewDense(1, 3, []float64{1, 1, 1}),
+		RHS:            mat.NewVecDense(1, []float64{10}),
+		Lower:          mat.NewVecDense(3, []float64{0, -1, 0}),
+		Upper:          mat.NewVecDense(3, []float64{3, 4, math.Inf(1)}),
+		ObjectiveValue: &objVal,
+		Status:         &status,
+	}
+	assert.Nil(t, Simplex(scf, common.DefaultSolverConfig()))
+
+	rg, err := Sensitivity(scf)
+	assert.Nil(t, err)
+
+	// x1 and x2 rest at their upper bounds until their costs turn positive
+	assert.True(t, math.IsInf(rg.CostDecrease.AtVec(0), 1))
+	assert.IsClose(t, rg.CostIncrease.AtVec(0), 1, 1e-9)
+	assert.True(t, math.IsInf(rg.CostDecrease.AtVec(1), 1))
+	assert.IsClose(t, rg.CostIncrease.AtVec(1), 2, 1e-9)
+
+	// The basic slack may grow cheaper only until x1 prefers its lower bound
+	assert.IsClose(t, rg.CostDecrease.AtVec(2), 1, 1e-9)
+	assert.True(t, math.IsInf(rg.CostIncrease.AtVec(2), 1))
+
+	assert.IsClose(t, rg.RHSDecrease.AtVec(0), 3, 1e-9)
+	assert.True(t, math.IsInf(rg.RHSIncrease.AtVec(0), 1))
+}
+
+func TestSensitivityWithoutBasis(t *testing.T) {
+	scf := &common.StandardComputationalForm{
+		Objective:   mat.NewVecDense(1, []float64{1}),
+		Constraints: mat.NewDense(1, 1, []float64{1}),
+		RHS:         mat.NewVecDense(1, []float64{1}),
+	}
+	_, err := Sensitivity(scf)
+	assert.NotNil(t, err)
+}
@@ -1,7 +1,9 @@
 package simplex
 
 import (
+	"log/slog"
 	"math"
+	"time"
 
 	"github.com/chriso345/gspl/internal/common"
 	"github.com/chriso345/gspl/internal/errors"
@@ -9,26 +11,69 @@ import (
 	"gonum.org/v1/gonum/mat"
 )
 
+// pivotTolerance is the smallest direction entry accepted in the ratio test.
+const pivotTolerance = 1e-9
+
+// degenerateLimit is the number of consecutive degenerate pivots after which
+// the primal and dual simplex fall back to Bland's smallest-index rule, which
+// cannot cycle. Their usual rules are restored by the next pivot that makes
+// progress.
+const degenerateLimit = 20
+
+// phaseDual is the phase a run of the dual simplex is recorded under.
+const phaseDual = 0
+
+// Simplex solves scf with the two-phase bounded revised simplex method.
+// Phase 1 starts from a crash basis: each row with a slack column that can
+// take up its residual starts from that slack, and only the remaining rows,
+// typically = and >= rows, are given an artificial variable to drive out.
 func Simplex(scf *common.StandardComputationalForm, config *common.SolverConfig) error {
+	// A basis carried over from an earlier solve can stand in for Phase 1
+	if warm, err := warmSimplex(scf, config); warm || err != nil {
+		return err
+	}
+
 	m, n := scf.Constraints.Dims()
 	sm := &simplexMethod{
-		m: m,
-		n: n,
+		m:     m,
+		n:     n,
+		stats: scf.Stats,
 	}
 
-	I := matrix.Eye(m)
+	// Bounds over the structural and artificial columns. Nonbasic columns start
+	// at their lower bound, or at the upper bound when no lower bound exists.
+	sm.lower = mat.NewVecDense(n+m, nil)
+	sm.upper = mat.NewVecDense(n+m, nil)
+	sm.atUpper = make([]bool, n+m)
+	for j := range n + m {
+		lower, upper := 0., math.Inf(1)
+		if j < n {
+			lower, upper = scf.Bounds(j)
+		}
+		if lower > upper+config.Tolerance {
+			*scf.Status = common.SolverStatusInfeasible
+			return nil
+		}
+		sm.lower.SetVec(j, lower)
+		sm.upper.SetVec(j, upper)
+		sm.atUpper[j] = math.IsInf(lower, -1) && !math.IsInf(upper, 1)
+	}
 
-	// Phase 1: Set up the auxilary problem
-	sm.A = mat.NewDense(m, n+m, nil)
+	// Artificial columns take the sign of the initial residual so that each
+	// artificial variable starts basic and non-negative.
+	sm.b = scf.RHS
+	residual := sm.nonbasicRHS(scf.Constraints, n)
+	signs := make([]float64, m)
 	for i := range m {
-		for j := range n {
-			sm.A.Set(i, j, scf.Constraints.At(i, j))
-		}
-		for j := range m {
-			sm.A.Set(i, n+j, I.At(i, j))
+		signs[i] = 1.
+		if residual.AtVec(i) < 0 {
+			signs[i] = -1.
 		}
 	}
 
+	// Phase 1: Set up the auxilary problem
+	sm.A = auxiliaryMatrix(scf.Constraints, signs)
+
 	// Construct the cost vector for Phase 1, [0,...,0,1,...,1]
 	sm.c = mat.NewVecDense(n+m, nil)
 	for i := range n + m {
@@ -39,14 +84,21 @@ func Simplex(scf *common.StandardComputationalForm, config *common.SolverConfig)
 		}
 	}
 
+	// Crash: rows with a slack column start from it, and only the remaining
+	// rows start from their artificial. The artificial of a crashed row is
+	// fixed at zero, so Phase 1 never brings it into the basis.
+	crash := sm.crashBasis(matrix.AsCSC(scf.Constraints), scf.Objective, residual, config.Tolerance)
 	sm.cb = mat.NewVecDense(m, nil)
-	for i := range m {
-		sm.cb.SetVec(i, float64(n+i)) // Artificial variables as initial basis
+	for i, j := range crash {
+		if j == -1 {
+			sm.cb.SetVec(i, float64(n+i))
+			continue
+		}
+		sm.cb.SetVec(i, float64(j))
+		sm.upper.SetVec(n+i, 0)
+		sm.atUpper[j] = false
 	}
 
-	sm.B = matrix.ExtractColumns(sm.A, sm.cb)
-	sm.b = scf.RHS
-
 	// Keep original constraints pointer so we can detect changes later (cheap check)
 	origConstraints := scf.Constraints
 
@@ -55,10 +107,19 @@ func Simplex(scf *common.StandardComputationalForm, config *common.SolverConfig)
 	if err != nil {
 		return errors.New(errors.ErrNumericalFailure, "error in Phase 1 of Simplex", err)
 	}
+	if sm.flag.Stopped() {
+		sm.storeSolution(scf)
+		return nil
+	}
 
-	// Check infeasibility
+	// Check infeasibility. The Phase 1 duals y prove it: every column at its
+	// lower bound has a^T y <= 0 and every column at its upper bound
+	// a^T y >= 0, so y^T A x is largest at the Phase 1 point, where it falls
+	// short of y^T b by the sum of the artificials.
 	if sm.flag == common.SolverStatusOptimal && sm.value > config.Tolerance {
-		*scf.Status = common.SolverStatusInfeasible
+		sm.flag = common.SolverStatusInfeasible
+		sm.ray = mat.VecDenseCopyOf(sm.pi)
+		sm.storeSolution(scf)
 		return nil
 	}
 
@@ -72,15 +133,7 @@ func Simplex(scf *common.StandardComputationalForm, config *common.SolverConfig)
 
 	// This _should_ always be true, but just in case
 	if scf.Constraints != origConstraints {
-		sm.A = mat.NewDense(m, n+m, nil)
-		for i := range m {
-			for j := range n {
-				sm.A.Set(i, j, scf.Constraints.At(i, j))
-			}
-			for j := range m {
-				sm.A.Set(i, n+j, I.At(i, j))
-			}
-		}
+		sm.A = auxiliaryMatrix(scf.Constraints, signs)
 	}
 
 	sm.c = mat.NewVecDense(n+m, nil)
@@ -92,24 +145,6 @@ func Simplex(scf *common.StandardComputationalForm, config *common.SolverConfig)
 	}
 
 	sm.cb = sm.indices
-	sm.B = matrix.ExtractColumns(sm.A, sm.cb)
-	// If the basis is singular, try to repair by swapping in non-basic original columns
-	var inv mat.Dense
-	if err := inv.Inverse(sm.B); err != nil {
-		for i := 0; i < sm.m; i++ {
-			idx := int(sm.indices.AtVec(i))
-			if idx >= sm.n { // artificial
-				for j := 0; j < sm.n; j++ {
-					if !contains(sm.indices, j) && sm.A.At(i, j) != 0 {
-						sm.indices.SetVec(i, float64(j))
-						break
-					}
-				}
-			}
-		}
-		// Rebuild B after repair attempts
-		sm.B = matrix.ExtractColumns(sm.A, sm.cb)
-	}
 	// Reuse RHS from Phase 1 (sm.b already points to scf.RHS) to avoid extra allocations
 	sm.b = scf.RHS
 
@@ -118,19 +153,147 @@ func Simplex(scf *common.StandardComputationalForm, config *common.SolverConfig)
 	if err != nil {
 		return errors.New(errors.ErrNumericalFailure, "error in Phase 2 of Simplex", err)
 	}
+	sm.storeSolution(scf)
+
+	return nil
+}
+
+// storeSolution writes the status of the final RSM run to scf and, when it is
+// optimal, the primal and dual solutions and the final basis. A run stopped
+// by a limit records its last point and basis without duals, and an
+// infeasible or unbounded run its certificate.
+func (sm *simplexMethod) storeSolution(scf *common.StandardComputationalForm) {
 	*scf.Status = sm.flag
-	if sm.flag == common.SolverStatusOptimal {
-		*scf.ObjectiveValue = sm.value
-		scf.PrimalSolution = sm.x
-		// debug removed
+	scf.FarkasRay, scf.UnboundedRay = nil, nil
+	switch sm.flag {
+	case common.SolverStatusInfeasible:
+		scf.FarkasRay = sm.ray
+		return
+	case common.SolverStatusUnbounded:
+		scf.UnboundedRay = sm.ray
+		return
+	}
+	if sm.flag != common.SolverStatusOptimal && !sm.flag.Stopped() {
+		return
 	}
 
-	return nil
+	scf.Basis = make([]int, sm.m)
+	for i := range sm.m {
+		scf.Basis[i] = int(sm.indices.AtVec(i))
+	}
+	scf.AtUpper = make([]bool, sm.n)
+	copy(scf.AtUpper, sm.atUpper)
+
+	if sm.flag.Stopped() {
+		// The point may still be in Phase 1, so value it by the true objective
+		scf.PrimalSolution = mat.NewVecDense(sm.n, nil)
+		for j := range sm.n {
+			scf.PrimalSolution.SetVec(j, sm.x.AtVec(j))
+		}
+		*scf.ObjectiveValue = mat.Dot(scf.Objective, scf.PrimalSolution)
+		return
+	}
+
+	*scf.ObjectiveValue = sm.value
+	scf.PrimalSolution = sm.x
+	scf.DualSolution = mat.VecDenseCopyOf(sm.pi)
+	scf.ReducedCosts = reducedCosts(scf.Constraints, scf.Objective, sm.pi)
 }
 
-func RSM(sm *simplexMethod, phase int, config *common.SolverConfig) error {
-	_maxIter := 1000 // Simple safeguard
+// reducedCosts returns c - A^T pi for every column of A.
+func reducedCosts(A mat.Matrix, c, pi *mat.VecDense) *mat.VecDense {
+	_, n := A.Dims()
+	d := mat.NewVecDense(n, nil)
+	for j := range n {
+		d.SetVec(j, c.AtVec(j)-matrix.ColDot(A, j, pi))
+	}
+	return d
+}
+
+// auxiliaryMatrix returns [A | diag(signs)], the constraint matrix extended by
+// one artificial column per row, held sparse.
+func auxiliaryMatrix(constraints mat.Matrix, signs []float64) *matrix.CSC {
+	return matrix.CSCHStack(matrix.AsCSC(constraints), matrix.CSCDiag(signs))
+}
+
+// columnBounds returns the bounds of column j. Missing vectors, or columns
+// beyond their length, default to x >= 0.
+func columnBounds(lower, upper *mat.VecDense, j int) (float64, float64) {
+	l, u := 0., math.Inf(1)
+	if lower != nil && j < lower.Len() {
+		l = lower.AtVec(j)
+	}
+	if upper != nil && j < upper.Len() {
+		u = upper.AtVec(j)
+	}
+	return l, u
+}
 
+// nonbasicValue returns the value of a nonbasic column: the bound it rests at,
+// or zero for a free column.
+func (sm *simplexMethod) nonbasicValue(j int) float64 {
+	lower, upper := columnBounds(sm.lower, sm.upper, j)
+	if sm.atUpper != nil && sm.atUpper[j] && !math.IsInf(upper, 1) {
+		return upper
+	}
+	if !math.IsInf(lower, -1) {
+		return lower
+	}
+	return 0.
+}
+
+// nonbasicRHS returns b - N*xN for the first n columns of A, treating every
+// column that sm.isbasic does not mark as nonbasic.
+func (sm *simplexMethod) nonbasicRHS(A mat.Matrix, n int) *mat.VecDense {
+	rhs := mat.VecDenseCopyOf(sm.b)
+	for j := range n {
+		if sm.isbasic != nil && sm.isbasic[j] {
+			continue
+		}
+		v := sm.nonbasicValue(j)
+		if v == 0 {
+			continue
+		}
+		matrix.AddScaledCol(rhs, -v, A, j)
+	}
+	return rhs
+}
+
+// markBasic rebuilds sm.isbasic from the basis in sm.indices. Every pivot
+// keeps it current from then on.
+func (sm *simplexMethod) markBasic() {
+	sm.isbasic = make([]bool, sm.n+sm.m)
+	for i := range sm.m {
+		if j := int(sm.indices.AtVec(i)); j >= 0 && j < len(sm.isbasic) {
+			sm.isbasic[j] = true
+		}
+	}
+}
+
+// maxIterations returns the pivot limit of config. A limit that is not
+// positive, as in a zero SolverConfig, means no limit.
+func maxIterations(config *common.SolverConfig) int {
+	if config.MaxIterations <= 0 {
+		return math.MaxInt
+	}
+	return config.MaxIterations
+}
+
+// stopStatus returns the limit status the solve must stop with before its
+// next pivot, or SolverStatusNotSolved to carry on.
+func (sm *simplexMethod) stopStatus(config *common.SolverConfig) common.SolverStatus {
+	if sm.iterations >= maxIterations(config) {
+		return common.SolverStatusIterationLimit
+	}
+	return common.StopStatus(config.Ctx)
+}
+
+// RSM runs one phase of the revised simplex method from the basis in sm.cb.
+// Column bounds are handled directly: nonbasic variables rest at either bound
+// and the ratio test accounts for bound flips, so bounds never become explicit
+// rows. It stops with the current basic solution once sm.iterations reaches
+// config.MaxIterations or config.Ctx is done; see stopStatus.
+func RSM(sm *simplexMethod, phase int, config *common.SolverConfig) error {
 	n := sm.n
 	if phase == 1 {
 		n += sm.m
@@ -141,29 +304,60 @@ func RSM(sm *simplexMethod, phase int, config *common.SolverConfig) error {
 	sm.value = 0. // z
 	sm.x = mat.NewVecDense(n, nil)
 	sm.indices = sm.cb
+	sm.markBasic()
+	if sm.atUpper == nil {
+		sm.atUpper = make([]bool, sm.c.Len())
+	}
 
 	// Initialise other variables
-	B := sm.B // RSM mutates B in-place and owns the basis; updateB writes into this matrix
 	cb := mat.NewVecDense(sm.m, nil)
 	for i := range sm.m {
 		index := int(sm.indices.AtVec(i))
 		cb.SetVec(i, sm.c.AtVec(index))
 	}
 
-	for range _maxIter {
-		xb := mat.NewVecDense(sm.m, nil) // Basic solution
-		err := xb.SolveVec(B, sm.b)
-		if err != nil {
-			// Basis is singular, return error
-			return errors.New(errors.ErrNumericalFailure, "error solving for basic solution", err)
-		}
+	start, pivots := time.Now(), sm.iterations
+	var factor *basisFactor
+	defer func() {
+		elapsed := time.Since(start)
+		sm.record(phase, elapsed, sm.iterations-pivots, factor)
+		sm.logFinish(config, phase, elapsed, sm.iterations-pivots)
+	}()
+	config.Notify(common.Event{Kind: common.EventPhase, Phase: eventPhase(phase), Iterations: sm.iterations})
+	config.Log().LogAttrs(config.Ctx, slog.LevelDebug, "simplex phase started",
+		slog.String("phase", eventPhase(phase).String()),
+		slog.Int("rows", sm.m),
+		slog.Int("columns", n),
+	)
+
+	factor, err := newBasisFactor(sm.A, sm.indices)
+	if err != nil {
+		return errors.New(errors.ErrNumericalFailure, "error factorising basis matrix", err)
+	}
 
-		// Finding the leaving variable
-		var BT mat.Dense
-		BT.CloneFrom(B.T())
+	isbasic := sm.isbasic[:n]
+	pr, err := newPricer(config.Pricing, sm.A, factor, isbasic)
+	if err != nil {
+		return err
+	}
+
+	// The basic solution is solved for here and after each refactorisation,
+	// and otherwise moved along the direction of each step
+	xb, err := factor.ftran(sm.nonbasicRHS(sm.A, n))
+	if err != nil {
+		// Basis is singular, return error
+		return errors.New(errors.ErrNumericalFailure, "error solving for basic solution", err)
+	}
 
-		sm.pi = mat.NewVecDense(sm.m, nil) // Dual variables
-		err = sm.pi.SolveVec(&BT, cb)
+	degenerate := 0 // Consecutive pivots with a zero step
+	for {
+		if stop := sm.stopStatus(config); stop != common.SolverStatusNotSolved {
+			sm.flag = stop
+			sm.setPoint(xb, n)
+			return nil
+		}
+
+		sm.pi, err = factor.btran(cb) // Dual variables
 		if err != nil {
 			// Basis is singular, return error
 			return errors.New(errors.ErrNumericalFailure, "error solving for dual variables", err)
@@ -173,16 +367,14 @@ func RSM(sm *simplexMethod, phase int, config *common.SolverConfig) error {
 			A:       sm.A,
 			pi:      sm.pi,
 			c:       sm.c,
-			isbasic: mat.NewVecDense(n, nil),
+			lower:   sm.lower,
+			upper:   sm.upper,
+			atUpper: sm.atUpper,
+			isbasic: isbasic,
 
 			epsilon: config.Tolerance,
-		}
-
-		for i := range sm.m {
-			index := int(sm.indices.AtVec(i))
-			if index < n {
-				fe.isbasic.SetVec(index, 1.)
-			}
+			bland:   degenerate >= degenerateLimit,
+			pricer:  pr,
 		}
 
 		err = findEnter(&fe)
@@ -193,25 +385,26 @@ func RSM(sm *simplexMethod, phase int, config *common.SolverConfig) error {
 		if fe.s == -1 {
 			// Optimal solution found
 			sm.flag = common.SolverStatusOptimal
-			sm.value = 0.
-			for ii := 0; ii < sm.m; ii++ {
-				index := int(sm.indices.AtVec(ii))
-				if index >= 0 && index < sm.x.Len() {
-					sm.x.SetVec(index, xb.AtVec(ii))
-				}
-				sm.value += cb.AtVec(ii) * xb.AtVec(ii)
-			}
+			sm.setPoint(xb, n)
 			return nil
 		}
+		sm.iterations++
+		sm.progress(config, phase, xb, n)
 
 		// Finding the leaving variable
 		fl := leavingVariable{
-			B:       B,
-			indices: sm.indices,
-			as:      fe.as,
-			xb:      xb,
-			phase:   phase,
-			n:       n,
+			A:          sm.A,
+			factor:     factor,
+			indices:    sm.indices,
+			as:         fe.as,
+			xb:         xb,
+			phase:      phase,
+			n:          n,
+			lower:      sm.lower,
+			upper:      sm.upper,
+			s:          fe.s,
+			decreasing: fe.decreasing,
+			bland:      fe.bland,
 		}
 
 		err = findLeave(&fl)
@@ -219,21 +412,64 @@ func RSM(sm *simplexMethod, phase int, config *common.SolverConfig) error {
 			return errors.New(errors.ErrNumericalFailure, "error finding leaving variable", err)
 		}
 
+		if fl.r != -1 && fl.theta < pivotTolerance {
+			degenerate++
+		} else {
+			degenerate = 0
+		}
+
+		// A step of theta in the entering variable moves the basic variables
+		// by -theta*direction
+		step := fl.theta
+		if fe.decreasing {
+			step = -step
+		}
+
+		if fl.flip {
+			// The entering variable moves to its opposite bound; the basis is unchanged
+			xb.AddScaledVec(xb, -step, fl.direction)
+			sm.atUpper[fe.s] = !fe.decreasing
+			continue
+		}
+
 		if fl.r == -1 {
 			// Unbounded solution
 			sm.flag = common.SolverStatusUnbounded
+			sm.ray = sm.unboundedRay(&fl)
 			// Set primal solution vector to zero
 			sm.x = mat.NewVecDense(n, nil)
 			sm.value = 0.
 			return nil
 		}
 
+		leaving := int(sm.indices.AtVec(fl.r))
+		pu := pricingUpdate{
+			A:         sm.A,
+			factor:    factor,
+			isbasic:   isbasic,
+			direction: fl.direction,
+			s:         fe.s,
+			r:         fl.r,
+			leaving:   leaving,
+		}
+		if err := pr.update(&pu); err != nil {
+			return errors.New(errors.ErrNumericalFailure, "error updating pricing weights", err)
+		}
+		sm.isbasic[fe.s] = true
+		sm.isbasic[leaving] = false
+
+		entering := sm.nonbasicValue(fe.s) + step
+		xb.AddScaledVec(xb, -step, fl.direction)
+		xb.SetVec(fl.r, entering)
+
+		// The leaving variable becomes nonbasic at the bound it reached
+		sm.atUpper[leaving] = fl.toUpper
+		sm.atUpper[fe.s] = false
+
 		// Update B, cb, and indices
 		bu := basisUpdate{
-			BMat:    B,
 			indices: sm.indices,
 			cb:      cb,
-			as:      fe.as,
 			s:       fe.s,
 			r:       fl.r,
 			cs:      fe.cs,
@@ -242,18 +478,155 @@ func RSM(sm *simplexMethod, phase int, config *common.SolverConfig) error {
 		if err := updateB(&bu); err != nil {
 			return errors.New(errors.ErrNumericalFailure, "error updating basis", err)
 		}
+		if err := factor.update(fl.r, fl.direction, sm.A, sm.indices); err != nil {
+			return errors.New(errors.ErrNumericalFailure, "error updating basis factorisation", err)
+		}
+		if factor.fresh() {
+			// Shed the rounding the updates of xb have gathered
+			if xb, err = factor.ftran(sm.nonbasicRHS(sm.A, n)); err != nil {
+				return errors.New(errors.ErrNumericalFailure, "error solving for basic solution", err)
+			}
+		}
+	}
+}
 
+// record adds the pivots, time and factorisations of one run of the simplex
+// method to sm.stats. Phase 1 and 2 are those of the primal simplex and
+// phaseDual marks a run of the dual simplex.
+func (sm *simplexMethod) record(phase int, elapsed time.Duration, pivots int, factor *basisFactor) {
+	st := sm.stats
+	if st == nil {
+		return
+	}
+	switch phase {
+	case 1:
+		st.Phase1Iterations += pivots
+		st.Phase1Time += elapsed
+	case 2:
+		st.Phase2Iterations += pivots
+		st.Phase2Time += elapsed
+	case phaseDual:
+		st.DualIterations += pivots
+		st.DualTime += elapsed
+	}
+	if factor != nil {
+		st.Refactorisations += factor.refactors
 	}
-	return errors.New(errors.ErrNumericalFailure, "max iterations reached in RSM", nil)
 }
 
+// progress reports the pivot just counted, which leaves the basic solution xb
+// over the first n columns: to the logger at debug level, and as an
+// EventIteration on every config.CallbackInterval-th pivot.
+func (sm *simplexMethod) progress(config *common.SolverConfig, phase int, xb *mat.VecDense, n int) {
+	if log := config.Log(); log.Enabled(config.Ctx, slog.LevelDebug) {
+		sm.setPoint(xb, n)
+		log.LogAttrs(config.Ctx, slog.LevelDebug, "simplex iteration",
+			slog.String("phase", eventPhase(phase).String()),
+			slog.Int("iteration", sm.iterations),
+			slog.Float64("objective", sm.value),
+			slog.Float64("infeasibility", sm.infeasibility(xb, phase)),
+		)
+	}
+
+	every := config.CallbackInterval
+	if config.Callback == nil || every <= 0 || sm.iterations%every != 0 {
+		return
+	}
+	sm.setPoint(xb, n)
+	config.Notify(common.Event{
+		Kind:       common.EventIteration,
+		Phase:      eventPhase(phase),
+		Iterations: sm.iterations,
+		Objective:  sm.value,
+	})
+}
+
+// infeasibility returns how far the basic solution xb is from feasible: its
+// objective, the sum of the artificial columns, in Phase 1 and the total
+// violation of the column bounds otherwise. sm.value must hold the objective.
+func (sm *simplexMethod) infeasibility(xb *mat.VecDense, phase int) float64 {
+	if phase == 1 {
+		return sm.value
+	}
+	total := 0.
+	for i := range sm.m {
+		lower, upper := columnBounds(sm.lower, sm.upper, int(sm.indices.AtVec(i)))
+		x := xb.AtVec(i)
+		total += math.Max(0, lower-x) + math.Max(0, x-upper)
+	}
+	return total
+}
+
+// logFinish records the end of a run of the simplex method at debug level.
+func (sm *simplexMethod) logFinish(config *common.SolverConfig, phase int, elapsed time.Duration, pivots int) {
+	config.Log().LogAttrs(config.Ctx, slog.LevelDebug, "simplex phase finished",
+		slog.String("phase", eventPhase(phase).String()),
+		slog.String("status", sm.flag.String()),
+		slog.Int("iterations", pivots),
+		slog.Float64("objective", sm.value),
+		slog.Duration("time", elapsed),
+	)
+}
+
+// eventPhase returns the Phase reported for a run of the simplex method.
+func eventPhase(phase int) common.Phase {
+	switch phase {
+	case 1:
+		return common.PhaseOne
+	case 2:
+		return common.PhaseTwo
+	default:
+		return common.PhaseDual
+	}
+}
+
+// unboundedRay returns the direction in which the failed ratio test fl lets
+// the entering column move without limit, over the structural columns. It
+// keeps Ad = 0, only moves columns towards an infinite bound, and lowers
+// the objective by the reduced cost of the entering column per unit step.
+func (sm *simplexMethod) unboundedRay(fl *leavingVariable) *mat.VecDense {
+	sign := 1.
+	if fl.decreasing {
+		sign = -1.
+	}
+	ray := mat.NewVecDense(sm.n, nil)
+	ray.SetVec(fl.s, sign)
+	for i := range sm.m {
+		if j := int(sm.indices.AtVec(i)); j < sm.n {
+			ray.SetVec(j, -sign*fl.direction.AtVec(i))
+		}
+	}
+	return ray
+}
+
+// setPoint records the solution of the current basis over the first n
+// columns, with basic values xb, and its objective value.
+func (sm *simplexMethod) setPoint(xb *mat.VecDense, n int) {
+	sm.value = 0.
+	for j := range n {
+		sm.x.SetVec(j, sm.nonbasicValue(j))
+	}
+	for i := range sm.m {
+		if index := int(sm.indices.AtVec(i)); index < n {
+			sm.x.SetVec(index, xb.AtVec(i))
+		}
+	}
+	for j := range n {
+		sm.value += sm.c.AtVec(j) * sm.x.AtVec(j)
+	}
+}
+
+// findEnter prices the nonbasic columns, by default with Dantzig's rule. A
+// column at its lower bound is attractive when its reduced cost is negative, a
+// column at its upper bound when it is positive, and a free column in either
+// case. Under Bland's rule the attractive column with the smallest index is
+// taken instead.
 func findEnter(fe *enteringVariable) error {
 	fe.s = -1
 	fe.cs = 0.
-	minrc := math.Inf(1)
-	tol := -fe.epsilon
+	fe.decreasing = false
 
-	n := fe.isbasic.Len()
+	n := len(fe.isbasic)
 	m, _ := fe.A.Dims()
 
 	// Reuse or allocate the 'as' vector once per call
@@ -261,83 +634,143 @@ func findEnter(fe *enteringVariable) error {
 		fe.as = mat.NewVecDense(m, nil)
 	}
 
-	for j := range n {
-		if fe.isbasic.AtVec(j) == 0 {
-			// Compute dot product without allocating a temporary vector
-			dot := 0.0
-			for i := range m {
-				dot += fe.pi.AtVec(i) * fe.A.At(i, j)
-			}
-			rc := fe.c.AtVec(j) - dot
-
-			if rc < minrc {
-				minrc = rc
+	score := func(j int) float64 {
+		v, _ := fe.score(j)
+		return v
+	}
+	switch {
+	case fe.bland:
+		for j := range n {
+			if score(j) > fe.epsilon {
 				fe.s = j
-				fe.cs = fe.c.AtVec(j)
-
-				// Reuse the preallocated as vector
-				for i := range m {
-					fe.as.SetVec(i, fe.A.At(i, j))
-				}
+				break
 			}
 		}
+	case fe.pricer != nil:
+		fe.s = fe.pricer.price(n, score, fe.epsilon)
+	default:
+		fe.s = dantzigPricer{}.price(n, score, fe.epsilon)
 	}
 
-	if minrc >= tol {
-		fe.s = -1
+	if fe.s == -1 {
 		for i := range m {
 			fe.as.SetVec(i, 0)
 		}
 		fe.cs = 0.
+		return nil
 	}
 
+	_, fe.decreasing = fe.score(fe.s)
+	fe.cs = fe.c.AtVec(fe.s)
+
+	// Reuse the preallocated as vector
+	matrix.ColInto(fe.as, fe.A, fe.s)
+
 	return nil
 }
 
+// score returns the rate at which column j improves the objective, or zero
+// when it cannot enter, and whether it enters by decreasing from its upper
+// bound.
+func (fe *enteringVariable) score(j int) (float64, bool) {
+	if fe.isbasic[j] {
+		return 0, false
+	}
+	lower, upper := columnBounds(fe.lower, fe.upper, j)
+	if lower == upper {
+		return 0, false // fixed columns never enter
+	}
+
+	// Only the nonzeros of a sparse column are visited
+	rc := fe.c.AtVec(j) - matrix.ColDot(fe.A, j, fe.pi)
+
+	atUpper := fe.atUpper != nil && fe.atUpper[j]
+	free := math.IsInf(lower, -1) && !atUpper
+
+	switch {
+	case atUpper || (free && rc > 0):
+		return rc, true
+	default:
+		return -rc, false
+	}
+}
+
+// findLeave performs the bounded ratio test. Each basic variable limits the step
+// of the entering variable by the distance to the bound it moves towards; the
+// entering variable is itself limited by the width of its own bounds, in which
+// case it simply flips to the opposite bound. Ties go to the first row, or
+// under Bland's rule to the basic column with the smallest index.
 func findLeave(fl *leavingVariable) error {
 	fl.r = -1
+	fl.flip = false
+	fl.toUpper = false
+
+	factor := fl.factor
+	if factor == nil {
+		var err error
+		if factor, err = newBasisFactor(fl.A, fl.indices); err != nil {
+			return errors.New(errors.ErrNumericalFailure, "error factorising basis matrix", err)
+		}
+	}
 
-	var Binv mat.Dense
-	if err := Binv.Inverse(fl.B); err != nil {
-		return errors.New(errors.ErrNumericalFailure, "error inverting basis matrix", err)
+	directionVec, err := factor.ftran(fl.as)
+	if err != nil {
+		return errors.New(errors.ErrNumericalFailure, "error solving for the entering direction", err)
 	}
+	fl.direction = directionVec
 
-	directionVec := mat.NewVecDense(fl.as.Len(), nil)
-	directionVec.MulVec(&Binv, fl.as)
+	// A step of t in the entering variable moves basic i by -sign*t*direction[i]
+	sign := 1.
+	if fl.decreasing {
+		sign = -1.
+	}
 
-	m := fl.xb.Len()
-	theta := math.Inf(1)
+	lower, upper := columnBounds(fl.lower, fl.upper, fl.s)
+	theta := upper - lower // +Inf when either bound is infinite
 
+	m := fl.xb.Len()
 	for i := range m {
 		dirVal := directionVec.AtVec(i)
 		indexVal := int(fl.indices.AtVec(i))
 
-		if fl.phase == 2 && indexVal > fl.n {
+		if fl.phase == 2 && indexVal >= fl.n {
+			// Artificial variables still in the basis must leave as soon as they would move
 			if dirVal != 0 {
 				fl.r = i
+				fl.theta = 0
 				return nil
 			}
-		} else {
-			if dirVal > 0 {
-				ratio := fl.xb.AtVec(i) / dirVal
-				if ratio < theta {
-					theta = ratio
-					fl.r = i
-				}
-			}
+			continue
+		}
+
+		delta := -sign * dirVal
+		l, u := columnBounds(fl.lower, fl.upper, indexVal)
+		ratio, toUpper := 0., false
+		switch {
+		case delta < -pivotTolerance && !math.IsInf(l, -1):
+			ratio = (fl.xb.AtVec(i) - l) / -delta
+		case delta > pivotTolerance && !math.IsInf(u, 1):
+			ratio, toUpper = (u-fl.xb.AtVec(i))/delta, true
+		default:
+			continue
+		}
+		ratio = math.Max(ratio, 0)
+
+		tie := fl.bland && fl.r != -1 && ratio <= theta+pivotTolerance &&
+			indexVal < int(fl.indices.AtVec(fl.r))
+		if ratio < theta || tie {
+			theta = math.Min(theta, ratio)
+			fl.r = i
+			fl.toUpper = toUpper
 		}
 	}
 
+	fl.theta = theta
+	fl.flip = fl.r == -1 && !math.IsInf(theta, 1)
 	return nil
 }
 
 func updateB(bu *basisUpdate) error {
-	m, _ := bu.BMat.Dims()
-
-	for i := range m {
-		bu.BMat.Set(i, bu.r, bu.as.AtVec(i))
-	}
-
 	bu.indices.SetVec(bu.r, float64(bu.s))
 	bu.cb.SetVec(bu.r, bu.cs)
 
@@ -347,75 +780,63 @@ func updateB(bu *basisUpdate) error {
 // removeArtificialFromBasis removes artificial variables from the basis before Phase 2.
 // If an artificial variable has a positive value, it returns an error (infeasible LP).
 func removeArtificialFromBasis(sm *simplexMethod) error {
+	basic := make([]bool, sm.n) // Original columns in the basis
+	for i := range sm.m {
+		if j := int(sm.indices.AtVec(i)); j < sm.n {
+			basic[j] = true
+		}
+	}
+	enter := func(i, j int) {
+		sm.indices.SetVec(i, float64(j))
+		basic[j] = true
+	}
+
 	for i := 0; i < sm.m; i++ {
 		index := int(sm.indices.AtVec(i))
 		if index >= sm.n { // artificial variable
 			// If artificial value is (approximately) zero we can try to remove it
 			if math.Abs(sm.x.AtVec(index)) < 1e-8 {
-				// First pass: prefer original non-basic columns that have non-zero in this row
-				replaced := false
 				// If sm.A is nil (unit tests) fall back to simple replacement logic
 				if sm.A == nil {
 					for j := 0; j < sm.n; j++ {
-						if contains(sm.indices, j) {
+						if basic[j] {
 							continue
 						}
-						sm.indices.SetVec(i, float64(j))
-						replaced = true
+						enter(i, j)
 						break
 					}
-					if replaced {
-						continue
-					}
-				} else {
-					for j := 0; j < sm.n; j++ {
-						if contains(sm.indices, j) {
-							continue
-						}
-
-						// Prefer columns with non-zero in this row
-						if sm.A.At(i, j) == 0 {
-							continue
-						}
+					continue
+				}
 
-						// Efficient invertibility test: attempt to solve B * y = e_r where B
-						// is the current basis with column i replaced by column j. If SolveVec
-						// returns without error, the matrix is likely non-singular.
-						tempIdx := mat.VecDenseCopyOf(sm.indices)
-						tempIdx.SetVec(i, float64(j))
-						tempB := matrix.ExtractColumns(sm.A, tempIdx)
-						e := mat.NewVecDense(sm.m, nil)
-						e.SetVec(i, 1.0)
-						y := mat.NewVecDense(sm.m, nil)
-						if err := y.SolveVec(tempB, e); err == nil {
-							sm.indices.SetVec(i, float64(j))
-							replaced = true
-							break
-						}
-					}
-					if replaced {
-						continue
+				// Column j can replace the artificial in row i of the basis
+				// exactly when entry i of B^-1 a_j is nonzero
+				factor, err := newBasisFactor(sm.A, sm.indices)
+				if err != nil {
+					return errors.New(errors.ErrNumericalFailure, "error factorising basis matrix", err)
+				}
+				a := mat.NewVecDense(sm.m, nil)
+				replaces := func(j int) bool {
+					if basic[j] {
+						return false
 					}
+					matrix.ColInto(a, sm.A, j)
+					d, err := factor.ftran(a)
+					return err == nil && math.Abs(d.AtVec(i)) > pivotTolerance
 				}
 
-				for j := 0; j < sm.n; j++ {
-					if contains(sm.indices, j) {
-						continue
-					}
-					tempIdx := mat.VecDenseCopyOf(sm.indices)
-					tempIdx.SetVec(i, float64(j))
-					tempB := matrix.ExtractColumns(sm.A, tempIdx)
-					e := mat.NewVecDense(sm.m, nil)
-					e.SetVec(i, 1.0)
-					y := mat.NewVecDense(sm.m, nil)
-					if err := y.SolveVec(tempB, e); err == nil {
-						sm.indices.SetVec(i, float64(j))
+				// First pass: prefer original non-basic columns that have non-zero in this row
+				replaced := false
+				for j := 0; j < sm.n && !replaced; j++ {
+					if sm.A.At(i, j) != 0 && replaces(j) {
+						enter(i, j)
 						replaced = true
-						break
 					}
 				}
-				if !replaced {
-					continue
+				for j := 0; j < sm.n && !replaced; j++ {
+					if replaces(j) {
+						enter(i, j)
+						replaced = true
+					}
 				}
 			} else {
 				return errors.New(errors.ErrInfeasible, "LP is infeasible: artificial variable in basis with positive value", nil)
@@ -424,12 +845,3 @@ func removeArtificialFromBasis(sm *simplexMethod) error {
 	}
 	return nil
 }
-
-func contains(vec *mat.VecDense, val int) bool {
-	for i := 0; i < vec.Len(); i++ {
-		if int(vec.AtVec(i)) == val {
-			return true
-		}
-	}
-	return false
-}
@@ -1,19 +1,28 @@
 package simplex
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"math/rand/v2"
 	"testing"
 
 	"github.com/chriso345/gore/assert"
 	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/matrix"
 	"gonum.org/v1/gonum/mat"
 )
 
-func TestContains(t *testing.T) {
-	v := mat.NewVecDense(4, []float64{0, 2, 3, 5})
-	assert.True(t, contains(v, 2))
-	assert.False(t, contains(v, 4))
-	assert.True(t, contains(v, 0))
-	assert.False(t, contains(v, 6))
+func TestMarkBasic(t *testing.T) {
+	sm := &simplexMethod{m: 3, n: 4}
+	sm.indices = mat.NewVecDense(3, []float64{0, 2, 5})
+	sm.markBasic()
+	assert.Equal(t, len(sm.isbasic), 7)
+	for j, basic := range sm.isbasic {
+		assert.Equal(t, basic, j == 0 || j == 2 || j == 5)
+	}
 }
 
 func TestRemoveArtificialFromBasis(t *testing.T) {}
@@ -59,7 +68,7 @@ func TestFindEnterSmall(t *testing.T) {
 	})
 	c := mat.NewVecDense(2, []float64{-1, -2})
 	pi := mat.NewVecDense(2, []float64{0, 0})
-	isbasic := mat.NewVecDense(2, []float64{0, 1})
+	isbasic := []bool{false, true}
 
 	fe := &enteringVariable{
 		A:       A,
@@ -76,7 +85,7 @@ func TestFindEnterSmall(t *testing.T) {
 }
 
 func TestFindLeaveSmall(t *testing.T) {
-	B := mat.NewDense(2, 2, []float64{
+	A := mat.NewDense(2, 2, []float64{
 		1, 0,
 		0, 1,
 	})
@@ -85,7 +94,7 @@ func TestFindLeaveSmall(t *testing.T) {
 	as := mat.NewVecDense(2, []float64{1, 1})
 
 	fl := &leavingVariable{
-		B:       B,
+		A:       A,
 		indices: indices,
 		xb:      xb,
 		as:      as,
@@ -100,14 +109,12 @@ func TestFindLeaveSmall(t *testing.T) {
 func TestRSM_ImmediateOptimal(t *testing.T) {
 	// Construct sm such that entering variable s=-1 immediately
 	A := mat.NewDense(2, 2, []float64{1, 0, 0, 1})
-	B := mat.NewDense(2, 2, []float64{1, 0, 0, 1})
 	c := mat.NewVecDense(2, []float64{0, 0})
 	indices := mat.NewVecDense(2, []float64{0, 1})
 	sm := &simplexMethod{
 		m: 2,
 		n: 2,
 		A: A,
-		B: B,
 		c: c,
 		b: mat.NewVecDense(2, []float64{5, 3}),
 		rsmResult: rsmResult{
@@ -116,7 +123,7 @@ func TestRSM_ImmediateOptimal(t *testing.T) {
 			pi:      mat.NewVecDense(2, nil),
 			flag:    common.SolverStatusNotSolved,
 		},
-		cb: mat.NewVecDense(2, []float64{0, 0}),
+		cb: mat.NewVecDense(2, []float64{0, 1}),
 	}
 	config := &common.SolverConfig{Tolerance: 1e-9}
 	err := RSM(sm, 2, config)
@@ -125,17 +132,12 @@ func TestRSM_ImmediateOptimal(t *testing.T) {
 }
 
 func TestUpdateB(t *testing.T) {
-	B := mat.NewDense(2, 2, []float64{1, 2, 3, 4})
-	as := mat.NewVecDense(2, []float64{9, 8})
 	indices := mat.NewVecDense(2, []float64{0, 1})
 	cb := mat.NewVecDense(2, []float64{0, 0})
-	bu := &basisUpdate{BMat: B, indices: indices, cb: cb, as: as, s: 1, r: 0, cs: 7}
+	bu := &basisUpdate{indices: indices, cb: cb, s: 1, r: 0, cs: 7}
 	if err := updateB(bu); err != nil {
 		t.Fatalf("updateB failed: %v", err)
 	}
-	if B.At(0, 0) != 9 || B.At(1, 0) != 8 {
-		t.Fatalf("B column not updated")
-	}
 	if int(indices.AtVec(0)) != 1 || cb.AtVec(0) != 7 {
 		t.Fatalf("indices or cb not updated")
 	}
@@ -146,7 +148,7 @@ func TestFindEnterAndLeave(t *testing.T) {
 	A := mat.NewDense(1, 2, []float64{1, 0})
 	pi := mat.NewVecDense(1, []float64{0})
 	c := mat.NewVecDense(2, []float64{0, -1})
-	isbasic := mat.NewVecDense(2, []float64{1, 0})
+	isbasic := []bool{true, false}
 	fe := &enteringVariable{A: A, pi: pi, c: c, isbasic: isbasic, epsilon: 1e-9}
 	if err := findEnter(fe); err != nil {
 		t.Fatalf("findEnter failed: %v", err)
@@ -156,11 +158,10 @@ func TestFindEnterAndLeave(t *testing.T) {
 	}
 
 	// findLeave setup: m=2, B=I, as=[1,2], xb=[5,1]
-	B := mat.NewDense(2, 2, []float64{1, 0, 0, 1})
 	indices := mat.NewVecDense(2, []float64{0, 1})
 	as := mat.NewVecDense(2, []float64{1, 2})
 	xb := mat.NewVecDense(2, []float64{5, 1})
-	fl := &leavingVariable{B: B, indices: indices, as: as, xb: xb, phase: 1, n: 2}
+	fl := &leavingVariable{A: matrix.Eye(2), indices: indices, as: as, xb: xb, phase: 1, n: 2}
 	if err := findLeave(fl); err != nil {
 		t.Fatalf("findLeave failed: %v", err)
 	}
@@ -199,7 +200,7 @@ func TestRemoveArtificialFromBasisANilPath(t *testing.T) {
 }
 
 func TestFindEnterNoEnter(t *testing.T) {
-	fe := &enteringVariable{A: mat.NewDense(1, 1, []float64{1}), pi: mat.NewVecDense(1, []float64{0}), c: mat.NewVecDense(1, []float64{1}), isbasic: mat.NewVecDense(1, []float64{1}), epsilon: 1e-9}
+	fe := &enteringVariable{A: mat.NewDense(1, 1, []float64{1}), pi: mat.NewVecDense(1, []float64{0}), c: mat.NewVecDense(1, []float64{1}), isbasic: []bool{true}, epsilon: 1e-9}
 	if err := findEnter(fe); err != nil {
 		t.Fatalf("findEnter failed: %v", err)
 	}
@@ -209,11 +210,11 @@ func TestFindEnterNoEnter(t *testing.T) {
 }
 
 func TestFindLeavePhase2Immediate(t *testing.T) {
-	B := mat.NewDense(1, 1, []float64{1})
+	A := mat.NewDense(1, 6, []float64{0, 0, 0, 0, 0, 1})
 	indices := mat.NewVecDense(1, []float64{5})
 	as := mat.NewVecDense(1, []float64{1})
 	xb := mat.NewVecDense(1, []float64{1})
-	fl := &leavingVariable{B: B, indices: indices, as: as, xb: xb, phase: 2, n: 2}
+	fl := &leavingVariable{A: A, indices: indices, as: as, xb: xb, phase: 2, n: 2}
 	if err := findLeave(fl); err != nil {
 		t.Fatalf("findLeave failed: %v", err)
 	}
@@ -224,11 +225,11 @@ func TestFindLeavePhase2Immediate(t *testing.T) {
 
 func TestRSMMaxIterFailure(t *testing.T) {
 	// exercise RSM early failure by creating singular B when solving xb
-	// create sm with m=1 n=1, B zero matrix will cause SolveVec to error
+	// create sm with m=1 n=1 whose only basic column is zero
 	sm := &simplexMethod{
 		m:         1,
 		n:         1,
-		B:         mat.NewDense(1, 1, []float64{0}),
+		A:         mat.NewDense(1, 2, []float64{1, 0}),
 		b:         mat.NewVecDense(1, []float64{1}),
 		c:         mat.NewVecDense(2, []float64{1, 1}),
 		cb:        mat.NewVecDense(1, []float64{1}),
@@ -241,26 +242,21 @@ func TestRSMMaxIterFailure(t *testing.T) {
 }
 
 func TestFindLeave_Unbounded(t *testing.T) {
-	B := mat.NewDense(2, 2, []float64{1, 0, 0, 1})
 	indices := mat.NewVecDense(2, []float64{0, 1})
 	xb := mat.NewVecDense(2, []float64{5, 3})
 	as := mat.NewVecDense(2, []float64{-1, 0})
 
-	fl := &leavingVariable{B: B, indices: indices, xb: xb, as: as, phase: 1, n: 2}
+	fl := &leavingVariable{A: matrix.Eye(2), indices: indices, xb: xb, as: as, phase: 1, n: 2}
 	assert.Nil(t, findLeave(fl))
 	assert.Equal(t, fl.r, -1)
 }
 
 func TestUpdateB_New(t *testing.T) {
-	B := mat.NewDense(2, 2, []float64{0, 0, 0, 0})
 	indices := mat.NewVecDense(2, []float64{0, 1})
 	cb := mat.NewVecDense(2, []float64{10, 20})
-	as := mat.NewVecDense(2, []float64{3, 4})
 	bu := &basisUpdate{
-		BMat:    B,
 		indices: indices,
 		cb:      cb,
-		as:      as,
 		s:       7,
 		r:       1,
 		cs:      42.0,
@@ -268,8 +264,428 @@ func TestUpdateB_New(t *testing.T) {
 
 	err := updateB(bu)
 	assert.Nil(t, err)
-	assert.Equal(t, B.At(0, 1), 3.0)
-	assert.Equal(t, B.At(1, 1), 4.0)
 	assert.Equal(t, int(indices.AtVec(1)), 7)
 	assert.Equal(t, cb.AtVec(1), 42.0)
 }
+
+func TestFindEnterAtUpperBound(t *testing.T) {
+	// Column 0 rests at its upper bound with a positive reduced cost, so
+	// decreasing it improves the objective
+	fe := &enteringVariable{
+		A:       mat.NewDense(1, 2, []float64{1, 1}),
+		pi:      mat.NewVecDense(1, []float64{0}),
+		c:       mat.NewVecDense(2, []float64{3, 1}),
+		lower:   mat.NewVecDense(2, []float64{0, 0}),
+		upper:   mat.NewVecDense(2, []float64{5, math.Inf(1)}),
+		atUpper: []bool{true, false},
+		isbasic: []bool{false, false},
+		epsilon: 1e-9,
+	}
+	assert.Nil(t, findEnter(fe))
+	assert.Equal(t, fe.s, 0)
+	assert.True(t, fe.decreasing)
+
+	// Fixed columns never enter
+	fe.upper.SetVec(0, 0)
+	fe.atUpper[0] = false
+	assert.Nil(t, findEnter(fe))
+	assert.Equal(t, fe.s, -1)
+}
+
+func TestFindLeaveBoundFlip(t *testing.T) {
+	// The entering column can only move 2 units before reaching its upper
+	// bound, which is less than any basic variable allows
+	fl := &leavingVariable{
+		A:       mat.NewDense(1, 2, []float64{0, 1}),
+		indices: mat.NewVecDense(1, []float64{1}),
+		as:      mat.NewVecDense(1, []float64{1}),
+		xb:      mat.NewVecDense(1, []float64{10}),
+		phase:   2,
+		n:       2,
+		lower:   mat.NewVecDense(2, []float64{0, 0}),
+		upper:   mat.NewVecDense(2, []float64{2, math.Inf(1)}),
+		s:       0,
+	}
+	assert.Nil(t, findLeave(fl))
+	assert.True(t, fl.flip)
+	assert.Equal(t, fl.r, -1)
+	assert.Equal(t, fl.theta, 2.0)
+
+	// A basic variable with an upper bound leaves at that bound
+	fl.as.SetVec(0, -1)
+	fl.upper.SetVec(0, math.Inf(1))
+	fl.upper.SetVec(1, 13)
+	assert.Nil(t, findLeave(fl))
+	assert.False(t, fl.flip)
+	assert.Equal(t, fl.r, 0)
+	assert.True(t, fl.toUpper)
+	assert.Equal(t, fl.theta, 3.0)
+}
+
+func TestSimplexBoundedVariables(t *testing.T) {
+	// Minimize: -x1 - 2*x2
+	// Subject to: x1 + x2 + s = 10, 0 <= x1 <= 3, -1 <= x2 <= 4
+	objVal := 0.
+	status := common.SolverStatusNotSolved
+	scf := &common.StandardComputationalForm{
+		Objective:      mat.NewVecDense(3, []float64{-1, -2, 0}),
+		Constraints:    mat.NewDense(1, 3, []float64{1, 1, 1}),
+		RHS:            mat.NewVecDense(1, []float64{10}),
+		Lower:          mat.NewVecDense(3, []float64{0, -1, 0}),
+		Upper:          mat.NewVecDense(3, []float64{3, 4, math.Inf(1)}),
+		ObjectiveValue: &objVal,
+		Status:         &status,
+	}
+
+	assert.Nil(t, Simplex(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, status, common.SolverStatusOptimal)
+	assert.IsClose(t, objVal, -11, 1e-9)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(0), 3, 1e-9)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(1), 4, 1e-9)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(2), 3, 1e-9)
+}
+
+func TestSimplexFreeVariable(t *testing.T) {
+	// Minimize: x1 subject to x1 - x2 = -4 with x1 free and 0 <= x2 <= 1
+	objVal := 0.
+	status := common.SolverStatusNotSolved
+	scf := &common.StandardComputationalForm{
+		Objective:      mat.NewVecDense(2, []float64{1, 0}),
+		Constraints:    mat.NewDense(1, 2, []float64{1, -1}),
+		RHS:            mat.NewVecDense(1, []float64{-4}),
+		Lower:          mat.NewVecDense(2, []float64{math.Inf(-1), 0}),
+		Upper:          mat.NewVecDense(2, []float64{math.Inf(1), 1}),
+		ObjectiveValue: &objVal,
+		Status:         &status,
+	}
+
+	assert.Nil(t, Simplex(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, status, common.SolverStatusOptimal)
+	assert.IsClose(t, objVal, -4, 1e-9)
+	assert.IsClose(t, scf.PrimalSolution.AtVec(0), -4, 1e-9)
+}
+
+// slackBasisRSM runs Phase 2 of the RSM on min c^T x s.t. [A | I] x = b,
+// starting from the slack basis.
+func slackBasisRSM(t *testing.T, A []float64, b, c []float64) *simplexMethod {
+	t.Helper()
+	m, n := len(b), len(c)
+	full := mat.NewDense(m, n+m, nil)
+	for i := range m {
+		for j := range n {
+			full.Set(i, j, A[i*n+j])
+		}
+		full.Set(i, n+i, 1)
+	}
+	cost := mat.NewVecDense(n+m, nil)
+	for j := range n {
+		cost.SetVec(j, c[j])
+	}
+	cb := mat.NewVecDense(m, nil)
+	for i := range m {
+		cb.SetVec(i, float64(n+i))
+	}
+
+	sm := &simplexMethod{
+		A:  full,
+		b:  mat.NewVecDense(m, b),
+		c:  cost,
+		m:  m,
+		n:  n + m,
+		cb: cb,
+	}
+	assert.Nil(t, RSM(sm, 2, common.DefaultSolverConfig()))
+	return sm
+}
+
+func TestRSMCyclingExample(t *testing.T) {
+	// Chvatal's example, which cycles under Dantzig's rule with first-row
+	// ties: min -10x1 + 57x2 + 9x3 + 24x4
+	sm := slackBasisRSM(t, []float64{
+		0.5, -5.5, -2.5, 9,
+		0.5, -1.5, -0.5, 1,
+		1, 0, 0, 0,
+	}, []float64{0, 0, 1}, []float64{-10, 57, 9, 24})
+
+	assert.Equal(t, sm.flag, common.SolverStatusOptimal)
+	assert.IsClose(t, sm.value, -1, 1e-9)
+	assert.IsClose(t, sm.x.AtVec(0), 1, 1e-9)
+	assert.IsClose(t, sm.x.AtVec(2), 1, 1e-9)
+}
+
+func TestRSMBealeExample(t *testing.T) {
+	// Beale's example: min -3/4 x4 + 150x5 - 1/50 x6 + 6x7
+	sm := slackBasisRSM(t, []float64{
+		0.25, -60, -1. / 25, 9,
+		0.5, -90, -1. / 50, 3,
+		0, 0, 1, 0,
+	}, []float64{0, 0, 1}, []float64{-0.75, 150, -1. / 50, 6})
+
+	assert.Equal(t, sm.flag, common.SolverStatusOptimal)
+	assert.IsClose(t, sm.value, -0.05, 1e-9)
+	assert.IsClose(t, sm.x.AtVec(0), 0.04, 1e-9)
+	assert.IsClose(t, sm.x.AtVec(2), 1, 1e-9)
+}
+
+func TestRSMBasicSolutionUpdates(t *testing.T) {
+	// Enough pivots to pass a refactorisation, after which the basic
+	// solution, moved along every step, must still satisfy Ax = b
+	rng := rand.New(rand.NewPCG(5, 6))
+	m, n := 150, 200
+	A, b, c := make([]float64, m*n), make([]float64, m), make([]float64, n)
+	for k := range A {
+		A[k] = rng.Float64()
+	}
+	for i := range b {
+		b[i] = 1 + rng.Float64()
+	}
+	for j := range c {
+		c[j] = -1 - rng.Float64()
+	}
+	sm := slackBasisRSM(t, A, b, c)
+
+	assert.Equal(t, sm.flag, common.SolverStatusOptimal)
+	assert.True(t, sm.iterations > refactorInterval)
+	var Ax mat.VecDense
+	Ax.MulVec(sm.A, sm.x)
+	for i := range m {
+		assert.IsClose(t, Ax.AtVec(i), b[i], 1e-9)
+	}
+}
+
+func TestFindEnterBland(t *testing.T) {
+	A := mat.NewDense(1, 3, []float64{1, 1, 1})
+	fe := &enteringVariable{
+		A:       A,
+		c:       mat.NewVecDense(3, []float64{0, -1, -5}),
+		pi:      mat.NewVecDense(1, []float64{0}),
+		isbasic: []bool{true, false, false},
+
+		epsilon: 1e-9,
+	}
+
+	assert.Nil(t, findEnter(fe))
+	assert.Equal(t, fe.s, 2)
+
+	fe.bland = true
+	assert.Nil(t, findEnter(fe))
+	assert.Equal(t, fe.s, 1)
+}
+
+func TestFindLeaveBlandTie(t *testing.T) {
+	// Both rows tie at a zero step; Bland's rule picks basic column 1 in row 1
+	fl := &leavingVariable{
+		A:       mat.NewDense(2, 4, []float64{0, 0, 0, 1, 0, 1, 0, 0}),
+		indices: mat.NewVecDense(2, []float64{3, 1}),
+		xb:      mat.NewVecDense(2, []float64{0, 0}),
+		as:      mat.NewVecDense(2, []float64{1, 2}),
+		phase:   2,
+		n:       4,
+		s:       0,
+	}
+
+	assert.Nil(t, findLeave(fl))
+	assert.Equal(t, fl.r, 0)
+
+	fl.bland = true
+	assert.Nil(t, findLeave(fl))
+	assert.Equal(t, fl.r, 1)
+	assert.Equal(t, fl.theta, 0.)
+}
+
+func TestSimplexIterationLimit(t *testing.T) {
+	config := common.DefaultSolverConfig()
+	config.MaxIterations = 1
+
+	scf := newDualTestSCF()
+	assert.Nil(t, Simplex(scf, config))
+	assert.Equal(t, *scf.Status, common.SolverStatusIterationLimit)
+	assert.Equal(t, len(scf.Basis), 2)
+	assert.True(t, scf.DualSolution == nil)
+
+	// The objective is that of the point reached, not of the Phase 1 problem
+	x := scf.PrimalSolution
+	assert.Equal(t, *scf.ObjectiveValue, 2*x.AtVec(0)+3*x.AtVec(1))
+
+	// The same problem solves within a larger limit
+	config.MaxIterations = 10
+	scf = newDualTestSCF()
+	assert.Nil(t, Simplex(scf, config))
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+}
+
+func TestSimplexCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	config := common.DefaultSolverConfig()
+	config.Ctx = ctx
+
+	scf := newDualTestSCF()
+	assert.Nil(t, Simplex(scf, config))
+	assert.Equal(t, *scf.Status, common.SolverStatusCancelled)
+	assert.Equal(t, len(scf.Basis), 2)
+	assert.NotNil(t, scf.PrimalSolution)
+
+	scf = newDualTestSCF()
+	assert.Nil(t, DualSimplex(scf, config))
+	assert.Equal(t, *scf.Status, common.SolverStatusCancelled)
+}
+
+func TestSimplexFarkasRay(t *testing.T) {
+	// x1 + x2 = -1 and x1 - x2 = 3 with 0 <= x1 <= 1, x2 >= 0
+	objVal := 0.
+	status := common.SolverStatusNotSolved
+	A := mat.NewDense(2, 2, []float64{1, 1, 1, -1})
+	b := mat.NewVecDense(2, []float64{-1, 3})
+	scf := &common.StandardComputationalForm{
+		Objective:      mat.NewVecDense(2, []float64{1, 1}),
+		Constraints:    A,
+		RHS:            b,
+		Lower:          mat.NewVecDense(2, []float64{0, 0}),
+		Upper:          mat.NewVecDense(2, []float64{1, math.Inf(1)}),
+		ObjectiveValue: &objVal,
+		Status:         &status,
+	}
+
+	assert.Nil(t, Simplex(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, status, common.SolverStatusInfeasible)
+	assert.True(t, scf.UnboundedRay == nil)
+	y := scf.FarkasRay
+	assert.Equal(t, y.Len(), 2)
+
+	// y^T A x over the bounds is largest at the bound each column's sign
+	// favours, and still falls short of y^T b
+	best := 0.
+	for j := range 2 {
+		aty := mat.Dot(A.ColView(j), y)
+		lower, upper := scf.Bounds(j)
+		switch {
+		case aty > 1e-9:
+			assert.False(t, math.IsInf(upper, 1))
+			best += aty * upper
+		case aty < -1e-9:
+			best += aty * lower
+		}
+	}
+	assert.True(t, mat.Dot(y, b) > best+1e-9)
+}
+
+func TestSimplexUnboundedRay(t *testing.T) {
+	// Minimize -x1 + x3 s.t. x1 - x2 = 1, x3 - x2 = 0 with x >= 0
+	objVal := 0.
+	status := common.SolverStatusNotSolved
+	A := mat.NewDense(2, 3, []float64{1, -1, 0, 0, -1, 1})
+	c := mat.NewVecDense(3, []float64{-1, 0, 0.5})
+	scf := &common.StandardComputationalForm{
+		Objective:      c,
+		Constraints:    A,
+		RHS:            mat.NewVecDense(2, []float64{1, 0}),
+		ObjectiveValue: &objVal,
+		Status:         &status,
+	}
+
+	assert.Nil(t, Simplex(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, status, common.SolverStatusUnbounded)
+	assert.True(t, scf.FarkasRay == nil)
+	d := scf.UnboundedRay
+	assert.Equal(t, d.Len(), 3)
+
+	var Ad mat.VecDense
+	Ad.MulVec(A, d)
+	assert.IsClose(t, mat.Norm(&Ad, math.Inf(1)), 0, 1e-9)
+	assert.True(t, mat.Dot(c, d) < -1e-9)
+	for j := range 3 {
+		assert.True(t, d.AtVec(j) >= -1e-9)
+	}
+}
+
+func TestSimplexStats(t *testing.T) {
+	scf := newDualTestSCF()
+	scf.Stats = &common.Stats{}
+	assert.Nil(t, Simplex(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+
+	// Both rows start on an artificial, so Phase 1 pivots at least twice
+	st := scf.Stats
+	assert.True(t, st.Phase1Iterations >= 2)
+	assert.Equal(t, st.DualIterations, 0)
+	assert.True(t, st.Refactorisations >= 2) // One for each phase
+	assert.True(t, st.Phase1Time > 0)
+
+	// The dual simplex records its own pivots
+	child := scf.Copy()
+	child.Stats = &common.Stats{}
+	child.SetBounds(1, 0, 0)
+	assert.Nil(t, DualSimplex(child, common.DefaultSolverConfig()))
+	assert.True(t, child.Stats.DualIterations >= 1)
+	assert.Equal(t, child.Stats.Phase1Iterations, 0)
+}
+
+func TestSimplexEvents(t *testing.T) {
+	var events []common.Event
+	config := common.DefaultSolverConfig()
+	config.CallbackInterval = 1
+	config.Callback = func(e common.Event) error {
+		events = append(events, e)
+		return nil
+	}
+
+	scf := newDualTestSCF()
+	scf.Stats = &common.Stats{}
+	assert.Nil(t, Simplex(scf, config))
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+
+	// Each phase is announced, then every pivot is reported in order
+	phases, pivots := []common.Phase{}, 0
+	for _, e := range events {
+		switch e.Kind {
+		case common.EventPhase:
+			phases = append(phases, e.Phase)
+		case common.EventIteration:
+			pivots++
+			assert.Equal(t, e.Iterations, pivots)
+		}
+	}
+	assert.Equal(t, len(phases), 2)
+	assert.Equal(t, phases[0], common.PhaseOne)
+	assert.Equal(t, phases[1], common.PhaseTwo)
+	assert.Equal(t, pivots, scf.Stats.Phase1Iterations+scf.Stats.Phase2Iterations)
+
+	// Phase 1 reports the sum of infeasibilities, which starts positive
+	assert.True(t, events[1].Objective > 0)
+}
+
+func TestSimplexLogging(t *testing.T) {
+	var buf bytes.Buffer
+	config := common.DefaultSolverConfig()
+	config.Logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	scf := newDualTestSCF()
+	scf.Stats = &common.Stats{}
+	assert.Nil(t, Simplex(scf, config))
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+
+	// Each phase is started and finished around a record per pivot
+	started, finished, pivots := 0, 0, 0
+	for line := range bytes.Lines(buf.Bytes()) {
+		var r map[string]any
+		assert.Nil(t, json.Unmarshal(line, &r))
+		assert.Equal(t, r["level"], "DEBUG")
+		switch r["msg"] {
+		case "simplex phase started":
+			started++
+		case "simplex phase finished":
+			finished++
+		case "simplex iteration":
+			pivots++
+			assert.Equal(t, r["iteration"].(float64), float64(pivots))
+			assert.True(t, r["infeasibility"].(float64) >= 0)
+			if r["phase"] == common.PhaseTwo.String() {
+				assert.IsClose(t, r["infeasibility"].(float64), 0, 1e-9)
+			}
+		}
+	}
+	assert.Equal(t, started, 2)
+	assert.Equal(t, finished, 2)
+	assert.Equal(t, pivots, scf.Stats.Phase1Iterations+scf.Stats.Phase2Iterations)
+}
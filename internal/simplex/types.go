@@ -6,16 +6,25 @@ import (
 )
 
 type simplexMethod struct {
-	A *mat.Dense
+	A mat.Matrix // Constraints extended by the artificial columns
 	b *mat.VecDense
 	c *mat.VecDense
 
+	// Column bounds over the columns of A; nil means x >= 0
+	lower *mat.VecDense
+	upper *mat.VecDense
+
 	m int
 	n int
 
-	B  *mat.Dense
 	cb *mat.VecDense
 
+	// Columns of A in the basis, kept current by every pivot
+	isbasic []bool
+
+	iterations int           // Pivots made so far, across both phases
+	stats      *common.Stats // Work recorded for the caller, or nil
+
 	rsmResult
 }
 
@@ -24,41 +33,73 @@ type rsmResult struct {
 	x       *mat.VecDense
 	pi      *mat.VecDense
 	indices *mat.VecDense
+	atUpper []bool // Nonbasic columns resting at their upper bound
 	flag    common.SolverStatus
+
+	// Certificate of an infeasible or unbounded flag: a Farkas ray over the
+	// rows, or an improving direction over the structural columns
+	ray *mat.VecDense
 }
 
 type enteringVariable struct {
-	A  *mat.Dense    // Pointer to the simpleMethod.A
+	A  mat.Matrix    // Pointer to the simpleMethod.A
 	pi *mat.VecDense // Pointer to the rsmResult.pi
 	c  *mat.VecDense // Pointer to the simpleMethod.c
 
-	isbasic *mat.VecDense
+	lower   *mat.VecDense // Pointer to the simpleMethod.lower
+	upper   *mat.VecDense // Pointer to the simpleMethod.upper
+	atUpper []bool        // Pointer to the rsmResult.atUpper
+
+	isbasic []bool // Pointer to the simplexMethod.isbasic
 
 	epsilon float64
+	bland   bool   // Take the first attractive column rather than the best
+	pricer  pricer // Dantzig's rule when nil
 
 	// Results
-	as *mat.VecDense
-	cs float64
-	s  int
+	as         *mat.VecDense
+	cs         float64
+	s          int
+	decreasing bool // The entering variable moves down from its upper bound
 }
 
 type leavingVariable struct {
-	B       *mat.Dense    // Pointer to the simpleMethod.B
+	A       mat.Matrix    // Pointer to the simpleMethod.A
+	factor  *basisFactor  // Factorisation of the basis; built from A when nil
 	indices *mat.VecDense // Pointer to the rsmResult.indices
 	as      *mat.VecDense
 	xb      *mat.VecDense
 	phase   int
 	n       int
 
-	r int
+	lower      *mat.VecDense // Pointer to the simpleMethod.lower
+	upper      *mat.VecDense // Pointer to the simpleMethod.upper
+	s          int           // Entering column
+	decreasing bool          // Copied from enteringVariable.decreasing
+	bland      bool          // Break ratio ties by the smallest basic column
+
+	// Results
+	direction *mat.VecDense // B^-1 as, reused to update the factorisation
+	r         int
+	theta     float64 // Step length of the entering variable
+	flip      bool    // The entering variable reaches its opposite bound first
+	toUpper   bool    // The leaving variable exits at its upper bound
 }
 
 type basisUpdate struct {
-	BMat    *mat.Dense
 	indices *mat.VecDense
 	cb      *mat.VecDense
-	as      *mat.VecDense
 	s       int
 	r       int
 	cs      float64
 }
+
+type pricingUpdate struct {
+	A         mat.Matrix    // Pointer to the simpleMethod.A
+	factor    *basisFactor  // Factorisation of the basis before the pivot
+	isbasic   []bool        // Basic columns before the pivot
+	direction *mat.VecDense // B^-1 as for the entering column
+	s         int           // Entering column
+	r         int           // Pivot row
+	leaving   int           // Column leaving the basis
+}
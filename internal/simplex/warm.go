@@ -0,0 +1,123 @@
+package simplex
+
+import (
+	"math"
+
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/errors"
+	"github.com/chriso345/gspl/internal/matrix"
+	"gonum.org/v1/gonum/mat"
+)
+
+// warmSimplex solves scf from the basis recorded on it by an earlier solve, or
+// given as a warm start, in place of Phase 1. A basis that is still primal
+// feasible goes straight to Phase 2 and one that is still dual feasible to the
+// dual simplex. It reports false, having changed nothing, when scf holds no
+// basis, the basis is singular, or it is feasible for neither method.
+func warmSimplex(scf *common.StandardComputationalForm, config *common.SolverConfig) (bool, error) {
+	m, n := scf.Constraints.Dims()
+	if len(scf.Basis) != m || len(scf.AtUpper) != n {
+		return false, nil
+	}
+	sm := &simplexMethod{
+		m:     m,
+		n:     n,
+		stats: scf.Stats,
+		b:     scf.RHS,
+	}
+
+	// Artificial columns are fixed at zero, as in the dual simplex
+	sm.lower = mat.NewVecDense(n+m, nil)
+	sm.upper = mat.NewVecDense(n+m, nil)
+	for j := range n {
+		lower, upper := scf.Bounds(j)
+		if lower > upper+config.Tolerance {
+			return false, nil
+		}
+		sm.lower.SetVec(j, lower)
+		sm.upper.SetVec(j, upper)
+	}
+
+	signs := make([]float64, m)
+	for i := range m {
+		signs[i] = 1.
+	}
+	sm.A = auxiliaryMatrix(scf.Constraints, signs)
+	sm.c = mat.NewVecDense(n+m, nil)
+	for j := range n {
+		sm.c.SetVec(j, scf.Objective.AtVec(j))
+	}
+
+	if !sm.warmStart(scf) {
+		return false, nil
+	}
+
+	// A nonbasic column rests at its upper bound only when it has one, and
+	// must when it has no lower bound
+	for j := range n {
+		lower, upper := columnBounds(sm.lower, sm.upper, j)
+		sm.atUpper[j] = !math.IsInf(upper, 1) && (sm.atUpper[j] || math.IsInf(lower, -1))
+	}
+
+	factor, err := newBasisFactor(sm.A, sm.indices)
+	if err != nil {
+		return false, nil
+	}
+	xb, err := factor.ftran(sm.nonbasicRHS(sm.A, n))
+	if err != nil {
+		return false, nil
+	}
+
+	if sm.primalFeasible(xb, config.Tolerance) {
+		sm.cb = sm.indices
+		if err := RSM(sm, 2, config); err != nil {
+			return true, errors.New(errors.ErrNumericalFailure, "error in Phase 2 of Simplex", err)
+		}
+		sm.storeSolution(scf)
+		return true, nil
+	}
+
+	cb := mat.NewVecDense(m, nil)
+	for i := range m {
+		cb.SetVec(i, sm.c.AtVec(int(sm.indices.AtVec(i))))
+	}
+	pi, err := factor.btran(cb)
+	if err != nil {
+		return false, nil
+	}
+	if !sm.dualFeasible(pi, config.Tolerance) {
+		return false, nil
+	}
+
+	// Every reduced cost already has a bound to rest against, so the dual
+	// simplex starts without boxing any column and never falls back here
+	return true, DualSimplex(scf, config)
+}
+
+// primalFeasible reports whether every basic value in xb lies within the
+// bounds of its column.
+func (sm *simplexMethod) primalFeasible(xb *mat.VecDense, tol float64) bool {
+	for i := range sm.m {
+		lower, upper := columnBounds(sm.lower, sm.upper, int(sm.indices.AtVec(i)))
+		if x := xb.AtVec(i); x < lower-tol || x > upper+tol {
+			return false
+		}
+	}
+	return true
+}
+
+// dualFeasible reports whether, for the duals pi, every nonbasic structural
+// column with a nonzero reduced cost has the bound that cost needs.
+func (sm *simplexMethod) dualFeasible(pi *mat.VecDense, tol float64) bool {
+	for j := range sm.n {
+		if sm.isbasic[j] {
+			continue
+		}
+		lower, upper := columnBounds(sm.lower, sm.upper, j)
+		d := sm.c.AtVec(j) - matrix.ColDot(sm.A, j, pi)
+		if (d > tol && math.IsInf(lower, -1)) || (d < -tol && math.IsInf(upper, 1)) {
+			return false
+		}
+	}
+	return true
+}
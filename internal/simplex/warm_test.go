@@ -0,0 +1,62 @@
+package simplex
+
+import (
+	"testing"
+
+	"github.com/chriso345/gore/assert"
+	"github.com/chriso345/gspl/internal/common"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSimplexWarmStartOptimal(t *testing.T) {
+	scf := newDualTestSCF()
+	assert.Nil(t, Simplex(scf, common.DefaultSolverConfig()))
+
+	// The optimal basis needs no pivots, where a cold start needs several
+	config := common.DefaultSolverConfig()
+	config.MaxIterations = 1
+	warm := newDualTestSCF()
+	warm.Basis, warm.AtUpper = scf.Basis, scf.AtUpper
+	assert.Nil(t, Simplex(warm, config))
+	assert.Equal(t, *warm.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, *warm.ObjectiveValue, 9, 1e-9)
+	assert.IsClose(t, warm.DualSolution.AtVec(0), 1.5, 1e-9)
+}
+
+func TestSimplexWarmStartPrimalFeasible(t *testing.T) {
+	scf := newDualTestSCF()
+	assert.Nil(t, Simplex(scf, common.DefaultSolverConfig()))
+
+	// Making x1 dearer leaves the basis feasible but no longer optimal
+	warm := newDualTestSCF()
+	warm.Objective = mat.NewVecDense(4, []float64{4, 3, 0, 0})
+	warm.Basis, warm.AtUpper = scf.Basis, scf.AtUpper
+	assert.Nil(t, Simplex(warm, common.DefaultSolverConfig()))
+	assert.Equal(t, *warm.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, *warm.ObjectiveValue, 12, 1e-9)
+	assert.IsClose(t, warm.PrimalSolution.AtVec(1), 4, 1e-9)
+}
+
+func TestSimplexWarmStartDualFeasible(t *testing.T) {
+	scf := newDualTestSCF()
+	assert.Nil(t, Simplex(scf, common.DefaultSolverConfig()))
+
+	// Raising the first right-hand side to 8 puts x2 at -1 in the old basis
+	warm := newDualTestSCF()
+	warm.RHS = mat.NewVecDense(2, []float64{8, 6})
+	warm.Basis, warm.AtUpper = scf.Basis, scf.AtUpper
+	assert.Nil(t, Simplex(warm, common.DefaultSolverConfig()))
+	assert.Equal(t, *warm.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, *warm.ObjectiveValue, 16, 1e-9)
+	assert.IsClose(t, warm.PrimalSolution.AtVec(0), 8, 1e-9)
+}
+
+func TestSimplexWarmStartUnusable(t *testing.T) {
+	// A repeated column is not a basis, so the solve starts cold
+	scf := newDualTestSCF()
+	scf.Basis = []int{0, 0}
+	scf.AtUpper = make([]bool, 4)
+	assert.Nil(t, Simplex(scf, common.DefaultSolverConfig()))
+	assert.Equal(t, *scf.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, *scf.ObjectiveValue, 9, 1e-9)
+}
@@ -2,6 +2,7 @@ package lp
 
 import (
 	"fmt"
+
 	"gonum.org/v1/gonum/mat"
 )
 
@@ -84,10 +85,12 @@ func (lp *LinearProgram) AddConstraint(expr LpExpression, conType LpConstraintTy
 		lp.Constraints = mat.NewDense(1, len(lp.Vars), newRow)
 		lp.RHS = mat.NewVecDense(1, []float64{rhs})
 		lp.ConTypes = []LpConstraintType{conType}
+		lp.ConFlipped = []bool{flipped}
 	} else {
 		lp.Constraints = mat.NewDense(lp.Constraints.RawMatrix().Rows+1, len(lp.Vars), append(lp.Constraints.RawMatrix().Data, newRow...))
 		lp.RHS = mat.NewVecDense(lp.RHS.Len()+1, append(lp.RHS.RawVector().Data, rhs))
 		lp.ConTypes = append(lp.ConTypes, conType)
+		lp.ConFlipped = append(lp.ConFlipped, flipped)
 	}
 
 	// If the constraint is GE or LE we need to add a slack/surplus variable
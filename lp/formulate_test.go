@@ -63,7 +63,8 @@ func TestAddConstraint(t *testing.T) {
 	})
 	lp.AddConstraint(expr, LpConstraintLE, 4)
 
-	assert.Equal(t, lp.Constraints.RawMatrix().Rows, 1)
+	rows, _ := lp.Constraints.Dims()
+	assert.Equal(t, rows, 1)
 	assert.Equal(t, len(lp.Vars), 3) // slack variable added
 
 	// GE constraint with negative RHS flips
@@ -72,5 +73,6 @@ func TestAddConstraint(t *testing.T) {
 		NewTerm(1, x2),
 	})
 	lp.AddConstraint(expr2, LpConstraintGE, -3)
-	assert.Equal(t, lp.Constraints.RawMatrix().Rows, 2)
+	rows, _ = lp.Constraints.Dims()
+	assert.Equal(t, rows, 2)
 }
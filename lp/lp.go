@@ -9,17 +9,22 @@ import (
 type LinearProgram struct {
 	// Problem definition
 	Objective   *mat.VecDense      // c
-	Constraints *mat.Dense         // A
+	Constraints *mat.Dense         // A; the solver works on a sparse copy
 	RHS         *mat.VecDense      // b
 	Sense       LpSense            // Minimize or Maximize
 	ConTypes    []LpConstraintType // metadata for constraints
 	Vars        []LpVariable       // metadata for variables
 
+	// ConFlipped marks constraints that AddConstraint negated to keep the RHS
+	// non-negative, so that dual values can be reported against the row as the
+	// user wrote it.
+	ConFlipped []bool
+
 	// Solution
 	ObjectiveValue float64
 	PrimalSolution *mat.VecDense // x*
-	// DualSolution   *mat.VecDense // y*
-	Status common.SolverStatus
+	DualSolution   *mat.VecDense // y*
+	Status         common.SolverStatus
 
 	// Simplex internal state (kept unexported for future use)
 	// (fields removed to satisfy linters until used)
@@ -1,5 +1,7 @@
 package lp
 
+import "math"
+
 // LpExpression represents the LHS of a linear expression
 type LpExpression struct {
 	Terms []LpTerm
@@ -22,27 +24,96 @@ func NewTerm(coefficient float64, variable LpVariable) LpTerm {
 }
 
 // LpVariable represents a variable in a linear programming problem.
+//
+// Variables are bounded below by zero unless different bounds are supplied
+// through NewVariable. A zero-valued LpVariable therefore models x >= 0.
 type LpVariable struct {
 	Name         string
 	IsSlack      bool
 	IsArtificial bool
 	Category     LpCategory
+
+	// Bounds set through NewVariable; ignored unless hasBounds is true so that
+	// literal LpVariable values keep the default x >= 0.
+	lower     float64
+	upper     float64
+	hasBounds bool
 }
 
 // NewVariable creates a new LpVariable with the given name.
-func NewVariable(name string, category ...LpCategory) LpVariable {
-	if len(category) > 1 {
+//
+// Options may be an LpCategory (at most one) and any number of bound options
+// such as WithLowerBound, WithUpperBound or WithBounds. Later bound options
+// override earlier ones.
+func NewVariable(name string, opts ...LpVariableOption) LpVariable {
+	v := LpVariable{Name: name, Category: LpCategoryContinuous} // Default to continuous variable
+
+	categories := 0
+	for _, opt := range opts {
+		if _, ok := opt.(LpCategory); ok {
+			categories++
+		}
+		opt.applyTo(&v)
+	}
+	if categories > 1 {
 		panic("Only one LpCategory can be specified for a variable")
 	}
-	if len(category) == 0 {
-		return LpVariable{name, false, false, LpCategoryContinuous} // Default to continuous variable
+	return v
+}
+
+// Bounds returns the lower and upper bound of the variable. Infinite bounds are
+// reported as math.Inf(-1) and math.Inf(1) respectively.
+func (v LpVariable) Bounds() (lower, upper float64) {
+	if !v.hasBounds {
+		return 0, math.Inf(1)
 	}
-	return LpVariable{name, false, false, category[0]}
+	return v.lower, v.upper
+}
+
+// HasDefaultBounds reports whether the variable uses the default x >= 0 bounds.
+func (v LpVariable) HasDefaultBounds() bool {
+	lower, upper := v.Bounds()
+	return lower == 0 && math.IsInf(upper, 1)
+}
+
+// LpVariableOption configures a variable created by NewVariable.
+type LpVariableOption interface {
+	applyTo(v *LpVariable)
+}
+
+type boundOption func(v *LpVariable)
+
+func (o boundOption) applyTo(v *LpVariable) { o(v) }
+
+// WithLowerBound sets the lower bound of a variable. Use math.Inf(-1) to remove it.
+func WithLowerBound(lower float64) LpVariableOption {
+	return boundOption(func(v *LpVariable) {
+		_, upper := v.Bounds()
+		v.lower, v.upper, v.hasBounds = lower, upper, true
+	})
+}
+
+// WithUpperBound sets the upper bound of a variable. Use math.Inf(1) to remove it.
+func WithUpperBound(upper float64) LpVariableOption {
+	return boundOption(func(v *LpVariable) {
+		lower, _ := v.Bounds()
+		v.lower, v.upper, v.hasBounds = lower, upper, true
+	})
+}
+
+// WithBounds sets both bounds of a variable. WithBounds(math.Inf(-1), math.Inf(1))
+// declares a free variable.
+func WithBounds(lower, upper float64) LpVariableOption {
+	return boundOption(func(v *LpVariable) {
+		v.lower, v.upper, v.hasBounds = lower, upper, true
+	})
 }
 
 // LpCategory represents the category of a linear programming variable, such as continuous, integer, or binary.
 type LpCategory int
 
+func (c LpCategory) applyTo(v *LpVariable) { v.Category = c }
+
 const (
 	LpCategoryContinuous LpCategory = iota
 	LpCategoryInteger
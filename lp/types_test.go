@@ -1,6 +1,7 @@
 package lp
 
 import (
+	"math"
 	"testing"
 
 	"github.com/chriso345/gore/assert"
@@ -23,3 +24,32 @@ func TestNewTermVariableExpression(t *testing.T) {
 	expr := NewExpression([]LpTerm{term})
 	assert.Equal(t, len(expr.Terms), 1)
 }
+
+func TestNewVariableBounds(t *testing.T) {
+	x := NewVariable("x")
+	lower, upper := x.Bounds()
+	assert.Equal(t, lower, 0.0)
+	assert.True(t, math.IsInf(upper, 1))
+	assert.True(t, x.HasDefaultBounds())
+
+	// Zero-valued literals keep the default x >= 0
+	lower, upper = LpVariable{Name: "lit"}.Bounds()
+	assert.Equal(t, lower, 0.0)
+	assert.True(t, math.IsInf(upper, 1))
+
+	y := NewVariable("y", LpCategoryInteger, WithLowerBound(-5), WithUpperBound(10))
+	lower, upper = y.Bounds()
+	assert.Equal(t, y.Category, LpCategoryInteger)
+	assert.Equal(t, lower, -5.0)
+	assert.Equal(t, upper, 10.0)
+	assert.False(t, y.HasDefaultBounds())
+
+	free := NewVariable("f", WithBounds(math.Inf(-1), math.Inf(1)))
+	lower, upper = free.Bounds()
+	assert.True(t, math.IsInf(lower, -1))
+	assert.True(t, math.IsInf(upper, 1))
+
+	assert.Panic(t, func() {
+		NewVariable("z", LpCategoryInteger, LpCategoryBinary)
+	})
+}
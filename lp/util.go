@@ -56,11 +56,12 @@ func (lp *LinearProgram) String() string {
 
 	// Constraints
 	sb.WriteString("Subject to:\n")
-	for row := range lp.Constraints.RawMatrix().Rows {
+	rows, cols := lp.Constraints.Dims()
+	for row := range rows {
 		sb.WriteString(fmt.Sprintf("  C%d: ", row+1))
 
 		first = true
-		for col := range lp.Constraints.RawMatrix().Cols {
+		for col := range cols {
 			coef := lp.Constraints.At(row, col)
 			if lp.Vars[col].IsSlack {
 				continue
@@ -98,6 +99,29 @@ func (lp *LinearProgram) String() string {
 		sb.WriteString(fmt.Sprintf("%.3f\n", lp.RHS.AtVec(row)))
 	}
 
+	// Explicit variable bounds
+	boundsHeader := false
+	for _, v := range lp.Vars {
+		if v.IsSlack || v.HasDefaultBounds() {
+			continue
+		}
+		if !boundsHeader {
+			sb.WriteString("Bounds:\n")
+			boundsHeader = true
+		}
+		lower, upper := v.Bounds()
+		switch {
+		case math.IsInf(lower, -1) && math.IsInf(upper, 1):
+			sb.WriteString(fmt.Sprintf("  %s free\n", v.Name))
+		case math.IsInf(lower, -1):
+			sb.WriteString(fmt.Sprintf("  %s <= %.3f\n", v.Name, upper))
+		case math.IsInf(upper, 1):
+			sb.WriteString(fmt.Sprintf("  %s >= %.3f\n", v.Name, lower))
+		default:
+			sb.WriteString(fmt.Sprintf("  %.3f <= %s <= %.3f\n", lower, v.Name, upper))
+		}
+	}
+
 	// Variable bounds (integer, binary)
 	intVars := []string{}
 	binVars := []string{}
@@ -2,6 +2,7 @@ package lp
 
 import (
 	"bytes"
+	"math"
 	"os"
 	"testing"
 
@@ -47,3 +48,18 @@ func TestPrintSolution(t *testing.T) {
 	assert.StringContains(t, buf.String(), "x1")
 	assert.StringContains(t, buf.String(), "x2")
 }
+
+func TestStringBounds(t *testing.T) {
+	x := NewVariable("x", WithBounds(-5, 10))
+	y := NewVariable("y", WithLowerBound(math.Inf(-1)))
+	z := NewVariable("z")
+	lp := NewLinearProgram("Bounds LP", []LpVariable{x, y, z})
+	lp.AddObjective(LpMinimise, NewExpression([]LpTerm{NewTerm(1, x), NewTerm(1, y), NewTerm(1, z)}))
+	lp.AddConstraint(NewExpression([]LpTerm{NewTerm(1, x), NewTerm(1, y)}), LpConstraintLE, 4)
+
+	out := lp.String()
+	assert.StringContains(t, out, "Bounds:")
+	assert.StringContains(t, out, "-5.000 <= x <= 10.000")
+	assert.StringContains(t, out, "y free")
+	assert.NotStringContains(t, out, "z >=")
+}
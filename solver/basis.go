@@ -0,0 +1,135 @@
+package solver
+
+import (
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/errors"
+	"github.com/chriso345/gspl/internal/matrix"
+	"github.com/chriso345/gspl/lp"
+	"gonum.org/v1/gonum/mat"
+)
+
+// slackRows returns the row of each slack column of the program, or -1 for
+// the columns of primal variables.
+func slackRows(prog *lp.LinearProgram) []int {
+	rows := make([]int, len(prog.Vars))
+	var A *matrix.CSC
+	for j, v := range prog.Vars {
+		rows[j] = -1
+		if !v.IsSlack || prog.Constraints == nil {
+			continue
+		}
+		if A == nil {
+			A = matrix.AsCSC(prog.Constraints)
+		}
+		if r, _ := A.Col(j); len(r) == 1 {
+			rows[j] = r[0]
+		}
+	}
+	return rows
+}
+
+// newBasis reads the final basis of scf in terms of the program's variables
+// and constraints. The artificial column of a row stands for the slack of an
+// equality row, which has no column of its own.
+func newBasis(prog *lp.LinearProgram, scf *common.StandardComputationalForm) *Basis {
+	m, n := scf.Constraints.Dims()
+	b := &Basis{
+		Variables:   make([]BasisStatus, scf.NumPrimals),
+		Constraints: make([]BasisStatus, m),
+	}
+	rows := slackRows(prog)
+	set := func(j int, s BasisStatus) {
+		switch {
+		case j >= n:
+			b.Constraints[j-n] = s
+		case rows[j] >= 0:
+			b.Constraints[rows[j]] = s
+		case j < scf.NumPrimals:
+			b.Variables[j] = s
+		}
+	}
+
+	for j := range n {
+		if scf.AtUpper[j] {
+			set(j, BasisAtUpper)
+		}
+	}
+	for _, j := range scf.Basis {
+		set(j, BasisBasic)
+	}
+	return b
+}
+
+// warmStart records basis on scf as the starting basis of its simplex solve.
+func warmStart(prog *lp.LinearProgram, scf *common.StandardComputationalForm, basis *Basis) error {
+	m, n := scf.Constraints.Dims()
+	if len(basis.Variables) != scf.NumPrimals || len(basis.Constraints) != m {
+		return errors.New(errors.ErrInvalidInput, "warm start basis does not match the program", nil)
+	}
+
+	// The slack column of each row, or its artificial column for an equality
+	slack := make([]int, m)
+	for i := range slack {
+		slack[i] = n + i
+	}
+	for j, i := range slackRows(prog) {
+		if i >= 0 {
+			slack[i] = j
+		}
+	}
+
+	scf.Basis = make([]int, 0, m)
+	scf.AtUpper = make([]bool, n)
+	place := func(j int, s BasisStatus) {
+		switch s {
+		case BasisBasic:
+			scf.Basis = append(scf.Basis, j)
+		case BasisAtUpper:
+			if j < n {
+				scf.AtUpper[j] = true
+			}
+		}
+	}
+	for j, s := range basis.Variables {
+		place(j, s)
+	}
+	for i, s := range basis.Constraints {
+		place(slack[i], s)
+	}
+
+	if len(scf.Basis) != m {
+		scf.Basis, scf.AtUpper = nil, nil
+		return errors.New(errors.ErrInvalidInput, "warm start basis needs one basic entry per constraint", nil)
+	}
+	return nil
+}
+
+// startPoint records x on scf as the point crossover starts from, giving each
+// slack column the value that satisfies its row.
+func startPoint(prog *lp.LinearProgram, scf *common.StandardComputationalForm, x *mat.VecDense) error {
+	m, n := scf.Constraints.Dims()
+	if x.Len() != scf.NumPrimals {
+		return errors.New(errors.ErrInvalidInput, "start point does not match the program", nil)
+	}
+
+	// Activity of the primal columns; the stored rows are the negated ones
+	// for a flipped constraint, as is the slack column
+	point := mat.NewVecDense(n, nil)
+	activity := mat.NewVecDense(m, nil)
+	primal := 0
+	for j, v := range prog.Vars {
+		if v.IsSlack {
+			continue
+		}
+		point.SetVec(j, x.AtVec(primal))
+		matrix.AddScaledCol(activity, x.AtVec(primal), scf.Constraints, j)
+		primal++
+	}
+	for j, i := range slackRows(prog) {
+		if i >= 0 {
+			point.SetVec(j, (scf.RHS.AtVec(i)-activity.AtVec(i))/scf.Constraints.At(i, j))
+		}
+	}
+	scf.PrimalSolution = point
+	return nil
+}
@@ -0,0 +1,49 @@
+package solver
+
+import (
+	"math"
+
+	"github.com/chriso345/gspl/lp"
+	"gonum.org/v1/gonum/mat"
+)
+
+// variableBounds returns the effective bounds of a variable, tightened for its
+// category: binaries are confined to [0, 1] and integer bounds are rounded inwards.
+func variableBounds(v lp.LpVariable) (float64, float64) {
+	lower, upper := v.Bounds()
+	switch v.Category {
+	case lp.LpCategoryBinary:
+		lower = math.Max(lower, 0)
+		upper = math.Min(upper, 1)
+		fallthrough
+	case lp.LpCategoryInteger:
+		lower = math.Ceil(lower)
+		upper = math.Floor(upper)
+	}
+	return lower, upper
+}
+
+// columnBounds returns the lower and upper bound vectors for the program's
+// columns, or nil vectors when every variable keeps the default x >= 0.
+func columnBounds(prog *lp.LinearProgram) (lower, upper *mat.VecDense) {
+	bounded := false
+	for _, v := range prog.Vars {
+		l, u := variableBounds(v)
+		if l != 0 || !math.IsInf(u, 1) {
+			bounded = true
+			break
+		}
+	}
+	if !bounded {
+		return nil, nil
+	}
+
+	lower = mat.NewVecDense(len(prog.Vars), nil)
+	upper = mat.NewVecDense(len(prog.Vars), nil)
+	for j, v := range prog.Vars {
+		l, u := variableBounds(v)
+		lower.SetVec(j, l)
+		upper.SetVec(j, u)
+	}
+	return lower, upper
+}
@@ -0,0 +1,51 @@
+package solver
+
+import (
+	"math/big"
+
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/exact"
+	"github.com/chriso345/gspl/lp"
+)
+
+// ExactSolution holds the optimal solution of a continuous program in exact
+// rational arithmetic, in the same sense and with the same sign conventions as
+// the float64 fields of Solution.
+//
+// It certifies its own optimality: PrimalSolution satisfies every constraint
+// and bound exactly, DualSolution and ReducedCosts satisfy the dual
+// constraints exactly, and both give ObjectiveValue.
+type ExactSolution struct {
+	ObjectiveValue *big.Rat
+	PrimalSolution []*big.Rat // one entry per primal variable
+	DualSolution   []*big.Rat // one entry per constraint
+	ReducedCosts   []*big.Rat // one entry per primal variable
+}
+
+// newExactSolution maps the exact solution of the solver's minimisation form
+// back to the program as the user wrote it.
+func newExactSolution(prog *lp.LinearProgram, scf *common.StandardComputationalForm, ex *exact.Solution) *ExactSolution {
+	// A maximisation minimises -c, flipping the objective and the duals
+	flip := func(r *big.Rat, negate bool) *big.Rat {
+		out := new(big.Rat).Set(r)
+		if negate {
+			out.Neg(out)
+		}
+		return out
+	}
+
+	s := &ExactSolution{
+		ObjectiveValue: flip(ex.Objective, scf.IsMaximization),
+		PrimalSolution: make([]*big.Rat, scf.NumPrimals),
+		DualSolution:   make([]*big.Rat, len(ex.Y)),
+		ReducedCosts:   make([]*big.Rat, scf.NumPrimals),
+	}
+	for j := 0; j < scf.NumPrimals; j++ {
+		s.PrimalSolution[j] = flip(ex.X[j], false)
+		s.ReducedCosts[j] = flip(ex.ReducedCosts[j], scf.IsMaximization)
+	}
+	for i, y := range ex.Y {
+		s.DualSolution[i] = flip(y, scf.IsMaximization != conFlipped(prog, i))
+	}
+	return s
+}
@@ -2,8 +2,11 @@ package solver
 
 import (
 	"context"
+	"log/slog"
+	"time"
 
 	"github.com/chriso345/gspl/internal/common"
+	"gonum.org/v1/gonum/mat"
 )
 
 // SolverOption defines a function that modifies SolverConfig.
@@ -23,32 +26,182 @@ func WithContext(ctx context.Context) SolverOption {
 	}
 }
 
-// WithMaxIterations sets the maximum number of iterations.
+// WithTimeLimit sets a wall-clock limit on a solve. A solve that reaches it
+// stops with SolverStatusTimeLimit and the point or incumbent reached so far.
+func WithTimeLimit(d time.Duration) SolverOption {
+	return func(cfg *common.SolverConfig) {
+		cfg.TimeLimit = d
+	}
+}
+
+// WithCallback reports the progress of a solve to cb: an EventPhase as each
+// run of the simplex method begins, an EventIteration every `every` pivots
+// (none when every is not positive), and for integer programs an EventNode for
+// each branch-and-bound node solved and an EventIncumbent for each better
+// integer solution. Objective values are in the sense of the program. Calls
+// are never concurrent, but they may come from different goroutines.
+//
+// If cb returns an error the solve stops at its next limit check, as if its
+// context were cancelled: unless it had already finished, Status is
+// SolverStatusCancelled and the Solution holds the point or incumbent reached.
+func WithCallback(cb Callback, every int) SolverOption {
+	return func(cfg *common.SolverConfig) {
+		cfg.Callback = cb
+		cfg.CallbackInterval = every
+	}
+}
+
+// WithMaxIterations sets the maximum number of simplex iterations of each LP
+// solve. A solve that reaches it stops with SolverStatusIterationLimit.
+// AlgorithmPDLP, whose iterations are far cheaper than pivots, may take 100
+// iterations for each one allowed.
 func WithMaxIterations(max int) SolverOption {
 	return func(cfg *common.SolverConfig) {
 		cfg.MaxIterations = max
 	}
 }
 
-// WithGapSensitivity sets the gap sensitivity.
+// WithAlgorithm selects the algorithm used for continuous programs. The
+// default is the two-phase primal simplex. AlgorithmBarrier and AlgorithmPDLP
+// return solutions without a basis; integer programs are always solved with
+// the simplex method, whose bases warm start the nodes.
+func WithAlgorithm(a Algorithm) SolverOption {
+	return func(cfg *common.SolverConfig) {
+		cfg.Algorithm = a
+	}
+}
+
+// WithPricing selects the rule the primal simplex uses to choose its entering
+// column. The default is PricingDantzig. Steepest edge and Devex usually need
+// fewer iterations at a higher cost per iteration; partial pricing makes each
+// iteration cheaper on problems with many columns.
+func WithPricing(p Pricing) SolverOption {
+	return func(cfg *common.SolverConfig) {
+		cfg.Pricing = p
+	}
+}
+
+// WithCrossover moves the solution found by AlgorithmBarrier or AlgorithmPDLP
+// to an optimal basic solution, so that the Solution is a vertex. It has no
+// effect on the simplex algorithms, whose solutions are already basic.
+func WithCrossover(enabled bool) SolverOption {
+	return func(cfg *common.SolverConfig) {
+		cfg.Crossover = enabled
+	}
+}
+
+// WithExact finishes the solve of a continuous program in exact rational
+// arithmetic, starting from the basis the floating point solve ends on, and
+// reports the result in Solution.Exact. Status then reflects the exact solve.
+// Coefficients are read as the shortest decimals that print as their float64
+// values. It implies WithCrossover for AlgorithmBarrier and AlgorithmPDLP, so
+// that there is a basis to start from, and has no effect on integer programs.
+func WithExact(enabled bool) SolverOption {
+	return func(cfg *common.SolverConfig) {
+		cfg.Exact = enabled
+	}
+}
+
+// WithWarmStart starts the simplex method of a continuous solve from basis,
+// typically the Solution.Basis of an earlier solve of a similar program. A
+// basis that is still primal feasible skips Phase 1 and one that is still
+// dual feasible is re-optimised by the dual simplex; any other basis, or one
+// that is singular for the new data, falls back to a cold start. Presolve is
+// skipped, as it would change the columns the basis refers to. It has no
+// effect on AlgorithmBarrier, AlgorithmPDLP or integer programs.
+func WithWarmStart(basis *Basis) SolverOption {
+	return func(cfg *common.SolverConfig) {
+		cfg.WarmStart = basis
+	}
+}
+
+// WithStartPoint solves a continuous program by crossover from x, one value
+// per variable of the program, such as the Solution.PrimalSolution of an
+// earlier solve or a point computed elsewhere, in place of the algorithm
+// selected by WithAlgorithm. Values within the tolerance of a bound are moved
+// onto it and the resulting basis is made optimal by the simplex method, so x
+// need not be a vertex or exactly feasible. Presolve is skipped, as it would
+// change the columns x refers to. It has no effect on integer programs.
+func WithStartPoint(x *mat.VecDense) SolverOption {
+	return func(cfg *common.SolverConfig) {
+		cfg.StartPoint = x
+	}
+}
+
+// WithSensitivity enables objective and right-hand side ranging for
+// continuous programs, reported in Solution.Sensitivity. Ranging needs an
+// optimal basis, so with AlgorithmBarrier or AlgorithmPDLP it also needs
+// WithCrossover.
+func WithSensitivity(enabled bool) SolverOption {
+	return func(cfg *common.SolverConfig) {
+		cfg.Sensitivity = enabled
+	}
+}
+
+// WithPresolve enables or disables the presolve pass for continuous
+// programs. Presolve is on by default and is skipped when sensitivity
+// analysis is requested.
+func WithPresolve(enabled bool) SolverOption {
+	return func(cfg *common.SolverConfig) {
+		cfg.Presolve = enabled
+	}
+}
+
+// WithScaling selects how rows and columns of a continuous program are scaled
+// before it is solved. The default is ScalingGeometric; ScalingNone disables
+// scaling.
+func WithScaling(s Scaling) SolverOption {
+	return func(cfg *common.SolverConfig) {
+		cfg.Scaling = s
+	}
+}
+
+// WithGapSensitivity sets the gap sensitivity: branch and bound stops with
+// SolverStatusGapLimit once the incumbent is within this gap, relative to its
+// objective, of the best bound on the open nodes. The default of zero searches
+// until the incumbent is proven optimal.
 func WithGapSensitivity(gap float64) SolverOption {
 	return func(cfg *common.SolverConfig) {
 		cfg.GapSensitivity = gap
 	}
 }
 
+// WithNodeSelection selects the order in which branch and bound explores open
+// nodes. The default is NodeDepthFirst, which finds incumbents soonest and
+// keeps fewer nodes open; NodeBestFirst usually needs fewer nodes to prove
+// optimality, and NodeBestEstimate favours nodes likely to hold good
+// incumbents.
+func WithNodeSelection(s NodeSelection) SolverOption {
+	return func(cfg *common.SolverConfig) {
+		cfg.NodeSelection = s
+	}
+}
+
 // WithThreads sets the number of threads to use.
 func WithThreads(n int) SolverOption {
 	panic("multi-threading not yet implemented")
 }
 
-// WithLogging enables or disables logging.
+// WithLogging enables or disables logging. Without a logger set by
+// WithLogger, records of info level and above are written as text to
+// standard error.
 func WithLogging(enabled bool) SolverOption {
 	return func(cfg *common.SolverConfig) {
 		cfg.Logging = enabled
 	}
 }
 
+// WithLogger sends the records of a solve to logger: the start and outcome of
+// the solve and each new incumbent at info level, simplex phases and pivots,
+// presolve and branch and bound nodes at debug level, and a search stopped by
+// a limit or a failed node at warn and error level. It takes precedence over
+// WithLogging.
+func WithLogger(logger *slog.Logger) SolverOption {
+	return func(cfg *common.SolverConfig) {
+		cfg.Logger = logger
+	}
+}
+
 /// Strategy Functions Options
 
 // WithBranch sets the branching strategy function.
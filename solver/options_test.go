@@ -1,10 +1,13 @@
 package solver
 
 import (
+	"io"
+	"log/slog"
 	"testing"
 
 	"github.com/chriso345/gore/assert"
 	"github.com/chriso345/gspl/internal/common"
+	"gonum.org/v1/gonum/mat"
 )
 
 func TestWithTolerance(t *testing.T) {
@@ -27,6 +30,19 @@ func TestWithLogging(t *testing.T) {
 	assert.True(t, cfg.Logging)
 }
 
+func TestWithNodeSelection(t *testing.T) {
+	cfg := NewSolverConfig(WithNodeSelection(NodeBestFirst))
+	assert.Equal(t, cfg.NodeSelection, NodeBestFirst)
+	assert.Equal(t, NewSolverConfig().NodeSelection, NodeDepthFirst)
+}
+
+func TestWithLogger(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := NewSolverConfig(WithLogger(logger))
+	assert.True(t, cfg.Logger == logger)
+	assert.True(t, NewSolverConfig().Logger == nil)
+}
+
 func TestNewSolverConfig_Defaults(t *testing.T) {
 	cfg := NewSolverConfig()
 	defaults := common.DefaultSolverConfig()
@@ -47,3 +63,25 @@ func TestUnimplementedStrategyOptions_Panic(t *testing.T) {
 	assert.Panic(t, func() { _ = WithHeuristic(nil) })
 	assert.Panic(t, func() { _ = WithCut(nil) })
 }
+
+func TestWithWarmStart(t *testing.T) {
+	basis := &Basis{Variables: []BasisStatus{BasisBasic}}
+	cfg := NewSolverConfig(WithWarmStart(basis))
+	assert.True(t, cfg.WarmStart == basis)
+	assert.True(t, NewSolverConfig().WarmStart == nil)
+}
+
+func TestWithStartPoint(t *testing.T) {
+	x := mat.NewVecDense(2, []float64{1, 2})
+	cfg := NewSolverConfig(WithStartPoint(x))
+	assert.True(t, cfg.StartPoint == x)
+	assert.True(t, NewSolverConfig().StartPoint == nil)
+}
+
+func TestWithCallback(t *testing.T) {
+	calls := 0
+	cfg := NewSolverConfig(WithCallback(func(Event) error { calls++; return nil }, 10))
+	assert.Equal(t, cfg.CallbackInterval, 10)
+	cfg.Notify(Event{})
+	assert.Equal(t, calls, 1)
+}
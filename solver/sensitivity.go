@@ -0,0 +1,54 @@
+package solver
+
+import (
+	"github.com/chriso345/gspl/internal/common"
+	"github.com/chriso345/gspl/internal/simplex"
+	"github.com/chriso345/gspl/lp"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Sensitivity reports how far each objective coefficient and each constraint
+// right-hand side may move, one at a time, before the optimal basis changes.
+//
+// Within an objective range the primal solution is unchanged; within a RHS
+// range the dual values are unchanged and the objective moves at the rate of
+// the constraint's dual value. Every entry is an allowable amount measured
+// from the current value in the sense of the original problem, so it is
+// non-negative and may be +Inf.
+type Sensitivity struct {
+	ObjectiveDecrease *mat.VecDense // one entry per primal variable
+	ObjectiveIncrease *mat.VecDense // one entry per primal variable
+	RHSDecrease       *mat.VecDense // one entry per constraint
+	RHSIncrease       *mat.VecDense // one entry per constraint
+}
+
+// newSensitivity maps the ranging of the solver's minimisation form back to
+// the program as the user wrote it.
+func newSensitivity(prog *lp.LinearProgram, scf *common.StandardComputationalForm, rg *simplex.Ranging) *Sensitivity {
+	s := &Sensitivity{
+		ObjectiveDecrease: mat.NewVecDense(scf.NumPrimals, nil),
+		ObjectiveIncrease: mat.NewVecDense(scf.NumPrimals, nil),
+		RHSDecrease:       mat.VecDenseCopyOf(rg.RHSDecrease),
+		RHSIncrease:       mat.VecDenseCopyOf(rg.RHSIncrease),
+	}
+
+	// A maximisation minimises -c, so raising c lowers the solver's cost
+	for j := 0; j < scf.NumPrimals; j++ {
+		dec, inc := rg.CostDecrease.AtVec(j), rg.CostIncrease.AtVec(j)
+		if scf.IsMaximization {
+			dec, inc = inc, dec
+		}
+		s.ObjectiveDecrease.SetVec(j, dec)
+		s.ObjectiveIncrease.SetVec(j, inc)
+	}
+
+	// Rows negated by AddConstraint have their RHS negated as well
+	for i := 0; i < s.RHSDecrease.Len(); i++ {
+		if conFlipped(prog, i) {
+			s.RHSDecrease.SetVec(i, rg.RHSIncrease.AtVec(i))
+			s.RHSIncrease.SetVec(i, rg.RHSDecrease.AtVec(i))
+		}
+	}
+
+	return s
+}
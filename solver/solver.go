@@ -2,11 +2,20 @@ package solver
 
 import (
 	"context"
+	"log/slog"
 	"math"
+	"sync"
+	"time"
 
+	"github.com/chriso345/gspl/internal/barrier"
 	"github.com/chriso345/gspl/internal/brancher"
 	"github.com/chriso345/gspl/internal/common"
 	"github.com/chriso345/gspl/internal/errors"
+	"github.com/chriso345/gspl/internal/exact"
+	"github.com/chriso345/gspl/internal/matrix"
+	"github.com/chriso345/gspl/internal/pdlp"
+	"github.com/chriso345/gspl/internal/presolve"
+	"github.com/chriso345/gspl/internal/scaling"
 	"github.com/chriso345/gspl/internal/simplex"
 	"github.com/chriso345/gspl/lp"
 	"gonum.org/v1/gonum/mat"
@@ -24,10 +33,48 @@ import (
 // that point into the provided LinearProgram; therefore callers MUST NOT mutate
 // the provided *lp.LinearProgram concurrently with a call to Solve.
 // To cancel a long-running solve pass a context using the WithContext option.
+//
+// Dual values and reduced costs are reported in the sense of the original
+// problem and against each constraint as it was written, so for a
+// maximisation the dual of a binding <= row is non-negative. They are only
+// available for continuous programs; for integer programs they are nil.
+// RowActivity holds a_i·x for each constraint and Slack holds rhs_i - a_i·x.
+//
+// A continuous program found infeasible comes with a Farkas certificate in
+// FarkasRay: multipliers y for the constraints as written, non-positive on <=
+// rows and non-negative on >= rows, such that y·rhs exceeds the largest value
+// y^T A x takes on any x within the variable bounds. One found unbounded
+// comes with a direction d in UnboundedRay along which every constraint and
+// bound stays satisfied and the objective improves without limit. Both are
+// scaled so that their largest entry has magnitude one.
+//
+// Basis holds the final simplex basis of a continuous solve that ended on
+// one, optimal or stopped by a limit, with the status of every variable and of
+// the slack of every constraint. Passed to WithWarmStart it lets a later solve
+// of a similar program start from it.
+//
+// Stats records the iterations, factorisations and time each phase of the
+// solve took and, for an integer program, the branch-and-bound search.
+//
+// When a limit stops the solve, Status is SolverStatusIterationLimit,
+// SolverStatusTimeLimit or SolverStatusCancelled. For a continuous program the
+// Solution then holds the basic solution of the last basis reached, which need
+// not be feasible, and no duals; for an integer program it holds the best
+// incumbent found, if any.
 type Solution struct {
 	ObjectiveValue float64
 	PrimalSolution *mat.VecDense
-	Status         common.SolverStatus
+	DualSolution   *mat.VecDense  // one entry per constraint
+	ReducedCosts   *mat.VecDense  // one entry per primal variable
+	RowActivity    *mat.VecDense  // one entry per constraint
+	Slack          *mat.VecDense  // one entry per constraint
+	Sensitivity    *Sensitivity   // nil unless requested with WithSensitivity
+	Exact          *ExactSolution // nil unless requested with WithExact
+	FarkasRay      *mat.VecDense  // one entry per constraint, when infeasible
+	UnboundedRay   *mat.VecDense  // one entry per primal variable, when unbounded
+	Basis          *Basis         // nil unless the solve ended on a basis
+	Stats          Stats
+	Status         SolverStatus
 }
 
 // ErrorKind and Error are re-exported for public API use
@@ -43,32 +90,149 @@ var (
 
 type Error = errors.Error
 
+// SolverStatus and its values are re-exported for inspecting Solution.Status
+type SolverStatus = common.SolverStatus
+
+const (
+	SolverStatusNotSolved      = common.SolverStatusNotSolved
+	SolverStatusOptimal        = common.SolverStatusOptimal
+	SolverStatusInfeasible     = common.SolverStatusInfeasible
+	SolverStatusUnbounded      = common.SolverStatusUnbounded
+	SolverStatusIterationLimit = common.SolverStatusIterationLimit
+	SolverStatusTimeLimit      = common.SolverStatusTimeLimit
+	SolverStatusCancelled      = common.SolverStatusCancelled
+	SolverStatusGapLimit       = common.SolverStatusGapLimit
+)
+
+// Algorithm and its values are re-exported for use with WithAlgorithm
+type Algorithm = common.Algorithm
+
+const (
+	AlgorithmPrimal  = common.AlgorithmPrimal
+	AlgorithmDual    = common.AlgorithmDual
+	AlgorithmBarrier = common.AlgorithmBarrier
+	AlgorithmPDLP    = common.AlgorithmPDLP
+)
+
+// Basis and BasisStatus are re-exported for Solution.Basis and WithWarmStart
+type Basis = common.Basis
+type BasisStatus = common.BasisStatus
+
+const (
+	BasisAtLower = common.BasisAtLower
+	BasisAtUpper = common.BasisAtUpper
+	BasisBasic   = common.BasisBasic
+)
+
+// Event, Callback and their enumerations are re-exported for use with
+// WithCallback
+type (
+	Event     = common.Event
+	EventKind = common.EventKind
+	Phase     = common.Phase
+	Callback  = common.Callback
+)
+
+const (
+	EventIteration = common.EventIteration
+	EventPhase     = common.EventPhase
+	EventIncumbent = common.EventIncumbent
+	EventNode      = common.EventNode
+
+	PhaseOne    = common.PhaseOne
+	PhaseTwo    = common.PhaseTwo
+	PhaseDual   = common.PhaseDual
+	PhaseBranch = common.PhaseBranch
+)
+
+// NodeSelection and its values are re-exported for use with WithNodeSelection
+type NodeSelection = common.NodeSelection
+
+const (
+	NodeDepthFirst   = common.NodeDepthFirst
+	NodeBestFirst    = common.NodeBestFirst
+	NodeBestEstimate = common.NodeBestEstimate
+)
+
+// Stats is re-exported for Solution.Stats
+type Stats = common.Stats
+
+// Pricing and its values are re-exported for use with WithPricing
+type Pricing = common.Pricing
+
+const (
+	PricingDantzig      = common.PricingDantzig
+	PricingSteepestEdge = common.PricingSteepestEdge
+	PricingDevex        = common.PricingDevex
+	PricingPartial      = common.PricingPartial
+)
+
+// Scaling and its values are re-exported for use with WithScaling
+type Scaling = common.Scaling
+
+const (
+	ScalingNone          = common.ScalingNone
+	ScalingGeometric     = common.ScalingGeometric
+	ScalingEquilibration = common.ScalingEquilibration
+)
+
 // Solve solves the given linear program and returns a Solution and an error.
 //
 // The function returns a populated *Solution on success, or a non-nil error if
 // the solve failed. Solve respects context cancellation when a context is
-// provided via SolverOption (WithContext). It may temporarily link into fields
-// of the provided LinearProgram for efficiency; therefore the provided program
-// must not be mutated concurrently. Solve is safe to call concurrently as long
-// as each goroutine uses a distinct *lp.LinearProgram.
+// provided via SolverOption (WithContext): a context that is done before the
+// solve starts yields its error, while cancellation or a WithTimeLimit deadline
+// during the solve stops it with the point or incumbent reached so far. It may
+// temporarily link into fields of the provided LinearProgram for efficiency;
+// therefore the provided program must not be mutated concurrently. Solve is
+// safe to call concurrently as long as each goroutine uses a distinct
+// *lp.LinearProgram.
 func Solve(prog *lp.LinearProgram, opts ...SolverOption) (*Solution, error) {
 	// Apply options
 	options := NewSolverConfig(opts...)
 	if options.Ctx == nil {
 		options.Ctx = context.Background()
 	}
+	options.Logger = options.Log()
 
 	tol := options.Tolerance
+	start := time.Now()
+	stats := &common.Stats{}
 
-	if hasIPConstraints(prog) {
-		ip := newIP(prog)
+	// A context that is already done fails the solve outright
+	select {
+	case <-options.Ctx.Done():
+		return nil, options.Ctx.Err()
+	default:
+	}
 
-		// Respect context cancellation
-		select {
-		case <-options.Ctx.Done():
-			return nil, options.Ctx.Err()
-		default:
-		}
+	// Once started, the solve stops at the time limit or on cancellation and
+	// reports the point reached with SolverStatusTimeLimit or SolverStatusCancelled
+	if options.TimeLimit > 0 {
+		ctx, cancel := context.WithTimeout(options.Ctx, options.TimeLimit)
+		defer cancel()
+		options.Ctx = ctx
+	}
+
+	// A callback that returns an error cancels the solve in the same way
+	if options.Callback != nil {
+		ctx, cancel := context.WithCancelCause(options.Ctx)
+		defer cancel(nil)
+		options.Ctx = ctx
+		options.Callback = callback(prog, options.Callback, cancel)
+	}
+
+	integer := hasIPConstraints(prog)
+	options.Logger.LogAttrs(options.Ctx, slog.LevelInfo, "solve started",
+		slog.Int("rows", len(prog.ConTypes)),
+		slog.Int("columns", len(prog.Vars)),
+		slog.Bool("integer", integer),
+		slog.String("algorithm", options.Algorithm.String()),
+	)
+
+	if integer {
+		ip := newIP(prog)
+		ip.SCF.Stats = stats
 
 		// Call the Integer Programming solver
 		err := brancher.BranchAndBound(ip, options)
@@ -76,7 +240,8 @@ func Solve(prog *lp.LinearProgram, opts ...SolverOption) (*Solution, error) {
 			return nil, errors.New(errors.ErrUnknown, "integer solve failed", err)
 		}
 
-		sol := &Solution{Status: *ip.SCF.Status}
+		stats.TotalTime = time.Since(start)
+		sol := &Solution{Status: *ip.SCF.Status, Stats: *stats}
 		sol.ObjectiveValue = ip.BestObj
 		sol.PrimalSolution = mat.NewVecDense(ip.SCF.NumPrimals, nil)
 		if ip.BestSolution != nil {
@@ -92,24 +257,39 @@ func Solve(prog *lp.LinearProgram, opts ...SolverOption) (*Solution, error) {
 			// ip.BestObj is already stored in the original problem sense by the
 			// branch-and-bound routine; use it rather than recomputing from the
 			// possibly-negated lp.Objective vector.
+			sol.RowActivity, sol.Slack = rowActivity(prog, sol.PrimalSolution)
 		}
 
+		logSolved(options, sol)
 		return sol, nil
 	}
 
 	// Create the SCF instance
 	scf := newSCF(prog)
+	scf.Stats = stats
+	if options.WarmStart != nil {
+		if err := warmStart(prog, scf, options.WarmStart); err != nil {
+			return nil, err
+		}
+	}
+	if options.StartPoint != nil {
+		if err := startPoint(prog, scf, options.StartPoint); err != nil {
+			return nil, err
+		}
+	}
 
-	// Respect context cancellation
-	select {
-	case <-options.Ctx.Done():
-		return nil, options.Ctx.Err()
-	default:
+	if err := solveContinuous(scf, options); err != nil {
+		return nil, err
 	}
 
-	// Call the Simplex solver
-	if err := simplex.Simplex(scf, options); err != nil {
-		return nil, errors.New(errors.ErrUnknown, "simplex failed", err)
+	// The exact solve reuses the final basis of the original problem, so it
+	// runs after postsolve and unscaling
+	var ex *exact.Solution
+	if options.Exact && !scf.Status.Stopped() {
+		var err error
+		if ex, err = exact.Solve(scf, options); err != nil {
+			return nil, errors.New(errors.ErrUnknown, "exact solve failed", err)
+		}
 	}
 
 	// Copy the solution back without mutating the original problem state
@@ -132,11 +312,158 @@ func Solve(prog *lp.LinearProgram, opts ...SolverOption) (*Solution, error) {
 			}
 			sol.PrimalSolution.SetVec(i, item)
 		}
+		sol.RowActivity, sol.Slack = rowActivity(prog, sol.PrimalSolution)
+	}
+
+	if scf.DualSolution != nil && scf.ReducedCosts != nil {
+		// The SCF duals belong to the minimisation form of the stored rows.
+		// Undo the objective negation for maximisation and the row negation
+		// applied by AddConstraint to negative right-hand sides.
+		sign := 1.0
+		if scf.IsMaximization {
+			sign = -1
+		}
+
+		sol.DualSolution = mat.NewVecDense(scf.DualSolution.Len(), nil)
+		for i := 0; i < scf.DualSolution.Len(); i++ {
+			y := sign * scf.DualSolution.AtVec(i)
+			if conFlipped(prog, i) {
+				y = -y
+			}
+			if y < tol && y > -tol {
+				continue
+			}
+			sol.DualSolution.SetVec(i, y)
+		}
+
+		sol.ReducedCosts = mat.NewVecDense(scf.NumPrimals, nil)
+		for j := 0; j < scf.NumPrimals; j++ {
+			d := sign * scf.ReducedCosts.AtVec(j)
+			if d < tol && d > -tol {
+				continue
+			}
+			sol.ReducedCosts.SetVec(j, d)
+		}
+	}
+
+	if scf.FarkasRay != nil {
+		sol.FarkasRay = normalisedRay(scf.FarkasRay, scf.FarkasRay.Len(), tol)
+		for i := 0; i < sol.FarkasRay.Len(); i++ {
+			if conFlipped(prog, i) {
+				sol.FarkasRay.SetVec(i, -sol.FarkasRay.AtVec(i))
+			}
+		}
+	}
+	if scf.UnboundedRay != nil {
+		sol.UnboundedRay = normalisedRay(scf.UnboundedRay, scf.NumPrimals, tol)
+	}
+
+	if scf.Basis != nil && (sol.Status == common.SolverStatusOptimal || sol.Status.Stopped()) {
+		sol.Basis = newBasis(prog, scf)
 	}
 
+	if options.Sensitivity && sol.Status == common.SolverStatusOptimal && scf.Basis != nil {
+		rg, err := simplex.Sensitivity(scf)
+		if err != nil {
+			return nil, errors.New(errors.ErrNumericalFailure, "sensitivity analysis failed", err)
+		}
+		sol.Sensitivity = newSensitivity(prog, scf, rg)
+	}
+
+	if ex != nil && ex.Status == common.SolverStatusOptimal {
+		sol.Exact = newExactSolution(prog, scf, ex)
+	}
+
+	stats.TotalTime = time.Since(start)
+	sol.Stats = *stats
+	logSolved(options, sol)
 	return sol, nil
 }
 
+// logSolved records the outcome of a solve at info level.
+func logSolved(options *common.SolverConfig, sol *Solution) {
+	st := sol.Stats
+	options.Log().LogAttrs(options.Ctx, slog.LevelInfo, "solve finished",
+		slog.String("status", sol.Status.String()),
+		slog.Float64("objective", sol.ObjectiveValue),
+		slog.Int("iterations", st.Phase1Iterations+st.Phase2Iterations+st.DualIterations+st.InteriorIterations),
+		slog.Int("nodes", st.Nodes),
+		slog.Duration("time", st.TotalTime),
+	)
+}
+
+// callback wraps cb so that its events are reported in the sense of prog,
+// one at a time, and an error from it cancels the solve.
+func callback(prog *lp.LinearProgram, cb Callback, cancel context.CancelCauseFunc) Callback {
+	var mu sync.Mutex
+	return func(e Event) error {
+		if prog.Sense == lp.LpMaximise {
+			if e.Phase != PhaseOne {
+				e.Objective = -e.Objective
+			}
+			e.Incumbent = -e.Incumbent
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		err := cb(e)
+		if err != nil {
+			cancel(err)
+		}
+		return err
+	}
+}
+
+// normalisedRay returns the first n entries of ray divided by the largest of
+// them in magnitude, with entries below tol set to zero.
+func normalisedRay(ray *mat.VecDense, n int, tol float64) *mat.VecDense {
+	scale := 0.
+	for j := range n {
+		scale = math.Max(scale, math.Abs(ray.AtVec(j)))
+	}
+	out := mat.NewVecDense(n, nil)
+	if scale == 0 {
+		return out
+	}
+	for j := range n {
+		if v := ray.AtVec(j) / scale; v >= tol || v <= -tol {
+			out.SetVec(j, v)
+		}
+	}
+	return out
+}
+
+// conFlipped reports whether constraint i was negated by AddConstraint.
+func conFlipped(prog *lp.LinearProgram, i int) bool {
+	return i < len(prog.ConFlipped) && prog.ConFlipped[i]
+}
+
+// rowActivity returns a_i·x and rhs_i - a_i·x for every constraint of the
+// program, measured against the constraints as they were written. x holds the
+// primal variables only; slack columns are ignored.
+func rowActivity(prog *lp.LinearProgram, x *mat.VecDense) (activity, slack *mat.VecDense) {
+	if prog.Constraints == nil {
+		return nil, nil
+	}
+	m, _ := prog.Constraints.Dims()
+	activity = mat.NewVecDense(m, nil)
+	slack = mat.NewVecDense(m, nil)
+	for j := 0; j < x.Len(); j++ {
+		if v := x.AtVec(j); v != 0 {
+			matrix.AddScaledCol(activity, v, prog.Constraints, j)
+		}
+	}
+	for i := 0; i < m; i++ {
+		a, rhs := activity.AtVec(i), prog.RHS.AtVec(i)
+		if conFlipped(prog, i) {
+			a, rhs = -a, -rhs
+		}
+		activity.SetVec(i, a)
+		slack.SetVec(i, rhs-a)
+	}
+	return activity, slack
+}
+
 // newSCF creates a new SCF instance for the linear program
 func newSCF(prog *lp.LinearProgram) *common.StandardComputationalForm {
 	slackIndices := make([]int, len(prog.Vars))
@@ -157,12 +484,23 @@ func newSCF(prog *lp.LinearProgram) *common.StandardComputationalForm {
 	if prog.Sense == lp.LpMaximise && !prog.ObjectiveIsNegated {
 		objCopy.ScaleVec(-1, objCopy)
 	}
+	lower, upper := columnBounds(prog)
+
+	// The solvers work on a sparse copy of A, shared by every node of a
+	// branch-and-bound search
+	var constraints mat.Matrix
+	if prog.Constraints != nil {
+		constraints = matrix.CSCFromMatrix(prog.Constraints)
+	}
 	return &common.StandardComputationalForm{
 		Objective:   objCopy,
-		Constraints: prog.Constraints,
+		Constraints: constraints,
 		RHS:         prog.RHS,
+		Lower:       lower,
+		Upper:       upper,
 
 		PrimalSolution: prog.PrimalSolution,
+		DualSolution:   prog.DualSolution,
 
 		// Link back to the original problem
 		ObjectiveValue: &prog.ObjectiveValue,
@@ -187,3 +525,135 @@ func newIP(prog *lp.LinearProgram) *common.IntegerProgram {
 	}
 	return ip
 }
+
+// solveContinuous runs the selected LP algorithm on scf, on the presolved and
+// scaled problem when enabled, and writes the outcome back onto scf. Ranging
+// describes the basis of the problem actually solved, and a warm start basis
+// or start point refers to the columns of scf, so presolve is skipped for
+// any of them.
+func solveContinuous(scf *common.StandardComputationalForm, options *common.SolverConfig) error {
+	problem := scf
+	var ps *presolve.Presolved
+	if options.Presolve && !options.Sensitivity && options.WarmStart == nil && options.StartPoint == nil {
+		start := time.Now()
+		ps = presolve.Presolve(scf, options.Tolerance)
+		problem = ps.Reduced
+		if scf.Stats != nil {
+			scf.Stats.PresolveTime += time.Since(start)
+		}
+		logPresolve(options, ps)
+	}
+
+	switch {
+	case problem == nil:
+	case options.Scaling == common.ScalingNone:
+		if err := solveLP(problem, options); err != nil {
+			return err
+		}
+	default:
+		sc := scaling.Scale(problem, options.Scaling)
+		if err := solveLP(sc.Problem, options); err != nil {
+			return err
+		}
+		sc.Unscale()
+	}
+
+	if ps != nil {
+		ps.Postsolve()
+	}
+	return certify(scf, options)
+}
+
+// logPresolve records at debug level how much of the problem presolve
+// removed, and whether it decided the outcome without leaving a problem to
+// solve.
+func logPresolve(options *common.SolverConfig, ps *presolve.Presolved) {
+	options.Log().LogAttrs(options.Ctx, slog.LevelDebug, "presolve finished",
+		slog.Int("rows_removed", ps.RowsRemoved),
+		slog.Int("columns_removed", ps.ColsRemoved),
+		slog.Bool("decided", ps.Reduced == nil),
+	)
+}
+
+// certify repeats an infeasible or unbounded solve that left no certificate,
+// as one decided by presolve does, with the primal simplex on scf itself.
+func certify(scf *common.StandardComputationalForm, options *common.SolverConfig) error {
+	status := *scf.Status
+	switch {
+	case status == common.SolverStatusInfeasible && scf.FarkasRay == nil:
+	case status == common.SolverStatusUnbounded && scf.UnboundedRay == nil:
+	default:
+		return nil
+	}
+
+	objVal := 0.
+	cp := &common.StandardComputationalForm{
+		Objective:      scf.Objective,
+		Constraints:    scf.Constraints,
+		RHS:            scf.RHS,
+		Lower:          scf.Lower,
+		Upper:          scf.Upper,
+		ObjectiveValue: &objVal,
+		Status:         new(common.SolverStatus),
+		Stats:          scf.Stats,
+	}
+	if err := simplex.Simplex(cp, options); err != nil {
+		return errors.New(errors.ErrUnknown, "simplex failed", err)
+	}
+	if *cp.Status == status {
+		scf.FarkasRay, scf.UnboundedRay = cp.FarkasRay, cp.UnboundedRay
+	}
+	return nil
+}
+
+// solveLP runs the algorithm selected in options on scf. A barrier solve that
+// stalls, as it does on infeasible and unbounded problems, and a PDLP solve
+// that finds an improving ray but no feasible point are handed to the simplex
+// method, which can tell the two apart. An optimal barrier or PDLP
+// solution is taken to a basis by crossover when requested. A start point
+// goes to crossover directly.
+func solveLP(scf *common.StandardComputationalForm, options *common.SolverConfig) error {
+	if options.StartPoint != nil {
+		if err := simplex.Crossover(scf, options); err != nil {
+			return errors.New(errors.ErrUnknown, "crossover failed", err)
+		}
+		return nil
+	}
+
+	switch options.Algorithm {
+	case common.AlgorithmBarrier:
+		if err := barrier.Solve(scf, options); err != nil {
+			return errors.New(errors.ErrUnknown, "barrier failed", err)
+		}
+		if *scf.Status == common.SolverStatusNotSolved {
+			options.Log().LogAttrs(options.Ctx, slog.LevelInfo, "barrier stalled, switching to simplex")
+			break
+		}
+		return crossover(scf, options)
+	case common.AlgorithmPDLP:
+		if err := pdlp.Solve(scf, options); err != nil {
+			return errors.New(errors.ErrUnknown, "PDLP failed", err)
+		}
+		if *scf.Status == common.SolverStatusNotSolved {
+			options.Log().LogAttrs(options.Ctx, slog.LevelInfo, "PDLP found no feasible point, switching to simplex")
+			break
+		}
+		return crossover(scf, options)
+	}
+	if err := simplex.Solve(scf, options); err != nil {
+		return errors.New(errors.ErrUnknown, "simplex failed", err)
+	}
+	return nil
+}
+
+// crossover moves an optimal interior solution on scf to a basis when
+// options asks for it, or for an exact solve to start from.
+func crossover(scf *common.StandardComputationalForm, options *common.SolverConfig) error {
+	if *scf.Status != common.SolverStatusOptimal || !(options.Crossover || options.Exact) {
+		return nil
+	}
+	if err := simplex.Crossover(scf, options); err != nil {
+		return errors.New(errors.ErrUnknown, "crossover failed", err)
+	}
+	return nil
+}
@@ -1,8 +1,16 @@
 package solver
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/big"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/chriso345/gspl/internal/common"
 	"github.com/chriso345/gspl/lp"
@@ -245,6 +253,54 @@ func TestSolve_Infeasible(t *testing.T) {
 	assert.Equal(t, prog.Status, common.SolverStatusInfeasible)
 }
 
+func TestSolve_FarkasRay(t *testing.T) {
+	// x + y <= 1 and -x - y <= -3 (stored as x + y >= 3) cannot both hold
+	x := lp.NewVariable("x")
+	y := lp.NewVariable("y")
+	prog := lp.NewLinearProgram("Farkas", []lp.LpVariable{x, y})
+	prog.AddObjective(lp.LpMinimise, lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x), lp.NewTerm(1, y)}))
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x), lp.NewTerm(1, y)}), lp.LpConstraintLE, 1)
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(-1, x), lp.NewTerm(-1, y)}), lp.LpConstraintLE, -3)
+
+	for _, opts := range [][]SolverOption{nil, {WithPresolve(false)}, {WithAlgorithm(AlgorithmDual), WithPresolve(false)}} {
+		sol, err := Solve(&prog, opts...)
+		assert.Nil(t, err)
+		assert.Equal(t, sol.Status, common.SolverStatusInfeasible)
+		assert.True(t, sol.UnboundedRay == nil)
+
+		// Both rows are <= as written, so the ray is non-positive, and with
+		// x, y >= 0 it needs y·rhs > 0 >= y^T A x
+		ray := sol.FarkasRay
+		assert.IsClose(t, ray.AtVec(0), -1, 1e-9)
+		assert.IsClose(t, ray.AtVec(1), -1, 1e-9)
+		assert.True(t, ray.AtVec(0)*1+ray.AtVec(1)*-3 > 0)
+	}
+}
+
+func TestSolve_UnboundedRay(t *testing.T) {
+	// Maximize: x + y
+	// Subject to: x - y <= 1
+	x := lp.NewVariable("x")
+	y := lp.NewVariable("y")
+	prog := lp.NewLinearProgram("Unbounded", []lp.LpVariable{x, y})
+	prog.AddObjective(lp.LpMaximise, lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x), lp.NewTerm(1, y)}))
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x), lp.NewTerm(-1, y)}), lp.LpConstraintLE, 1)
+
+	for _, opts := range [][]SolverOption{nil, {WithPresolve(false)}} {
+		sol, err := Solve(&prog, opts...)
+		assert.Nil(t, err)
+		assert.Equal(t, sol.Status, common.SolverStatusUnbounded)
+		assert.True(t, sol.FarkasRay == nil)
+
+		// Moving along d keeps x - y <= 1 and x, y >= 0 and raises x + y
+		d := sol.UnboundedRay
+		assert.Equal(t, d.Len(), 2)
+		assert.True(t, d.AtVec(0) >= 0 && d.AtVec(1) >= 0)
+		assert.True(t, d.AtVec(0)-d.AtVec(1) <= 1e-9)
+		assert.True(t, d.AtVec(0)+d.AtVec(1) > 0)
+	}
+}
+
 func TestSolve_ContextCancel(t *testing.T) {
 	objective := mat.NewVecDense(1, []float64{1})
 	constraints := mat.NewDense(1, 1, []float64{1})
@@ -268,3 +324,769 @@ func TestSolve_ContextCancel(t *testing.T) {
 		t.Error("Expected nil solution on context cancellation")
 	}
 }
+
+// Test lower, upper and free variable bounds
+func TestSolve_VariableBounds(t *testing.T) {
+	// Minimize: x + y - z
+	// Subject to: y >= -7, x + z >= -20
+	// Bounds: -5 <= x <= 10, y free, z <= 3
+
+	x := lp.NewVariable("x", lp.WithBounds(-5, 10))
+	y := lp.NewVariable("y", lp.WithBounds(math.Inf(-1), math.Inf(1)))
+	z := lp.NewVariable("z", lp.WithBounds(math.Inf(-1), 3))
+
+	prog := lp.NewLinearProgram("Bounds", []lp.LpVariable{x, y, z})
+	prog.AddObjective(lp.LpMinimise, lp.NewExpression([]lp.LpTerm{
+		lp.NewTerm(1, x), lp.NewTerm(1, y), lp.NewTerm(-1, z),
+	}))
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, y)}), lp.LpConstraintGE, -7)
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x), lp.NewTerm(1, z)}), lp.LpConstraintGE, -20)
+
+	sol, err := Solve(&prog)
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, sol.ObjectiveValue, -15, 1e-9)
+	assert.IsClose(t, sol.PrimalSolution.AtVec(0), -5, 1e-9)
+	assert.IsClose(t, sol.PrimalSolution.AtVec(1), -7, 1e-9)
+	assert.IsClose(t, sol.PrimalSolution.AtVec(2), 3, 1e-9)
+}
+
+func TestSolve_IntegerUpperBound(t *testing.T) {
+	// Maximize x with x integer and x <= 7.5 as a variable bound
+
+	x := lp.NewVariable("x", lp.LpCategoryInteger, lp.WithUpperBound(7.5))
+	prog := lp.NewLinearProgram("Integer Bound", []lp.LpVariable{x})
+	prog.AddObjective(lp.LpMaximise, lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x)}))
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x)}), lp.LpConstraintLE, 100)
+
+	sol, err := Solve(&prog)
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, common.SolverStatusOptimal)
+	assert.Equal(t, sol.ObjectiveValue, 7.0)
+	assert.Equal(t, sol.PrimalSolution.AtVec(0), 7.0)
+}
+
+func TestSolve_InconsistentBounds(t *testing.T) {
+	x := lp.NewVariable("x", lp.WithBounds(5, 3))
+	prog := lp.NewLinearProgram("Inconsistent", []lp.LpVariable{x})
+	prog.AddObjective(lp.LpMinimise, lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x)}))
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x)}), lp.LpConstraintLE, 10)
+
+	sol, err := Solve(&prog)
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, common.SolverStatusInfeasible)
+}
+
+// wyndor returns the program
+//
+//	maximise 3x1 + 5x2 subject to x1 <= 4, 2x2 <= 12, 3x1 + 2x2 <= 18,
+//
+// whose optimum of 36 is at (2, 6), with row duals (0, 1.5, 1).
+func wyndor() lp.LinearProgram {
+	x1 := lp.NewVariable("x1")
+	x2 := lp.NewVariable("x2")
+	prog := lp.NewLinearProgram("Wyndor", []lp.LpVariable{x1, x2})
+	prog.AddObjective(lp.LpMaximise, lp.NewExpression([]lp.LpTerm{lp.NewTerm(3, x1), lp.NewTerm(5, x2)}))
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x1)}), lp.LpConstraintLE, 4)
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(2, x2)}), lp.LpConstraintLE, 12)
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(3, x1), lp.NewTerm(2, x2)}), lp.LpConstraintLE, 18)
+	return prog
+}
+
+func TestSolve_DualsAndSlacks(t *testing.T) {
+	prog := wyndor()
+
+	sol, err := Solve(&prog)
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, sol.ObjectiveValue, 36, 1e-9)
+
+	duals := []float64{0, 1.5, 1}
+	activity := []float64{2, 12, 18}
+	slack := []float64{2, 0, 0}
+	for i := range duals {
+		assert.IsClose(t, sol.DualSolution.AtVec(i), duals[i], 1e-9)
+		assert.IsClose(t, sol.RowActivity.AtVec(i), activity[i], 1e-9)
+		assert.IsClose(t, sol.Slack.AtVec(i), slack[i], 1e-9)
+	}
+	assert.Equal(t, sol.ReducedCosts.Len(), 2)
+	assert.IsClose(t, sol.ReducedCosts.AtVec(0), 0, 1e-9)
+	assert.IsClose(t, sol.ReducedCosts.AtVec(1), 0, 1e-9)
+}
+
+func TestSolve_DualsFlippedRow(t *testing.T) {
+	// Minimize: x + 2y
+	// Subject to: -x - y <= -3 (stored as x + y >= 3)
+
+	x := lp.NewVariable("x")
+	y := lp.NewVariable("y")
+	prog := lp.NewLinearProgram("Flipped", []lp.LpVariable{x, y})
+	prog.AddObjective(lp.LpMinimise, lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x), lp.NewTerm(2, y)}))
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(-1, x), lp.NewTerm(-1, y)}), lp.LpConstraintLE, -3)
+
+	sol, err := Solve(&prog)
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, sol.ObjectiveValue, 3, 1e-9)
+	assert.IsClose(t, sol.DualSolution.AtVec(0), -1, 1e-9)
+	assert.IsClose(t, sol.RowActivity.AtVec(0), -3, 1e-9)
+	assert.IsClose(t, sol.Slack.AtVec(0), 0, 1e-9)
+	assert.IsClose(t, sol.ReducedCosts.AtVec(0), 0, 1e-9)
+	assert.IsClose(t, sol.ReducedCosts.AtVec(1), 1, 1e-9)
+}
+
+func TestSolve_IntegerHasNoDuals(t *testing.T) {
+	x := lp.NewVariable("x", lp.LpCategoryInteger)
+	prog := lp.NewLinearProgram("Integer", []lp.LpVariable{x})
+	prog.AddObjective(lp.LpMaximise, lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x)}))
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(2, x)}), lp.LpConstraintLE, 7)
+
+	sol, err := Solve(&prog)
+	assert.Nil(t, err)
+	assert.True(t, sol.DualSolution == nil)
+	assert.IsClose(t, sol.RowActivity.AtVec(0), 6, 1e-9)
+	assert.IsClose(t, sol.Slack.AtVec(0), 1, 1e-9)
+}
+
+func TestSolve_Sensitivity(t *testing.T) {
+	prog := wyndor()
+
+	sol, err := Solve(&prog)
+	assert.Nil(t, err)
+	assert.True(t, sol.Sensitivity == nil)
+
+	sol, err = Solve(&prog, WithSensitivity(true))
+	assert.Nil(t, err)
+	s := sol.Sensitivity
+
+	// c1 in [0, 7.5], c2 in [2, +Inf)
+	assert.IsClose(t, s.ObjectiveDecrease.AtVec(0), 3, 1e-9)
+	assert.IsClose(t, s.ObjectiveIncrease.AtVec(0), 4.5, 1e-9)
+	assert.IsClose(t, s.ObjectiveDecrease.AtVec(1), 3, 1e-9)
+	assert.True(t, math.IsInf(s.ObjectiveIncrease.AtVec(1), 1))
+
+	// b1 in [2, +Inf), b2 in [6, 18], b3 in [12, 24]
+	assert.IsClose(t, s.RHSDecrease.AtVec(0), 2, 1e-9)
+	assert.True(t, math.IsInf(s.RHSIncrease.AtVec(0), 1))
+	assert.IsClose(t, s.RHSDecrease.AtVec(1), 6, 1e-9)
+	assert.IsClose(t, s.RHSIncrease.AtVec(1), 6, 1e-9)
+	assert.IsClose(t, s.RHSDecrease.AtVec(2), 6, 1e-9)
+	assert.IsClose(t, s.RHSIncrease.AtVec(2), 6, 1e-9)
+}
+
+func TestSolve_SensitivityFlippedRow(t *testing.T) {
+	// Minimize: x + 2y
+	// Subject to: -x - y <= -3
+
+	x := lp.NewVariable("x")
+	y := lp.NewVariable("y")
+	prog := lp.NewLinearProgram("Flipped", []lp.LpVariable{x, y})
+	prog.AddObjective(lp.LpMinimise, lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x), lp.NewTerm(2, y)}))
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(-1, x), lp.NewTerm(-1, y)}), lp.LpConstraintLE, -3)
+
+	sol, err := Solve(&prog, WithSensitivity(true))
+	assert.Nil(t, err)
+	s := sol.Sensitivity
+
+	// The RHS may rise to 0 but fall without limit
+	assert.True(t, math.IsInf(s.RHSDecrease.AtVec(0), 1))
+	assert.IsClose(t, s.RHSIncrease.AtVec(0), 3, 1e-9)
+	assert.IsClose(t, s.ObjectiveDecrease.AtVec(0), 1, 1e-9)
+	assert.IsClose(t, s.ObjectiveIncrease.AtVec(0), 1, 1e-9)
+	assert.IsClose(t, s.ObjectiveDecrease.AtVec(1), 1, 1e-9)
+	assert.True(t, math.IsInf(s.ObjectiveIncrease.AtVec(1), 1))
+}
+
+func TestSolve_DualAlgorithm(t *testing.T) {
+	prog := wyndor()
+
+	sol, err := Solve(&prog, WithAlgorithm(AlgorithmDual))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, common.SolverStatusOptimal)
+	assert.IsClose(t, sol.ObjectiveValue, 36, 1e-9)
+	assert.IsClose(t, sol.PrimalSolution.AtVec(0), 2, 1e-9)
+	assert.IsClose(t, sol.PrimalSolution.AtVec(1), 6, 1e-9)
+	assert.IsClose(t, sol.DualSolution.AtVec(1), 1.5, 1e-9)
+}
+
+func TestSolve_BarrierAlgorithm(t *testing.T) {
+	prog := wyndor()
+
+	sol, err := Solve(&prog, WithAlgorithm(AlgorithmBarrier))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusOptimal)
+	assert.IsClose(t, sol.ObjectiveValue, 36, 1e-6)
+	assert.IsClose(t, sol.PrimalSolution.AtVec(0), 2, 1e-6)
+	assert.IsClose(t, sol.PrimalSolution.AtVec(1), 6, 1e-6)
+	assert.IsClose(t, sol.DualSolution.AtVec(1), 1.5, 1e-6)
+	assert.IsClose(t, sol.DualSolution.AtVec(2), 1, 1e-6)
+
+	// The barrier stalls on an infeasible problem and the simplex method
+	// classifies it
+	y1 := lp.NewVariable("y1")
+	y2 := lp.NewVariable("y2")
+	infeasible := lp.NewLinearProgram("Barrier Infeasible", []lp.LpVariable{y1, y2})
+	infeasible.AddObjective(lp.LpMinimise, lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, y1), lp.NewTerm(1, y2)}))
+	infeasible.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, y1), lp.NewTerm(1, y2)}), lp.LpConstraintLE, 1)
+	infeasible.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, y1), lp.NewTerm(1, y2)}), lp.LpConstraintGE, 3)
+
+	sol, err = Solve(&infeasible, WithAlgorithm(AlgorithmBarrier), WithPresolve(false))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusInfeasible)
+}
+
+func TestSolve_BarrierCrossover(t *testing.T) {
+	// Maximize: x1 + x2
+	// Subject to: x1 + x2 <= 4, x1 <= 3
+	// The barrier ends inside the optimal edge; crossover picks a vertex of it
+
+	x1 := lp.NewVariable("x1")
+	x2 := lp.NewVariable("x2")
+	prog := lp.NewLinearProgram("Crossover", []lp.LpVariable{x1, x2})
+	prog.AddObjective(lp.LpMaximise, lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x1), lp.NewTerm(1, x2)}))
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x1), lp.NewTerm(1, x2)}), lp.LpConstraintLE, 4)
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x1)}), lp.LpConstraintLE, 3)
+
+	sol, err := Solve(&prog, WithAlgorithm(AlgorithmBarrier), WithCrossover(true), WithSensitivity(true))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusOptimal)
+	assert.IsClose(t, sol.ObjectiveValue, 4, 1e-9)
+	x := sol.PrimalSolution.AtVec(0)
+	assert.True(t, x == 0 || math.Abs(x-3) < 1e-9)
+	assert.NotNil(t, sol.Sensitivity)
+}
+
+func TestSolve_StartPoint(t *testing.T) {
+	prog := wyndor()
+
+	// Crossover from a point strictly inside the feasible region
+	sol, err := Solve(&prog, WithStartPoint(mat.NewVecDense(2, []float64{1, 1})))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusOptimal)
+	assert.IsClose(t, sol.ObjectiveValue, 36, 1e-9)
+	assert.IsClose(t, sol.PrimalSolution.AtVec(0), 2, 1e-9)
+	assert.IsClose(t, sol.PrimalSolution.AtVec(1), 6, 1e-9)
+	assert.IsClose(t, sol.DualSolution.AtVec(2), 1, 1e-9)
+	assert.NotNil(t, sol.Basis)
+
+	// An infeasible point is repaired by the simplex method
+	sol, err = Solve(&prog, WithStartPoint(mat.NewVecDense(2, []float64{5, 7})))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusOptimal)
+	assert.IsClose(t, sol.ObjectiveValue, 36, 1e-9)
+
+	_, err = Solve(&prog, WithStartPoint(mat.NewVecDense(3, nil)))
+	assert.NotNil(t, err)
+}
+
+func TestSolve_PDLPAlgorithm(t *testing.T) {
+	prog := wyndor()
+
+	// The default iteration limit leaves PDHG room to converge
+	sol, err := Solve(&prog, WithAlgorithm(AlgorithmPDLP))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusOptimal)
+	assert.IsClose(t, sol.ObjectiveValue, 36, 1e-4)
+	assert.IsClose(t, sol.PrimalSolution.AtVec(0), 2, 1e-4)
+	assert.IsClose(t, sol.PrimalSolution.AtVec(1), 6, 1e-4)
+
+	// Crossover turns the approximate solution into the exact vertex
+	sol, err = Solve(&prog, WithAlgorithm(AlgorithmPDLP), WithCrossover(true))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusOptimal)
+	assert.IsClose(t, sol.ObjectiveValue, 36, 1e-9)
+	assert.IsClose(t, sol.DualSolution.AtVec(1), 1.5, 1e-9)
+}
+
+func TestSolve_PDLPInfeasibleWithRay(t *testing.T) {
+	// Minimize: 3x1 - x2 - 5x3 - 3x4
+	// Subject to: -3x1 - x2 - x4 = -1, x1 - 2x4 = 2, -x1 + 3x2 = 5
+	// x3 improves the objective without limit, but no point is feasible
+	x := []lp.LpVariable{lp.NewVariable("x1"), lp.NewVariable("x2"), lp.NewVariable("x3"), lp.NewVariable("x4")}
+	prog := lp.NewLinearProgram("PDLP Infeasible", x)
+	prog.AddObjective(lp.LpMinimise, lp.NewExpression([]lp.LpTerm{
+		lp.NewTerm(3, x[0]), lp.NewTerm(-1, x[1]), lp.NewTerm(-5, x[2]), lp.NewTerm(-3, x[3]),
+	}))
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(-3, x[0]), lp.NewTerm(-1, x[1]), lp.NewTerm(-1, x[3])}), lp.LpConstraintEQ, -1)
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x[0]), lp.NewTerm(-2, x[3])}), lp.LpConstraintEQ, 2)
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(-1, x[0]), lp.NewTerm(3, x[1])}), lp.LpConstraintEQ, 5)
+
+	sol, err := Solve(&prog, WithAlgorithm(AlgorithmPDLP), WithPresolve(false))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusInfeasible)
+	assert.True(t, sol.UnboundedRay == nil)
+}
+
+func TestSolve_Exact(t *testing.T) {
+	// Maximize: 0.3x + 0.2y
+	// Subject to: -0.1x - 0.1y >= -0.7 (stored as 0.1x + 0.1y <= 0.7), x <= 3
+
+	x := lp.NewVariable("x")
+	y := lp.NewVariable("y")
+	prog := lp.NewLinearProgram("Exact", []lp.LpVariable{x, y})
+	prog.AddObjective(lp.LpMaximise, lp.NewExpression([]lp.LpTerm{lp.NewTerm(0.3, x), lp.NewTerm(0.2, y)}))
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(-0.1, x), lp.NewTerm(-0.1, y)}), lp.LpConstraintGE, -0.7)
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x)}), lp.LpConstraintLE, 3)
+
+	sol, err := Solve(&prog, WithExact(true))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusOptimal)
+	assert.NotNil(t, sol.Exact)
+
+	want := func(got *big.Rat, s string) {
+		t.Helper()
+		r, _ := new(big.Rat).SetString(s)
+		assert.True(t, got.Cmp(r) == 0)
+	}
+	want(sol.Exact.ObjectiveValue, "17/10")
+	want(sol.Exact.PrimalSolution[0], "3")
+	want(sol.Exact.PrimalSolution[1], "4")
+	want(sol.Exact.DualSolution[0], "-2")
+	want(sol.Exact.DualSolution[1], "1/10")
+	want(sol.Exact.ReducedCosts[1], "0")
+
+	// The float64 fields are the values nearest the exact ones
+	assert.Equal(t, sol.ObjectiveValue, 1.7)
+	assert.Equal(t, sol.DualSolution.AtVec(1), 0.1)
+
+	// Without the option there is no exact solution
+	sol, err = Solve(&prog)
+	assert.Nil(t, err)
+	assert.True(t, sol.Exact == nil)
+}
+
+func TestSolve_WithoutPresolve(t *testing.T) {
+	// Minimize: x1 + 2x2
+	// Subject to: x1 = 2, x1 + x2 >= 5
+
+	build := func() lp.LinearProgram {
+		x1 := lp.NewVariable("x1")
+		x2 := lp.NewVariable("x2")
+		prog := lp.NewLinearProgram("Presolve", []lp.LpVariable{x1, x2})
+		prog.AddObjective(lp.LpMinimise, lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x1), lp.NewTerm(2, x2)}))
+		prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x1)}), lp.LpConstraintEQ, 2)
+		prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x1), lp.NewTerm(1, x2)}), lp.LpConstraintGE, 5)
+		return prog
+	}
+
+	on := build()
+	withPresolve, err := Solve(&on)
+	assert.Nil(t, err)
+	off := build()
+	withoutPresolve, err := Solve(&off, WithPresolve(false))
+	assert.Nil(t, err)
+
+	for _, sol := range []*Solution{withPresolve, withoutPresolve} {
+		assert.Equal(t, sol.Status, common.SolverStatusOptimal)
+		assert.IsClose(t, sol.ObjectiveValue, 8, 1e-9)
+		assert.IsClose(t, sol.PrimalSolution.AtVec(0), 2, 1e-9)
+		assert.IsClose(t, sol.PrimalSolution.AtVec(1), 3, 1e-9)
+		assert.IsClose(t, sol.DualSolution.AtVec(0), -1, 1e-9)
+		assert.IsClose(t, sol.DualSolution.AtVec(1), 2, 1e-9)
+	}
+}
+
+func TestSolve_Scaling(t *testing.T) {
+	// Minimize: 2e-4 x1 + 3e4 x2
+	// Subject to: 1e-1 x1 + 1e7 x2 >= 4e3, 1e-4 x1 + 3e4 x2 >= 6
+
+	for _, s := range []Scaling{ScalingNone, ScalingGeometric, ScalingEquilibration} {
+		x1 := lp.NewVariable("x1")
+		x2 := lp.NewVariable("x2")
+		prog := lp.NewLinearProgram("Scaling", []lp.LpVariable{x1, x2})
+		prog.AddObjective(lp.LpMinimise, lp.NewExpression([]lp.LpTerm{lp.NewTerm(2e-4, x1), lp.NewTerm(3e4, x2)}))
+		prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1e-1, x1), lp.NewTerm(1e7, x2)}), lp.LpConstraintGE, 4e3)
+		prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1e-4, x1), lp.NewTerm(3e4, x2)}), lp.LpConstraintGE, 6)
+
+		sol, err := Solve(&prog, WithScaling(s))
+		assert.Nil(t, err)
+		assert.Equal(t, sol.Status, common.SolverStatusOptimal)
+		assert.IsClose(t, sol.ObjectiveValue, 9, 1e-9)
+		assert.IsClose(t, sol.PrimalSolution.AtVec(0), 3e4, 1e-6)
+		assert.IsClose(t, sol.PrimalSolution.AtVec(1), 1e-4, 1e-12)
+		assert.IsClose(t, sol.DualSolution.AtVec(0), 1.5e-3, 1e-12)
+		assert.IsClose(t, sol.DualSolution.AtVec(1), 0.5, 1e-9)
+	}
+}
+
+func TestSolve_PricingRules(t *testing.T) {
+	// Transportation problem: 3 supplies of 20, 30, 25 and 3 demands of 10,
+	// 35, 30 with costs c_ij = |i - j| + 1
+	supply := []float64{20, 30, 25}
+	demand := []float64{10, 35, 30}
+
+	for _, p := range []Pricing{PricingDantzig, PricingSteepestEdge, PricingDevex, PricingPartial} {
+		vars := make([]lp.LpVariable, 9)
+		obj := []lp.LpTerm{}
+		for i := range 3 {
+			for j := range 3 {
+				vars[3*i+j] = lp.NewVariable(fmt.Sprintf("x%d%d", i, j))
+				cost := math.Abs(float64(i-j)) + 1
+				obj = append(obj, lp.NewTerm(cost, vars[3*i+j]))
+			}
+		}
+		prog := lp.NewLinearProgram("Transport", vars)
+		prog.AddObjective(lp.LpMinimise, lp.NewExpression(obj))
+		for i := range 3 {
+			row := []lp.LpTerm{}
+			for j := range 3 {
+				row = append(row, lp.NewTerm(1, vars[3*i+j]))
+			}
+			prog.AddConstraint(lp.NewExpression(row), lp.LpConstraintLE, supply[i])
+		}
+		for j := range 3 {
+			col := []lp.LpTerm{}
+			for i := range 3 {
+				col = append(col, lp.NewTerm(1, vars[3*i+j]))
+			}
+			prog.AddConstraint(lp.NewExpression(col), lp.LpConstraintGE, demand[j])
+		}
+
+		sol, err := Solve(&prog, WithPricing(p))
+		assert.Nil(t, err)
+		assert.Equal(t, sol.Status, common.SolverStatusOptimal)
+		assert.IsClose(t, sol.ObjectiveValue, 90, 1e-9)
+	}
+}
+
+func TestSolve_IterationLimit(t *testing.T) {
+	prog := wyndor()
+
+	sol, err := Solve(&prog, WithMaxIterations(1))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusIterationLimit)
+	assert.NotNil(t, sol.PrimalSolution)
+	assert.True(t, sol.DualSolution == nil)
+
+	sol, err = Solve(&prog, WithMaxIterations(100))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusOptimal)
+	assert.IsClose(t, sol.ObjectiveValue, 36, 1e-9)
+}
+
+func TestSolve_TimeLimit(t *testing.T) {
+	prog := wyndor()
+
+	sol, err := Solve(&prog, WithTimeLimit(time.Nanosecond))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusTimeLimit)
+	assert.NotNil(t, sol.PrimalSolution)
+
+	sol, err = Solve(&prog, WithTimeLimit(time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusOptimal)
+	assert.IsClose(t, sol.ObjectiveValue, 36, 1e-9)
+}
+
+func TestSolve_IntegerTimeLimit(t *testing.T) {
+	x := lp.NewVariable("x", lp.LpCategoryInteger, lp.WithUpperBound(7.5))
+	prog := lp.NewLinearProgram("Integer Time Limit", []lp.LpVariable{x})
+	prog.AddObjective(lp.LpMaximise, lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x)}))
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x)}), lp.LpConstraintLE, 100)
+
+	sol, err := Solve(&prog, WithTimeLimit(time.Nanosecond))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusTimeLimit)
+	assert.True(t, math.IsInf(sol.Stats.Gap, 1))
+}
+
+func TestSolve_Basis(t *testing.T) {
+	// Maximize: 3x + 2y + z
+	// Subject to: x + y <= 4, x + 3y <= 9, z - y = 0, x <= 3
+	x := lp.NewVariable("x", lp.WithUpperBound(3))
+	y := lp.NewVariable("y")
+	z := lp.NewVariable("z")
+	prog := lp.NewLinearProgram("Basis", []lp.LpVariable{x, y, z})
+	prog.AddObjective(lp.LpMaximise, lp.NewExpression([]lp.LpTerm{lp.NewTerm(3, x), lp.NewTerm(2, y), lp.NewTerm(1, z)}))
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x), lp.NewTerm(1, y)}), lp.LpConstraintLE, 4)
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x), lp.NewTerm(3, y)}), lp.LpConstraintLE, 9)
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, z), lp.NewTerm(-1, y)}), lp.LpConstraintEQ, 0)
+
+	sol, err := Solve(&prog)
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusOptimal)
+	assert.IsClose(t, sol.ObjectiveValue, 12, 1e-9)
+	assert.True(t, slices.Equal(sol.Basis.Variables, []BasisStatus{BasisAtUpper, BasisBasic, BasisBasic}))
+	assert.True(t, slices.Equal(sol.Basis.Constraints, []BasisStatus{BasisAtLower, BasisBasic, BasisAtLower}))
+}
+
+func TestSolve_WarmStart(t *testing.T) {
+	prog := wyndor()
+	sol, err := Solve(&prog)
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusOptimal)
+
+	// The optimal basis needs no pivots, where a cold start needs several
+	sol, err = Solve(&prog, WithWarmStart(sol.Basis), WithMaxIterations(1))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusOptimal)
+	assert.IsClose(t, sol.ObjectiveValue, 36, 1e-9)
+
+	// After the last right-hand side rises to 21 the basis is re-optimised
+	for _, algorithm := range []Algorithm{AlgorithmPrimal, AlgorithmDual} {
+		next := wyndor()
+		next.RHS.SetVec(2, 21)
+		got, err := Solve(&next, WithWarmStart(sol.Basis), WithAlgorithm(algorithm))
+		assert.Nil(t, err)
+		assert.Equal(t, got.Status, SolverStatusOptimal)
+		assert.IsClose(t, got.ObjectiveValue, 39, 1e-9)
+	}
+
+	// A basis of another program is rejected
+	_, err = Solve(&prog, WithWarmStart(&Basis{Variables: []BasisStatus{BasisBasic}}))
+	assert.NotNil(t, err)
+}
+
+func TestSolve_Stats(t *testing.T) {
+	prog := wyndor()
+
+	sol, err := Solve(&prog, WithPresolve(false))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusOptimal)
+	assert.True(t, sol.Stats.Phase2Iterations >= 2)
+	assert.True(t, sol.Stats.Refactorisations >= 1)
+	assert.True(t, sol.Stats.TotalTime >= sol.Stats.Phase1Time+sol.Stats.Phase2Time)
+	assert.Equal(t, sol.Stats.Nodes, 0)
+
+	sol, err = Solve(&prog, WithAlgorithm(AlgorithmBarrier))
+	assert.Nil(t, err)
+	assert.True(t, sol.Stats.InteriorIterations > 0)
+}
+
+func TestSolve_IntegerStats(t *testing.T) {
+	// Maximize: x + y
+	// Subject to: 2x + 2y <= 7, x and y integer
+	x := lp.NewVariable("x", lp.LpCategoryInteger)
+	y := lp.NewVariable("y", lp.LpCategoryInteger)
+	prog := lp.NewLinearProgram("Integer Stats", []lp.LpVariable{x, y})
+	prog.AddObjective(lp.LpMaximise, lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x), lp.NewTerm(1, y)}))
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(2, x), lp.NewTerm(2, y)}), lp.LpConstraintLE, 7)
+
+	sol, err := Solve(&prog)
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusOptimal)
+	assert.IsClose(t, sol.ObjectiveValue, 3, 1e-9)
+
+	// The root relaxation is fractional, so the search branches at least once
+	st := sol.Stats
+	assert.True(t, st.Nodes >= 3)
+	assert.True(t, st.MaxDepth >= 1)
+	assert.True(t, st.NodesPruned <= st.Nodes)
+	assert.Equal(t, st.Gap, 0.)
+	assert.True(t, st.Phase1Iterations+st.Phase2Iterations+st.DualIterations > 0)
+}
+
+func TestSolve_Callback(t *testing.T) {
+	prog := wyndor()
+
+	// Phase 2 objectives are reported as the maximisation they belong to
+	var last Event
+	pivots := 0
+	sol, err := Solve(&prog, WithPresolve(false), WithCallback(func(e Event) error {
+		if e.Kind == EventIteration {
+			pivots++
+			last = e
+		}
+		return nil
+	}, 1))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusOptimal)
+	assert.Equal(t, pivots, sol.Stats.Phase1Iterations+sol.Stats.Phase2Iterations)
+	assert.Equal(t, last.Phase, PhaseTwo)
+	assert.True(t, last.Objective > 0 && last.Objective <= 36)
+
+	// An error from the callback stops the solve
+	sol, err = Solve(&prog, WithPresolve(false), WithCallback(func(e Event) error {
+		if e.Kind == EventIteration {
+			return fmt.Errorf("stop")
+		}
+		return nil
+	}, 1))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusCancelled)
+	assert.NotNil(t, sol.PrimalSolution)
+}
+
+// integerProgram returns the pure integer program
+//
+//	maximise 5x + 4y + 3z subject to 2x + 3y + z <= 5.5, 4x + y + 2z <= 11.3,
+//	3x + 4y + 2z <= 8.7,
+//
+// whose optimum is 13.
+func integerProgram() lp.LinearProgram {
+	x := lp.NewVariable("x", lp.LpCategoryInteger)
+	y := lp.NewVariable("y", lp.LpCategoryInteger)
+	z := lp.NewVariable("z", lp.LpCategoryInteger)
+	prog := lp.NewLinearProgram("Integer", []lp.LpVariable{x, y, z})
+	prog.AddObjective(lp.LpMaximise, lp.NewExpression([]lp.LpTerm{lp.NewTerm(5, x), lp.NewTerm(4, y), lp.NewTerm(3, z)}))
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(2, x), lp.NewTerm(3, y), lp.NewTerm(1, z)}), lp.LpConstraintLE, 5.5)
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(4, x), lp.NewTerm(1, y), lp.NewTerm(2, z)}), lp.LpConstraintLE, 11.3)
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(3, x), lp.NewTerm(4, y), lp.NewTerm(2, z)}), lp.LpConstraintLE, 8.7)
+	return prog
+}
+
+func TestSolve_CallbackIncumbent(t *testing.T) {
+	prog := integerProgram()
+
+	nodes, best := 0, math.Inf(-1)
+	sol, err := Solve(&prog, WithCallback(func(e Event) error {
+		switch e.Kind {
+		case EventNode:
+			nodes++
+			assert.Equal(t, e.Phase, PhaseBranch)
+		case EventIncumbent:
+			assert.True(t, e.Incumbent > best)
+			best = e.Incumbent
+		}
+		return nil
+	}, 0))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusOptimal)
+	assert.Equal(t, nodes, sol.Stats.Nodes)
+	assert.Equal(t, best, sol.ObjectiveValue)
+	assert.IsClose(t, sol.ObjectiveValue, 13, 1e-9)
+
+	// Stop at the first incumbent that is good enough
+	sol, err = Solve(&prog, WithCallback(func(e Event) error {
+		if e.Kind == EventIncumbent && e.Incumbent >= 10 {
+			return fmt.Errorf("good enough")
+		}
+		return nil
+	}, 0))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusCancelled)
+	assert.True(t, sol.ObjectiveValue >= 10)
+	assert.True(t, sol.Stats.Nodes < nodes)
+}
+
+// logRecords solves prog with a JSON logger at level and returns its records.
+func logRecords(t *testing.T, prog *lp.LinearProgram, level slog.Level, opts ...SolverOption) (*Solution, []map[string]any) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: level}))
+	sol, err := Solve(prog, append(opts, WithLogger(logger))...)
+	assert.Nil(t, err)
+
+	var records []map[string]any
+	for line := range bytes.Lines(buf.Bytes()) {
+		var r map[string]any
+		assert.Nil(t, json.Unmarshal(line, &r))
+		records = append(records, r)
+	}
+	return sol, records
+}
+
+func TestSolve_Logger(t *testing.T) {
+	prog := wyndor()
+
+	// At info level only the start and outcome of the solve are recorded
+	sol, records := logRecords(t, &prog, slog.LevelInfo, WithPresolve(false))
+	assert.Equal(t, sol.Status, SolverStatusOptimal)
+	assert.Equal(t, len(records), 2)
+	assert.Equal(t, records[0]["msg"], "solve started")
+	assert.Equal(t, records[0]["rows"].(float64), 3)
+	assert.Equal(t, records[1]["msg"], "solve finished")
+	assert.Equal(t, records[1]["status"].(string), SolverStatusOptimal.String())
+	assert.IsClose(t, records[1]["objective"].(float64), 36, 1e-9)
+
+	// At debug level every pivot is recorded as well
+	sol, records = logRecords(t, &prog, slog.LevelDebug, WithPresolve(false))
+	pivots := 0
+	for _, r := range records {
+		if r["msg"] == "simplex iteration" {
+			pivots++
+		}
+	}
+	assert.Equal(t, pivots, sol.Stats.Phase1Iterations+sol.Stats.Phase2Iterations)
+}
+
+func TestSolve_LoggerInteger(t *testing.T) {
+	prog := integerProgram()
+
+	// Every node is recorded with its depth, and incumbents in the sense of
+	// the program
+	sol, records := logRecords(t, &prog, slog.LevelDebug)
+	assert.Equal(t, sol.Status, SolverStatusOptimal)
+	nodes, best := 0, math.Inf(-1)
+	for _, r := range records {
+		switch r["msg"] {
+		case "node solved":
+			nodes++
+			assert.True(t, r["depth"].(float64) <= float64(sol.Stats.MaxDepth))
+		case "new incumbent":
+			assert.Equal(t, r["level"], "INFO")
+			assert.True(t, r["objective"].(float64) > best)
+			best = r["objective"].(float64)
+		}
+	}
+	assert.Equal(t, nodes, sol.Stats.Nodes)
+	assert.IsClose(t, best, 13, 1e-9)
+}
+
+// knapsack returns a 0-1 knapsack program and its optimum by enumeration.
+func knapsack() (lp.LinearProgram, float64) {
+	values := []float64{10, 13, 7, 8, 9, 12, 4, 11, 6, 5}
+	weights := []float64{5, 7, 4, 5, 6, 8, 3, 7, 4, 3}
+	capacity := 25.
+
+	vars := make([]lp.LpVariable, len(values))
+	obj := make([]lp.LpTerm, len(values))
+	con := make([]lp.LpTerm, len(values))
+	for i := range values {
+		vars[i] = lp.NewVariable(fmt.Sprintf("x%d", i), lp.LpCategoryInteger, lp.WithUpperBound(1))
+		obj[i] = lp.NewTerm(values[i], vars[i])
+		con[i] = lp.NewTerm(weights[i], vars[i])
+	}
+	prog := lp.NewLinearProgram("Knapsack", vars)
+	prog.AddObjective(lp.LpMaximise, lp.NewExpression(obj))
+	prog.AddConstraint(lp.NewExpression(con), lp.LpConstraintLE, capacity)
+
+	best := 0.
+	for mask := range 1 << len(values) {
+		value, weight := 0., 0.
+		for i := range values {
+			if mask&(1<<i) != 0 {
+				value += values[i]
+				weight += weights[i]
+			}
+		}
+		if weight <= capacity {
+			best = math.Max(best, value)
+		}
+	}
+	return prog, best
+}
+
+func TestSolve_NodeSelection(t *testing.T) {
+	prog, best := knapsack()
+	for _, sel := range []NodeSelection{NodeBestFirst, NodeDepthFirst, NodeBestEstimate} {
+		sol, err := Solve(&prog, WithNodeSelection(sel), WithGapSensitivity(0))
+		assert.Nil(t, err)
+		assert.Equal(t, sol.Status, SolverStatusOptimal)
+		assert.IsClose(t, sol.ObjectiveValue, best, 1e-9)
+
+		// Nodes whose bound cannot beat the incumbent are closed unexplored
+		assert.Equal(t, sol.Stats.Gap, 0.)
+		assert.True(t, sol.Stats.NodesPruned > 0)
+		assert.True(t, sol.Stats.Nodes < 1<<11-1)
+	}
+}
+
+func TestSolve_GapSensitivity(t *testing.T) {
+	prog, best := knapsack()
+	exact, err := Solve(&prog, WithGapSensitivity(0))
+	assert.Nil(t, err)
+
+	assert.Equal(t, exact.Status, SolverStatusOptimal)
+	assert.IsClose(t, exact.ObjectiveValue, best, 1e-9)
+
+	// The search stops once the incumbent is within the gap of the bound,
+	// which leaves it unproven
+	gap := 0.2
+	sol, err := Solve(&prog, WithGapSensitivity(gap))
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusGapLimit)
+	assert.True(t, sol.Stats.Gap > 0 && sol.Stats.Gap <= gap)
+	assert.True(t, sol.ObjectiveValue*(1+gap) >= best-1e-9)
+	assert.True(t, sol.ObjectiveValue <= best+1e-9)
+	assert.True(t, sol.Stats.Nodes <= exact.Stats.Nodes)
+}
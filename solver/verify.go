@@ -0,0 +1,188 @@
+package solver
+
+import (
+	"math"
+
+	"github.com/chriso345/gspl/internal/errors"
+	"github.com/chriso345/gspl/internal/matrix"
+	"github.com/chriso345/gspl/lp"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Residual is the largest violation of one group of optimality conditions.
+// Max is the absolute violation and Relative the largest violation divided by
+// one plus the magnitude of the data it is measured against.
+type Residual struct {
+	Max      float64
+	Relative float64
+}
+
+// add records violation v measured against data of magnitude scale.
+func (r *Residual) add(v, scale float64) {
+	r.Max = math.Max(r.Max, v)
+	r.Relative = math.Max(r.Relative, v/(1+math.Abs(scale)))
+}
+
+// Verification reports how far a Solution is from satisfying the optimality
+// conditions of its program, measured on the constraints as they were written
+// and in the sense of the original objective.
+//
+// The dual conditions are only checked when the Solution holds duals, which
+// Duals reports. Dual holds both the residual of c - A^T y = d and any duals
+// or reduced costs of the wrong sign for their row or bounds.
+type Verification struct {
+	Primal        Residual // Constraint violations, a_i·x against rhs_i
+	Bounds        Residual // Variable bound violations
+	Integrality   Residual // Distance of integer variables from the nearest integer
+	Dual          Residual // Dual feasibility
+	Complementary Residual // Slack times dual and bound gap times reduced cost
+	Objective     Residual // Reported objective value against c^T x
+
+	Duals bool
+}
+
+// Within reports whether every relative residual is at most tol.
+func (v *Verification) Within(tol float64) bool {
+	for _, r := range []Residual{v.Primal, v.Bounds, v.Integrality, v.Dual, v.Complementary, v.Objective} {
+		if r.Relative > tol {
+			return false
+		}
+	}
+	return true
+}
+
+// Verify recomputes the primal feasibility, dual feasibility and
+// complementary slackness of sol as a solution of prog, independently of the
+// solver. Solve reports values below the tolerance as zero and rounds integer
+// solutions, and Verify shows whether the reported point still satisfies the
+// program.
+func Verify(prog *lp.LinearProgram, sol *Solution) (*Verification, error) {
+	if prog == nil || sol == nil || sol.PrimalSolution == nil {
+		return nil, errors.New(errors.ErrInvalidInput, "verify needs a program and a solution", nil)
+	}
+	x := sol.PrimalSolution
+	n := x.Len()
+	if prog.Objective == nil || n > len(prog.Vars) {
+		return nil, errors.New(errors.ErrInvalidInput, "solution does not match the program", nil)
+	}
+	m := 0
+	if prog.Constraints != nil {
+		m, _ = prog.Constraints.Dims()
+	}
+	if sol.DualSolution != nil && (sol.DualSolution.Len() != m || sol.ReducedCosts == nil || sol.ReducedCosts.Len() != n) {
+		return nil, errors.New(errors.ErrInvalidInput, "duals do not match the program", nil)
+	}
+
+	// Objective coefficients and row types as the user wrote them
+	c := make([]float64, n)
+	for j := range c {
+		c[j] = prog.Objective.AtVec(j)
+		if prog.ObjectiveIsNegated {
+			c[j] = -c[j]
+		}
+	}
+	conType := func(i int) lp.LpConstraintType {
+		t := prog.ConTypes[i]
+		if conFlipped(prog, i) {
+			switch t {
+			case lp.LpConstraintLE:
+				t = lp.LpConstraintGE
+			case lp.LpConstraintGE:
+				t = lp.LpConstraintLE
+			}
+		}
+		return t
+	}
+
+	v := &Verification{Duals: sol.DualSolution != nil}
+
+	obj := 0.
+	for j := range n {
+		obj += c[j] * x.AtVec(j)
+	}
+	v.Objective.add(math.Abs(obj-sol.ObjectiveValue), obj)
+
+	activity, slack := rowActivity(prog, x)
+	for i := range m {
+		rhs := activity.AtVec(i) + slack.AtVec(i)
+		s := slack.AtVec(i)
+		switch conType(i) {
+		case lp.LpConstraintLE:
+			v.Primal.add(math.Max(0, -s), rhs)
+		case lp.LpConstraintGE:
+			v.Primal.add(math.Max(0, s), rhs)
+		default:
+			v.Primal.add(math.Abs(s), rhs)
+		}
+	}
+
+	for j := range n {
+		xj := x.AtVec(j)
+		lower, upper := variableBounds(prog.Vars[j])
+		v.Bounds.add(math.Max(0, lower-xj), lower)
+		v.Bounds.add(math.Max(0, xj-upper), upper)
+		if prog.Vars[j].Category != lp.LpCategoryContinuous {
+			d := math.Abs(xj - math.Round(xj))
+			v.Integrality.add(d, 0)
+		}
+	}
+
+	if !v.Duals {
+		return v, nil
+	}
+
+	// The sign conditions below are those of a minimisation
+	sense := 1.
+	if prog.Sense == lp.LpMaximise {
+		sense = -1
+	}
+	y, d := sol.DualSolution, sol.ReducedCosts
+
+	// A <= row has a non-positive dual and a >= row a non-negative one, and
+	// a dual may only be nonzero on a binding row
+	for i := range m {
+		yi := sense * y.AtVec(i)
+		switch conType(i) {
+		case lp.LpConstraintLE:
+			v.Dual.add(math.Max(0, yi), 0)
+		case lp.LpConstraintGE:
+			v.Dual.add(math.Max(0, -yi), 0)
+		}
+		v.Complementary.add(math.Abs(yi*slack.AtVec(i)), obj)
+	}
+
+	// c - A^T y = d on the rows as written, where d is non-negative at a
+	// lower bound and non-positive at an upper bound
+	stored := mat.NewVecDense(max(m, 1), nil)
+	for i := range m {
+		yi := y.AtVec(i)
+		if conFlipped(prog, i) {
+			yi = -yi
+		}
+		stored.SetVec(i, yi)
+	}
+	for j := range n {
+		aty := 0.
+		if m > 0 {
+			aty = matrix.ColDot(prog.Constraints, j, stored)
+		}
+		dj := d.AtVec(j)
+		v.Dual.add(math.Abs(c[j]-aty-dj), c[j])
+
+		dj *= sense
+		xj := x.AtVec(j)
+		lower, upper := variableBounds(prog.Vars[j])
+		switch {
+		case dj > 0 && math.IsInf(lower, -1):
+			v.Dual.add(dj, 0)
+		case dj > 0:
+			v.Complementary.add(dj*math.Abs(xj-lower), obj)
+		case dj < 0 && math.IsInf(upper, 1):
+			v.Dual.add(-dj, 0)
+		case dj < 0:
+			v.Complementary.add(-dj*math.Abs(upper-xj), obj)
+		}
+	}
+
+	return v, nil
+}
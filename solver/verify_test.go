@@ -0,0 +1,93 @@
+package solver
+
+import (
+	"testing"
+
+	"github.com/chriso345/gore/assert"
+	"github.com/chriso345/gspl/lp"
+	"gonum.org/v1/gonum/mat"
+)
+
+func verifyProgram() lp.LinearProgram {
+	// Maximize: 3x + 2y
+	// Subject to: x + y <= 4, -x - 3y <= -3 (stored as x + 3y >= 3), x <= 3
+	x := lp.NewVariable("x")
+	y := lp.NewVariable("y")
+	prog := lp.NewLinearProgram("Verify", []lp.LpVariable{x, y})
+	prog.AddObjective(lp.LpMaximise, lp.NewExpression([]lp.LpTerm{lp.NewTerm(3, x), lp.NewTerm(2, y)}))
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x), lp.NewTerm(1, y)}), lp.LpConstraintLE, 4)
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(-1, x), lp.NewTerm(-3, y)}), lp.LpConstraintLE, -3)
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x)}), lp.LpConstraintLE, 3)
+	return prog
+}
+
+func TestVerify(t *testing.T) {
+	prog := verifyProgram()
+	sol, err := Solve(&prog)
+	assert.Nil(t, err)
+	assert.Equal(t, sol.Status, SolverStatusOptimal)
+
+	v, err := Verify(&prog, sol)
+	assert.Nil(t, err)
+	assert.True(t, v.Duals)
+	assert.True(t, v.Within(1e-9))
+
+	// Moving x off the optimum breaks the first and last rows and
+	// complementary slackness, and the objective no longer matches
+	sol.PrimalSolution.SetVec(0, 3.5)
+	v, err = Verify(&prog, sol)
+	assert.Nil(t, err)
+	assert.IsClose(t, v.Primal.Max, 0.5, 1e-12)
+	assert.IsClose(t, v.Primal.Relative, 0.125, 1e-12) // 0.5 against x <= 3
+	assert.Equal(t, v.Bounds.Max, 0.)
+	assert.IsClose(t, v.Objective.Max, 1.5, 1e-12)
+	assert.True(t, v.Complementary.Max > 0)
+	assert.False(t, v.Within(1e-6))
+
+	// A negative dual on a binding <= row of a maximisation is infeasible
+	sol.PrimalSolution.SetVec(0, 3)
+	sol.DualSolution.SetVec(0, -sol.DualSolution.AtVec(0))
+	v, err = Verify(&prog, sol)
+	assert.Nil(t, err)
+	assert.True(t, v.Dual.Max > 1)
+}
+
+func TestVerifyInteger(t *testing.T) {
+	x := lp.NewVariable("x", lp.LpCategoryInteger)
+	prog := lp.NewLinearProgram("Verify Integer", []lp.LpVariable{x})
+	prog.AddObjective(lp.LpMaximise, lp.NewExpression([]lp.LpTerm{lp.NewTerm(1, x)}))
+	prog.AddConstraint(lp.NewExpression([]lp.LpTerm{lp.NewTerm(2, x)}), lp.LpConstraintLE, 5)
+
+	sol, err := Solve(&prog)
+	assert.Nil(t, err)
+	v, err := Verify(&prog, sol)
+	assert.Nil(t, err)
+	assert.False(t, v.Duals)
+	assert.True(t, v.Within(1e-9))
+
+	sol.PrimalSolution.SetVec(0, 2.4)
+	sol.ObjectiveValue = 2.4
+	v, err = Verify(&prog, sol)
+	assert.Nil(t, err)
+	assert.IsClose(t, v.Integrality.Max, 0.4, 1e-12)
+	assert.Equal(t, v.Primal.Max, 0.)
+
+	sol.PrimalSolution.SetVec(0, -1)
+	sol.ObjectiveValue = -1
+	v, err = Verify(&prog, sol)
+	assert.Nil(t, err)
+	assert.Equal(t, v.Bounds.Max, 1.)
+	assert.Equal(t, v.Integrality.Max, 0.)
+}
+
+func TestVerifyInvalid(t *testing.T) {
+	prog := verifyProgram()
+	_, err := Verify(&prog, nil)
+	assert.NotNil(t, err)
+
+	sol, err := Solve(&prog)
+	assert.Nil(t, err)
+	sol.DualSolution = sol.DualSolution.SliceVec(0, 1).(*mat.VecDense)
+	_, err = Verify(&prog, sol)
+	assert.NotNil(t, err)
+}
@@ -158,5 +158,6 @@ func Test_Minimisation2(t *testing.T) {
 
 	assert.Nil(t, err)
 	assert.Equal(t, sol.Status.String(), lp.LpStatusOptimal.String())
-	assert.IsClose(t, sol.ObjectiveValue, 10.0, 1e-5)
+	// The LP relaxation is 12.5; the best integer point is [1 1 2 1 0]
+	assert.IsClose(t, sol.ObjectiveValue, 13.0, 1e-5)
 }